@@ -0,0 +1,65 @@
+package ply
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// Notations for float values in ASCII output.
+const (
+	// ShortestNotation writes the shortest text that reads back exactly.
+	ShortestNotation = iota
+	// FixedNotation writes a fixed number of decimals, like %.6f.
+	FixedNotation
+	// ScientificNotation writes an exponent, like %.6e.
+	ScientificNotation
+)
+
+// ASCIIOptions controls the text of ASCII output; binary output ignores
+// it.
+type ASCIIOptions struct {
+	// Notation of float values. With ShortestNotation, properties listed
+	// in Precision use FixedNotation.
+	Notation int
+	// Precision is the number of decimals of float properties, keyed by
+	// property name or by "element.property", which takes precedence.
+	// Properties without one use the shortest exact representation in
+	// fixed and scientific notation too.
+	Precision map[string]int
+	// ListSeparator separates list counts and items, a space by default.
+	// It must be whitespace for the output to be readable.
+	ListSeparator string
+}
+
+// format formats a value of prop, applying the float options.
+func (opts *ASCIIOptions) format(b []byte, elem *Element, prop *Property, order binary.ByteOrder) string {
+	if opts == nil || !isFloat(prop.Type) || codecs[prop.Type] != nil {
+		return formatValue(b, prop.Type, order)
+	}
+	digits, ok := opts.Precision[elem.Name+"."+prop.Name]
+	if !ok {
+		digits, ok = opts.Precision[prop.Name]
+	}
+	if !ok {
+		digits = -1
+	}
+	notation := byte('f')
+	switch {
+	case opts.Notation == ScientificNotation:
+		notation = 'e'
+	case opts.Notation == ShortestNotation && !ok:
+		return formatValue(b, prop.Type, order)
+	}
+	bits := 64
+	if SizeOfType[prop.Type] == 4 {
+		bits = 32
+	}
+	return strconv.FormatFloat(scalarFloat64(b, prop.Type, order), notation, digits, bits)
+}
+
+func (opts *ASCIIOptions) listSeparator() string {
+	if opts == nil || opts.ListSeparator == "" {
+		return " "
+	}
+	return opts.ListSeparator
+}
@@ -0,0 +1,37 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestASCIIOptions(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	write := func(opts *ASCIIOptions) string {
+		var out bytes.Buffer
+		if e := p.WriteWithOptions(&out, &SaveOptions{ASCII: opts}); e != nil {
+			t.Fatal(e)
+		}
+		return out.String()[strings.Index(out.String(), "end_header\n")+11:]
+	}
+	body := write(&ASCIIOptions{
+		Precision:     map[string]int{"x": 2, "vertex.quality": 3},
+		ListSeparator: "\t",
+	})
+	want := "0.00 0 0 0.500\n1.00 0 0 0.250\n1.00 1 0 -0.000\n0.00 1 0 3.000\n" +
+		"3\t0\t1\t2 -7\n4\t0\t1\t2\t3 300\n"
+	if body != want {
+		t.Errorf("unexpected fixed output\n%s", body)
+	}
+	body = write(&ASCIIOptions{Notation: ScientificNotation, Precision: map[string]int{"z": 1}})
+	if !strings.HasPrefix(body, "0e+00 0e+00 0.0e+00 5e-01\n") || !strings.Contains(body, "-1e-05") {
+		t.Errorf("unexpected scientific output\n%s", body)
+	}
+	if write(nil) != write(&ASCIIOptions{}) {
+		t.Error("expected empty options to match the default output")
+	}
+}
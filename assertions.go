@@ -0,0 +1,133 @@
+package ply
+
+import "strings"
+
+// AssertionFailure describes one violated expectation. Element and
+// Property are empty for whole-file rules.
+type AssertionFailure struct {
+	Element  string `json:"element,omitempty"`
+	Property string `json:"property,omitempty"`
+	// Rule is "element", "min_rows", "max_rows", "property" or
+	// "max_total_rows".
+	Rule     string `json:"rule"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// AssertionError lists every failure found by Assertions.Check.
+type AssertionError struct {
+	Failures []AssertionFailure
+}
+
+func (e *AssertionError) Error() string {
+	msgs := make([]string, len(e.Failures))
+	for k, f := range e.Failures {
+		subject := f.Element
+		if f.Property != "" {
+			subject += "." + f.Property
+		}
+		if subject != "" {
+			subject += ": "
+		}
+		msgs[k] = subject + f.Rule + " expected " + f.Expected + ", got " + f.Actual
+	}
+	return "Assertions failed: " + strings.Join(msgs, "; ")
+}
+
+// Assertions are expectations about a file's layout, checked against the
+// header before the body is decoded when set in LoadOptions:
+//
+//	a := NewAssertions()
+//	a.ExpectElement("vertex").MinRows(1).RequireProps("x", "y", "z")
+//	e := p.LoadWithOptions(name, &LoadOptions{Assertions: a})
+type Assertions struct {
+	elements      []*ElementAssertion
+	maxTotalRows  int
+	checkMaxTotal bool
+}
+
+// ElementAssertion holds the expectations about one element.
+type ElementAssertion struct {
+	name     string
+	minRows  int
+	maxRows  int
+	checkMax bool
+	props    []string
+}
+
+func NewAssertions() *Assertions {
+	return &Assertions{}
+}
+
+// ExpectElement requires the named element and returns its expectations.
+func (a *Assertions) ExpectElement(name string) *ElementAssertion {
+	for _, ea := range a.elements {
+		if ea.name == name {
+			return ea
+		}
+	}
+	ea := &ElementAssertion{name: name}
+	a.elements = append(a.elements, ea)
+	return ea
+}
+
+// MaxTotalRows bounds the rows of all elements together.
+func (a *Assertions) MaxTotalRows(n int) *Assertions {
+	a.maxTotalRows, a.checkMaxTotal = n, true
+	return a
+}
+
+func (ea *ElementAssertion) MinRows(n int) *ElementAssertion {
+	ea.minRows = n
+	return ea
+}
+
+func (ea *ElementAssertion) MaxRows(n int) *ElementAssertion {
+	ea.maxRows, ea.checkMax = n, true
+	return ea
+}
+
+// RequireProps requires the named properties.
+func (ea *ElementAssertion) RequireProps(names ...string) *ElementAssertion {
+	ea.props = append(ea.props, names...)
+	return ea
+}
+
+// Check returns an *AssertionError listing every expectation p violates.
+func (a *Assertions) Check(p *PLY) error {
+	var failures []AssertionFailure
+	total := 0
+	for _, elem := range p.Elements {
+		total += elem.Size
+	}
+	if a.checkMaxTotal && total > a.maxTotalRows {
+		failures = append(failures, AssertionFailure{Rule: "max_total_rows",
+			Expected: "<= " + itoa(a.maxTotalRows), Actual: itoa(total)})
+	}
+	for _, ea := range a.elements {
+		elem := p.findElement(ea.name)
+		if elem == nil {
+			failures = append(failures, AssertionFailure{Element: ea.name, Rule: "element",
+				Expected: "present", Actual: "missing"})
+			continue
+		}
+		if elem.Size < ea.minRows {
+			failures = append(failures, AssertionFailure{Element: ea.name, Rule: "min_rows",
+				Expected: ">= " + itoa(ea.minRows), Actual: itoa(elem.Size)})
+		}
+		if ea.checkMax && elem.Size > ea.maxRows {
+			failures = append(failures, AssertionFailure{Element: ea.name, Rule: "max_rows",
+				Expected: "<= " + itoa(ea.maxRows), Actual: itoa(elem.Size)})
+		}
+		for _, name := range ea.props {
+			if elem.findProperty(name) == nil {
+				failures = append(failures, AssertionFailure{Element: ea.name, Property: name,
+					Rule: "property", Expected: "present", Actual: "missing"})
+			}
+		}
+	}
+	if failures != nil {
+		return &AssertionError{Failures: failures}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package ply
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAssertions(t *testing.T) {
+	a := NewAssertions()
+	a.ExpectElement("vertex").MinRows(1).RequireProps("x", "y", "z")
+	a.ExpectElement("face").MaxRows(2)
+	p := new(PLY)
+	if e := p.ReadWithOptions(strings.NewReader(testASCIIMesh), &LoadOptions{Assertions: a}); e != nil {
+		t.Fatal(e)
+	}
+	a.ExpectElement("vertex").MinRows(5).RequireProps("nx")
+	a.ExpectElement("edge")
+	a.MaxTotalRows(3)
+	// a body that would fail to decode shows the header is checked first
+	src := strings.Replace(testASCIIMesh, "0 1 0 3", "0 1 0 x", 1)
+	e := new(PLY).ReadWithOptions(strings.NewReader(src), &LoadOptions{Assertions: a})
+	ae, ok := e.(*AssertionError)
+	if !ok {
+		t.Fatalf("expected *AssertionError, got %v", e)
+	}
+	rules := make([]string, len(ae.Failures))
+	for k, f := range ae.Failures {
+		rules[k] = f.Rule
+	}
+	if strings.Join(rules, ",") != "max_total_rows,min_rows,property,element" {
+		t.Errorf("unexpected failures %+v", ae.Failures)
+	}
+	if ae.Failures[2].Property != "nx" || !strings.Contains(e.Error(), "vertex.nx: property expected present") {
+		t.Errorf("unexpected message %q", e.Error())
+	}
+	b, _ := json.Marshal(ae.Failures[1])
+	var f map[string]string
+	if json.Unmarshal(b, &f); f["element"] != "vertex" || f["expected"] != ">= 5" || f["actual"] != "4" {
+		t.Errorf("unexpected JSON %s", b)
+	}
+}
@@ -0,0 +1,94 @@
+package ply
+
+import (
+	"errors"
+)
+
+// Positions decodes the x, y and z properties of the vertex element.
+func (p *PLY) Positions() ([][3]float64, error) {
+	return p.vertexPositions()
+}
+
+// Normals decodes the nx, ny and nz properties of the vertex element.
+func (p *PLY) Normals() ([][3]float64, error) {
+	return p.vertexVec3("nx", "ny", "nz")
+}
+
+// SetPositions stores pos into the x, y and z properties, converting to
+// their declared types. Missing properties, and the vertex element if
+// needed, are created as float.
+func (p *PLY) SetPositions(pos [][3]float64) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	return p.setVertexVec3(pos, "x", "y", "z")
+}
+
+// SetNormals stores n into the nx, ny and nz properties like SetPositions.
+func (p *PLY) SetNormals(n [][3]float64) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	return p.setVertexVec3(n, "nx", "ny", "nz")
+}
+
+func (p *PLY) vertexVec3(names ...string) ([][3]float64, error) {
+	elem := p.findElement("vertex")
+	if elem == nil {
+		return nil, errors.New("No vertex element")
+	}
+	props := elem.scalarProperties(names...)
+	if props == nil {
+		return nil, errors.New("Vertex element has no scalar " + names[0] + ", " + names[1] + ", " + names[2] + " properties")
+	}
+	values := make([][3]float64, elem.Size)
+	for i := range values {
+		for j := 0; j < 3; j++ {
+			values[i][j] = props[j].float64At(i)
+		}
+	}
+	return values, nil
+}
+
+func (p *PLY) setVertexVec3(values [][3]float64, names ...string) error {
+	elem := p.findElement("vertex")
+	if elem == nil {
+		elem = &Element{Name: "vertex", Size: len(values)}
+		p.Elements = append([]*Element{elem}, p.Elements...)
+	}
+	if len(values) != elem.Size {
+		return errors.New("Got " + itoa(len(values)) + " values for " + itoa(elem.Size) + " vertices")
+	}
+	props := make([]*Property, 3)
+	for j, name := range names {
+		prop, e := elem.ensureProperty(name, "float")
+		if e != nil {
+			return e
+		}
+		props[j] = prop
+	}
+	for j := range props {
+		for i, v := range values {
+			props[j].setFloat64At(i, v[j])
+		}
+	}
+	return nil
+}
+
+// ensureProperty returns the named scalar property, appending it with
+// typeName if missing, with Data sized to the element.
+func (e *Element) ensureProperty(name, typeName string) (*Property, error) {
+	prop := e.findProperty(name)
+	if prop == nil {
+		prop = newProperty(name, typeName, e.Size)
+		prop.pos = len(e.Properties)
+		e.Properties = append(e.Properties, prop)
+	}
+	if prop.IsList {
+		return nil, errors.New("Property " + name + " is a list")
+	}
+	if len(prop.Data) < e.Size {
+		prop.Data = append(prop.Data, make([][]byte, e.Size-len(prop.Data))...)
+	}
+	return prop, nil
+}
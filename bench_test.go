@@ -0,0 +1,155 @@
+package ply
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// benchMesh returns an ASCII mesh with n colored vertices and n-2 faces.
+func benchMesh(n int) string {
+	var src strings.Builder
+	src.WriteString("ply\nformat ascii 1.0\nelement vertex " + itoa(n) +
+		"\nproperty float x\nproperty float y\nproperty float z\n" +
+		"property uchar red\nproperty uchar green\nproperty uchar blue\n" +
+		"element face " + itoa(n-2) + "\nproperty list uchar int vertex_indices\nend_header\n")
+	for i := 0; i < n; i++ {
+		f := strconv.FormatFloat(float64(i)/7, 'f', 3, 64)
+		c := itoa(i % 256)
+		src.WriteString(f + " " + f + " " + f + " " + c + " " + c + " " + c + "\n")
+	}
+	for i := 0; i < n-2; i++ {
+		src.WriteString("3 " + itoa(i) + " " + itoa(i+1) + " " + itoa(i+2) + "\n")
+	}
+	return src.String()
+}
+
+func benchBinary(b testing.TB, n int) []byte {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(benchMesh(n))); e != nil {
+		b.Fatal(e)
+	}
+	p.FileType = BinaryLittleEndian
+	var buf bytes.Buffer
+	if e := p.Write(&buf); e != nil {
+		b.Fatal(e)
+	}
+	return buf.Bytes()
+}
+
+func TestColumnChunks(t *testing.T) {
+	// enough rows for several chunks per property
+	const n = 40000
+	src := benchMesh(n)
+	a := new(PLY)
+	if e := a.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	b := new(PLY)
+	if e := b.Read(bytes.NewReader(benchBinary(t, n))); e != nil {
+		t.Fatal(e)
+	}
+	if a.ContentHash() != b.ContentHash() {
+		t.Error("expected the ASCII and binary loads to match")
+	}
+	x := b.Elements[0].findProperty("x")
+	if got := x.float64At(n - 1); float32(got) != float32(strconvRound(float64(n-1)/7)) {
+		t.Errorf("unexpected last x %v", got)
+	}
+	faces := b.ReadFaces()
+	if len(faces) != n-2 || faces[n-3][2] != n-1 {
+		t.Errorf("unexpected last face %v", faces[len(faces)-1])
+	}
+	row := x.row(0)
+	if cap(row) != len(row) {
+		t.Error("expected rows capped at their length")
+	}
+}
+
+// strconvRound rounds v to the 3 decimals benchMesh writes.
+func strconvRound(v float64) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'f', 3, 64), 64)
+	return f
+}
+
+func BenchmarkReadBinary(b *testing.B) {
+	data := benchBinary(b, 100000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if e := new(PLY).Read(bytes.NewReader(data)); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
+
+func BenchmarkReadASCII(b *testing.B) {
+	data := benchMesh(100000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if e := new(PLY).Read(strings.NewReader(data)); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	data := benchBinary(b, 100000)
+	d := NewDecoder(nil, nil)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Reset(bytes.NewReader(data))
+		p := new(PLY)
+		if e := d.Decode(p); e != nil {
+			b.Fatal(e)
+		}
+		p.Close()
+	}
+}
+
+func BenchmarkFloat32s(b *testing.B) {
+	p := new(PLY)
+	if e := p.Read(bytes.NewReader(benchBinary(b, 100000))); e != nil {
+		b.Fatal(e)
+	}
+	vertex := p.findElement("vertex")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vertex.Float32s("x")
+	}
+}
+
+func BenchmarkReadLazyPositions(b *testing.B) {
+	data := benchBinary(b, 100000)
+	opts := &LoadOptions{Lazy: true}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := new(PLY)
+		if e := p.ReadWithOptions(bytes.NewReader(data), opts); e != nil {
+			b.Fatal(e)
+		}
+		p.ReadVertices()
+	}
+}
+
+func BenchmarkReadSelected(b *testing.B) {
+	data := benchBinary(b, 100000)
+	opts := &LoadOptions{Properties: []string{"vertex.x", "vertex.y", "vertex.z"}}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if e := new(PLY).ReadWithOptions(bytes.NewReader(data), opts); e != nil {
+			b.Fatal(e)
+		}
+	}
+}
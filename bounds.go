@@ -0,0 +1,37 @@
+package ply
+
+// BoundingBox returns the axis-aligned bounds of the vertex positions,
+// ignoring non-finite coordinates. Both corners are zero when there is no
+// valid vertex.
+func (p *PLY) BoundingBox() (min, max [3]float64) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return
+	}
+	if b := positionBounds(pos); b != nil {
+		return b.Min, b.Max
+	}
+	return
+}
+
+// Centroid returns the mean of the finite vertex positions, or zero when
+// there is none.
+func (p *PLY) Centroid() [3]float64 {
+	var c [3]float64
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return c
+	}
+	n := 0
+	for _, v := range pos {
+		if isInvalidPoint(v) {
+			continue
+		}
+		c = add3(c, v)
+		n++
+	}
+	if n > 0 {
+		c = scale3(c, 1/float64(n))
+	}
+	return c
+}
@@ -0,0 +1,26 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBoundingBoxCentroid(t *testing.T) {
+	src := strings.Replace(testASCIIMesh, "property float y", "property short y", 1)
+	src = strings.Replace(src, "0 1 0 3", "nan 1 0 3", 1)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	min, max := p.BoundingBox()
+	if min != [3]float64{0, 0, 0} || max != [3]float64{1, 1, 0} {
+		t.Errorf("unexpected bounds %v %v", min, max)
+	}
+	if c := p.Centroid(); c != [3]float64{2.0 / 3, 1.0 / 3, 0} {
+		t.Errorf("unexpected centroid %v", c)
+	}
+	empty := new(PLY)
+	if min, max := empty.BoundingBox(); min != max || empty.Centroid() != [3]float64{} {
+		t.Error("expected zero results without vertices")
+	}
+}
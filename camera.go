@@ -0,0 +1,55 @@
+package ply
+
+import "errors"
+
+// Camera is a row of the camera element as written by MeshLab and VCG
+// tools. Missing properties are zero.
+type Camera struct {
+	// Position is the viewpoint, view_px, view_py and view_pz.
+	Position [3]float64
+	// Axes holds the camera x, y and z axes in world coordinates, from
+	// x_axisx through z_axisz.
+	Axes [3][3]float64
+	// Focal is the focal length, Scale the pixel size and Center the
+	// principal point in pixels.
+	Focal  float64
+	Scale  [2]float64
+	Center [2]float64
+	// Viewport is the image width and height in pixels.
+	Viewport [2]int
+	// Distortion holds the radial distortion coefficients k1 to k4.
+	Distortion [4]float64
+}
+
+// ReadCameras decodes the camera element.
+func (p *PLY) ReadCameras() ([]Camera, error) {
+	elem := p.findElement("camera")
+	if elem == nil {
+		return nil, errors.New("No camera element")
+	}
+	value := func(name string, i int) float64 {
+		if prop := elem.findProperty(name); prop != nil && !prop.IsList {
+			return prop.float64At(i)
+		}
+		return 0
+	}
+	axes := []string{"x", "y", "z"}
+	cameras := make([]Camera, elem.Size)
+	for i := range cameras {
+		c := &cameras[i]
+		for j, a := range axes {
+			c.Position[j] = value("view_p"+a, i)
+			for k, b := range axes {
+				c.Axes[j][k] = value(a+"_axis"+b, i)
+			}
+		}
+		c.Focal = value("focal", i)
+		c.Scale = [2]float64{value("scalex", i), value("scaley", i)}
+		c.Center = [2]float64{value("centerx", i), value("centery", i)}
+		c.Viewport = [2]int{int(value("viewportx", i)), int(value("viewporty", i))}
+		for k := range c.Distortion {
+			c.Distortion[k] = value("k"+itoa(k+1), i)
+		}
+	}
+	return cameras, nil
+}
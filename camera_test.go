@@ -0,0 +1,53 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCameras(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element camera 1
+property float view_px
+property float view_py
+property float view_pz
+property float x_axisx
+property float x_axisy
+property float x_axisz
+property float y_axisx
+property float y_axisy
+property float y_axisz
+property float z_axisx
+property float z_axisy
+property float z_axisz
+property float focal
+property float scalex
+property float scaley
+property float centerx
+property float centery
+property int viewportx
+property int viewporty
+property float k1
+property float k2
+end_header
+1 2 3 1 0 0 0 1 0 0 0 1 35 0.01 0.01 320 240 640 480 0.5 -0.25
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	cameras, e := p.ReadCameras()
+	if e != nil {
+		t.Fatal(e)
+	}
+	c := cameras[0]
+	if len(cameras) != 1 || c.Position != [3]float64{1, 2, 3} || c.Axes[2] != [3]float64{0, 0, 1} ||
+		c.Focal != 35 || c.Center != [2]float64{320, 240} || c.Viewport != [2]int{640, 480} ||
+		c.Distortion != [4]float64{0.5, -0.25, 0, 0} {
+		t.Errorf("unexpected camera %+v", c)
+	}
+	if _, e := new(PLY).ReadCameras(); e == nil {
+		t.Error("expected an error without a camera element")
+	}
+}
@@ -0,0 +1,70 @@
+package ply
+
+import "sort"
+
+// Canonicalize orders the vertex properties as in Properties, x y z nx ny
+// nz red green blue alpha, followed by the others in their current order,
+// and moves the vertex and face elements to the front, so that files
+// written from different sources have the same header.
+func (p *PLY) Canonicalize() {
+	if p.frozen {
+		panic(ErrFrozen)
+	}
+	p.Elements = canonicalElements(p.Elements)
+	if vertex := p.findElement("vertex"); vertex != nil {
+		sortCanonical(vertex.Properties)
+	}
+}
+
+// canonical returns a canonicalized copy of p sharing the data of p.
+func (p *PLY) canonical() *PLY {
+	q := p.shallowCopy()
+	q.Elements = canonicalElements(p.Elements)
+	for k, elem := range q.Elements {
+		if elem.Name != "vertex" {
+			continue
+		}
+		c := *elem
+		c.Properties = make([]*Property, len(elem.Properties))
+		for j, prop := range elem.Properties {
+			cp := *prop
+			c.Properties[j] = &cp
+		}
+		sortCanonical(c.Properties)
+		q.Elements[k] = &c
+	}
+	return q
+}
+
+// canonicalElements returns elems with vertex first and face second,
+// the others following in their order.
+func canonicalElements(elems []*Element) []*Element {
+	sorted := make([]*Element, len(elems))
+	copy(sorted, elems)
+	rank := func(e *Element) int {
+		switch e.Name {
+		case "vertex":
+			return 0
+		case "face":
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(sorted, func(a, b int) bool { return rank(sorted[a]) < rank(sorted[b]) })
+	return sorted
+}
+
+func sortCanonical(props []*Property) {
+	rank := func(prop *Property) int {
+		for k, name := range Properties {
+			if prop.Name == name {
+				return k
+			}
+		}
+		return len(Properties)
+	}
+	sort.SliceStable(props, func(a, b int) bool { return rank(props[a]) < rank(props[b]) })
+	for j, prop := range props {
+		prop.pos = j
+	}
+}
@@ -0,0 +1,76 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element face 1
+property list uchar int vertex_indices
+element vertex 2
+property float quality
+property uchar red
+property float x
+property float z
+property float y
+element edge 1
+property int vertex1
+property int vertex2
+end_header
+3 0 1 2
+0.5 255 1 3 2
+0.25 0 4 6 5
+7 0
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	var out bytes.Buffer
+	if e := p.WriteWithOptions(&out, &SaveOptions{Canonical: true}); e != nil {
+		t.Fatal(e)
+	}
+	want := `ply
+format ascii 1.0
+element vertex 2
+property float x
+property float y
+property float z
+property uchar red
+property float quality
+element face 1
+property list uchar int vertex_indices
+element edge 1
+property int vertex1
+property int vertex2
+end_header
+1 2 3 255 0.5
+4 5 6 0 0.25
+3 0 1 2
+7 0
+`
+	if out.String() != want {
+		t.Errorf("unexpected canonical output\n%s", out.String())
+	}
+	if p.Elements[0].Name != "face" || p.findElement("vertex").Properties[0].Name != "quality" {
+		t.Error("expected the canonical option to leave p unchanged")
+	}
+
+	p.Canonicalize()
+	out.Reset()
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	if out.String() != want {
+		t.Errorf("unexpected output after Canonicalize\n%s", out.String())
+	}
+	for k, prop := range p.findElement("vertex").Properties {
+		if prop.pos != k {
+			t.Errorf("property %s at %d has pos %d", prop.Name, k, prop.pos)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ply
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Checksums of the body for SaveOptions.Checksum, written as a
+// "comment checksum <algorithm> <hex digest>" header line.
+const (
+	NoChecksum = iota
+	CRC32Checksum
+	SHA256Checksum
+)
+
+var checksumNames = []string{"", "crc32", "sha256"}
+
+func newChecksum(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "crc32":
+		return crc32.NewIEEE(), nil
+	case "sha256":
+		return sha256.New(), nil
+	}
+	return nil, errors.New("Unknown checksum " + algorithm)
+}
+
+// parseChecksumComment splits a checksum comment into its algorithm and
+// digest.
+func parseChecksumComment(comment string) (algorithm, digest string, ok bool) {
+	words := strings.Fields(comment)
+	if len(words) != 3 || words[0] != "checksum" {
+		return "", "", false
+	}
+	return words[1], words[2], true
+}
+
+// withoutChecksum returns p without checksum comments, which would be
+// stale once p is written again.
+func (p *PLY) withoutChecksum() *PLY {
+	var comments []string
+	for _, c := range p.Comments {
+		if _, _, ok := parseChecksumComment(c); !ok {
+			comments = append(comments, c)
+		}
+	}
+	if len(comments) == len(p.Comments) {
+		return p
+	}
+	q := p.shallowCopy()
+	q.Comments = comments
+	return q
+}
+
+// withChecksum returns a copy of p with a comment holding the checksum of
+// the body p writes.
+func (p *PLY) withChecksum(checksum int, ascii *ASCIIOptions) (*PLY, error) {
+	if checksum <= NoChecksum || checksum >= len(checksumNames) {
+		return nil, errors.New("Unknown checksum " + itoa(checksum))
+	}
+	h, _ := newChecksum(checksumNames[checksum])
+	bw := bufio.NewWriter(h)
+	if e := writeBody(p, bw, ascii); e != nil {
+		return nil, e
+	}
+	bw.Flush()
+	q := p.shallowCopy()
+	q.Comments = append(append([]string(nil), p.Comments...),
+		"checksum "+checksumNames[checksum]+" "+hex.EncodeToString(h.Sum(nil)))
+	return q, nil
+}
+
+// checksumReader makes p.reader hash the body when the header holds a
+// checksum comment. The returned function checks the digest once the
+// body has been read.
+func (p *PLY) checksumReader() (func() error, error) {
+	for _, c := range p.Comments {
+		algorithm, digest, ok := parseChecksumComment(c)
+		if !ok {
+			continue
+		}
+		h, e := newChecksum(algorithm)
+		if e != nil {
+			return nil, e
+		}
+		p.reader = bufio.NewReader(io.TeeReader(p.reader, h))
+		verify := func() error {
+			// trailing bytes are part of what was transferred
+			if _, e := io.Copy(ioutil.Discard, p.reader); e != nil {
+				return e
+			}
+			if hex.EncodeToString(h.Sum(nil)) != strings.ToLower(digest) {
+				return errors.New("Checksum mismatch in " + p.filename)
+			}
+			return nil
+		}
+		return verify, nil
+	}
+	return nil, nil
+}
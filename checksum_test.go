@@ -0,0 +1,51 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	p.FileType = BinaryLittleEndian
+	for _, checksum := range []int{CRC32Checksum, SHA256Checksum} {
+		var out bytes.Buffer
+		if e := p.WriteWithOptions(&out, &SaveOptions{Checksum: checksum}); e != nil {
+			t.Fatal(e)
+		}
+		src := out.Bytes()
+		q := new(PLY)
+		if e := q.ReadWithOptions(bytes.NewReader(src), &LoadOptions{VerifyChecksum: true}); e != nil {
+			t.Fatal(e)
+		}
+		if len(q.Comments) != 2 || !strings.HasPrefix(q.Comments[1], "checksum "+checksumNames[checksum]+" ") {
+			t.Errorf("unexpected comments %q", q.Comments)
+		}
+
+		corrupt := append([]byte(nil), src...)
+		corrupt[bytes.Index(src, []byte("end_header\n"))+13] ^= 0x40
+		if e := new(PLY).ReadWithOptions(bytes.NewReader(corrupt), &LoadOptions{VerifyChecksum: true}); e == nil ||
+			!strings.Contains(e.Error(), "Checksum mismatch") {
+			t.Errorf("expected a checksum mismatch, got %v", e)
+		}
+		if e := new(PLY).Read(bytes.NewReader(corrupt)); e != nil {
+			t.Error("expected reads without verification to succeed", e)
+		}
+
+		// rewriting drops the stale checksum
+		out.Reset()
+		q = new(PLY)
+		q.Read(bytes.NewReader(src))
+		q.Write(&out)
+		if strings.Contains(out.String(), "checksum") {
+			t.Error("expected the checksum comment to be dropped")
+		}
+	}
+	if e := p.WriteWithOptions(new(bytes.Buffer), &SaveOptions{Checksum: 9}); e == nil {
+		t.Error("expected an error for an unknown checksum")
+	}
+}
@@ -0,0 +1,113 @@
+package ply
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const chunkCommentPrefix = "chunk_bounds "
+
+// ChunkBounds is the bounding box of Count vertices starting at Offset.
+type ChunkBounds struct {
+	Offset int `json:"offset"`
+	Count  int `json:"count"`
+	Bounds
+}
+
+func (c ChunkBounds) Intersects(min, max [3]float64) bool {
+	for j := 0; j < 3; j++ {
+		if c.Max[j] < min[j] || c.Min[j] > max[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// ComputeChunkBounds splits the vertex element into chunks of rows
+// vertices and returns the bounds of each. Chunks without a finite
+// position are omitted. The bounds are only selective when the cloud is
+// spatially sorted.
+func (p *PLY) ComputeChunkBounds(rows int) ([]ChunkBounds, error) {
+	if rows <= 0 {
+		return nil, errors.New("Chunk size must be positive")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	var chunks []ChunkBounds
+	for off := 0; off < len(pos); off += rows {
+		end := off + rows
+		if end > len(pos) {
+			end = len(pos)
+		}
+		if b := positionBounds(pos[off:end]); b != nil {
+			chunks = append(chunks, ChunkBounds{off, end - off, *b})
+		}
+	}
+	return chunks, nil
+}
+
+// ChunkBounds parses chunk bounds written by SaveOptions.ChunkRows from
+// the header comments.
+func (p *PLY) ChunkBounds() ([]ChunkBounds, error) {
+	var chunks []ChunkBounds
+	for _, c := range p.Comments {
+		if !strings.HasPrefix(c, chunkCommentPrefix) {
+			continue
+		}
+		words := strings.Fields(c[len(chunkCommentPrefix):])
+		if len(words) != 8 {
+			return nil, errors.New("Malformed chunk comment \"" + c + "\"")
+		}
+		var v [8]float64
+		for k, w := range words {
+			f, e := strconv.ParseFloat(w, 64)
+			if e != nil {
+				return nil, errors.New("Malformed chunk comment \"" + c + "\"")
+			}
+			v[k] = f
+		}
+		chunks = append(chunks, ChunkBounds{int(v[0]), int(v[1]),
+			Bounds{[3]float64{v[2], v[3], v[4]}, [3]float64{v[5], v[6], v[7]}}})
+	}
+	return chunks, nil
+}
+
+// WriteChunkBounds writes the chunk bounds as a JSON sidecar document.
+func (p *PLY) WriteChunkBounds(w io.Writer, rows int) error {
+	chunks, e := p.ComputeChunkBounds(rows)
+	if e != nil {
+		return e
+	}
+	if chunks == nil {
+		chunks = []ChunkBounds{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(chunks)
+}
+
+// withChunkComments returns a shallow copy of p whose comments carry fresh
+// chunk bounds in place of any previous ones.
+func (p *PLY) withChunkComments(rows int) (*PLY, error) {
+	chunks, e := p.ComputeChunkBounds(rows)
+	if e != nil {
+		return nil, e
+	}
+	q := p.shallowCopy()
+	q.Comments = nil
+	for _, c := range p.Comments {
+		if !strings.HasPrefix(c, chunkCommentPrefix) {
+			q.Comments = append(q.Comments, c)
+		}
+	}
+	for _, c := range chunks {
+		q.Comments = append(q.Comments, chunkCommentPrefix+itoa(c.Offset)+" "+itoa(c.Count)+" "+
+			formatVec3(c.Min)+" "+formatVec3(c.Max))
+	}
+	return q, nil
+}
@@ -0,0 +1,52 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestChunkBounds(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := p.WriteWithOptions(&buf, &SaveOptions{ChunkRows: 3}); e != nil {
+		t.Fatal(e)
+	}
+	if len(p.Comments) != 1 {
+		t.Error("source comments modified")
+	}
+	q := new(PLY)
+	if e := q.Read(&buf); e != nil {
+		t.Fatal(e)
+	}
+	chunks, e := q.ChunkBounds()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(chunks) != 2 || chunks[0].Count != 3 || chunks[1].Offset != 3 ||
+		chunks[0].Max != [3]float64{1, 1, 0} || chunks[1].Min != [3]float64{0, 1, 0} {
+		t.Fatalf("unexpected chunks %+v", chunks)
+	}
+	if chunks[1].Intersects([3]float64{0.5, 0, 0}, [3]float64{2, 0.5, 1}) || !chunks[0].Intersects([3]float64{0.5, 0, 0}, [3]float64{2, 0.5, 1}) {
+		t.Error("unexpected intersection result")
+	}
+	buf.Reset()
+	if e := q.WriteWithOptions(&buf, &SaveOptions{ChunkRows: 4}); e != nil {
+		t.Fatal(e)
+	}
+	if strings.Count(buf.String(), "comment chunk_bounds") != 1 {
+		t.Error("stale chunk comments were kept")
+	}
+	buf.Reset()
+	if e := p.WriteChunkBounds(&buf, 2); e != nil {
+		t.Fatal(e)
+	}
+	var side []ChunkBounds
+	if e := json.Unmarshal(buf.Bytes(), &side); e != nil || len(side) != 2 || side[1].Max[1] != 1 {
+		t.Errorf("unexpected sidecar %s", buf.String())
+	}
+}
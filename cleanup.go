@@ -0,0 +1,59 @@
+package ply
+
+import "errors"
+
+// CleanupStats counts the rows removed by Cleanup.
+type CleanupStats struct {
+	Vertices int
+	Faces    int
+}
+
+// Cleanup removes degenerate faces (fewer than three distinct valid
+// corners or zero area) and then every vertex no remaining face
+// references, compacting the face indices. Files without a face element
+// are left untouched, as all their vertices would count as unreferenced.
+func (p *PLY) Cleanup() (CleanupStats, error) {
+	if p.frozen {
+		return CleanupStats{}, ErrFrozen
+	}
+	var stats CleanupStats
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return stats, e
+	}
+	face := p.findElement("face")
+	if face == nil {
+		return stats, nil
+	}
+	idx := p.faceIndexProperty(face)
+	if idx == nil {
+		return stats, errors.New("Face element has no vertex index list")
+	}
+	used := make([]bool, len(pos))
+	var rows []int
+	for i := 0; i < face.Size; i++ {
+		f := idx.listIntsAt(i)
+		if isDegenerateFace(f, pos) {
+			continue
+		}
+		rows = append(rows, i)
+		for _, v := range f {
+			used[v] = true
+		}
+	}
+	stats.Faces = face.Size - len(rows)
+	if stats.Faces > 0 {
+		sub := face.selectRows(rows)
+		face.Properties = sub.Properties
+		face.Size = sub.Size
+	}
+	for _, u := range used {
+		if !u {
+			stats.Vertices++
+		}
+	}
+	if stats.Vertices > 0 {
+		p.Elements = p.filterVertices(func(i int) bool { return used[i] }).Elements
+	}
+	return stats, nil
+}
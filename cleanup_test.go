@@ -0,0 +1,51 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanup(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 6
+property float x
+property float y
+property float z
+element face 4
+property list uchar int vertex_indices
+property uchar flags
+end_header
+9 9 9
+0 0 0
+1 0 0
+0 1 0
+2 0 0
+5 5 5
+3 1 2 3 1
+3 1 2 4 2
+3 3 3 2 3
+2 1 5 4
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	stats, e := p.Cleanup()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if stats.Faces != 3 || stats.Vertices != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	faces := p.ReadFaces()
+	if p.VerticesCount() != 3 || len(faces) != 1 || faces[0][0] != 0 || faces[0][2] != 2 {
+		t.Errorf("unexpected result: %d vertices, faces %v", p.VerticesCount(), faces)
+	}
+	if flags := p.findElement("face").findProperty("flags"); flags.float64At(0) != 1 {
+		t.Error("expected face properties to follow their faces")
+	}
+	if x := p.ReadVertices()[0]; x[0] != 0 || x[1] != 1 {
+		t.Errorf("unexpected vertices %v", x)
+	}
+}
@@ -0,0 +1,40 @@
+package ply
+
+import "errors"
+
+// ErrClosed is returned when writing a PLY after Close.
+var ErrClosed = errors.New("PLY is closed")
+
+// Close releases the data of p: its elements and the reader of the last
+// load. Row buffers of a PLY decoded by a Decoder go back to it for reuse.
+// Elements shared with copies derived from p are left to them, and closing
+// such a copy leaves p intact. Writing p afterwards fails with
+// ErrClosed, and closing it twice returns ErrClosed. Loading into a closed
+// PLY opens it again. Other methods see an empty PLY.
+func (p *PLY) Close() error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if p.closed {
+		return ErrClosed
+	}
+	p.Elements = nil
+	p.reader = nil
+	p.releaseSlabs()
+	p.closed = true
+	return nil
+}
+
+// Closed reports whether p was closed and not loaded again.
+func (p *PLY) Closed() bool {
+	return p.closed
+}
+
+// shallowCopy returns a copy of p sharing its elements. The copy does not
+// own p's reader or decoded row buffers, so that only p releases them.
+func (p *PLY) shallowCopy() *PLY {
+	q := *p
+	q.reader = nil
+	q.decoder, q.slabs, q.slab = nil, nil, nil
+	return &q
+}
@@ -0,0 +1,58 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestClose(t *testing.T) {
+	p := new(PLY)
+	if e := p.ReadWithOptions(strings.NewReader(testASCIIMesh), &LoadOptions{Layout: ColumnMajor}); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.Close(); e != nil {
+		t.Fatal(e)
+	}
+	if !p.Closed() || p.Elements != nil || p.VerticesCount() != 0 {
+		t.Error("expected Close to release the data")
+	}
+	if e := p.Write(new(bytes.Buffer)); e != ErrClosed {
+		t.Errorf("expected ErrClosed writing, got %v", e)
+	}
+	if e := p.Close(); e != ErrClosed {
+		t.Errorf("expected ErrClosed closing twice, got %v", e)
+	}
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if p.Closed() || p.VerticesCount() != 4 {
+		t.Error("expected loading to open p again")
+	}
+	if e := p.Write(new(bytes.Buffer)); e != nil {
+		t.Error(e)
+	}
+}
+
+func TestCloseDerived(t *testing.T) {
+	p := new(PLY)
+	if e := NewDecoder(strings.NewReader(testASCIIMesh), nil).Decode(p); e != nil {
+		t.Fatal(e)
+	}
+	parts := p.SplitByElement()
+	crop, e := p.CropAABB([3]float64{-1, -1, -1}, [3]float64{2, 2, 2})
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, q := range append(parts, crop) {
+		if e := q.Close(); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if p.VerticesCount() != 4 || p.findElement("face").Size != 2 {
+		t.Fatalf("expected closing copies to leave p intact, got %d vertices", p.VerticesCount())
+	}
+	if x := p.findElement("vertex").findProperty("x"); len(x.Data) != 4 || x.float64At(1) != 1 {
+		t.Error("expected p's rows to survive")
+	}
+}
@@ -0,0 +1,126 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Attribute is a per-point scalar channel. Values are held as float64,
+// which represents every PLY scalar type exactly; Type is the PLY type
+// used when converting back.
+type Attribute struct {
+	Name   string
+	Type   string
+	Values []float64
+}
+
+// Cloud is a point cloud with positions and an ordered set of scalar
+// attributes such as intensity, colors or normals.
+type Cloud struct {
+	Positions  []Vec3
+	Attributes []*Attribute
+}
+
+// NewCloudFromPLY decodes the vertex element of p. Every scalar property
+// other than x, y and z becomes an attribute; list properties and other
+// elements are ignored.
+func NewCloudFromPLY(p *PLY) (*Cloud, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	c := &Cloud{Positions: make([]Vec3, len(pos))}
+	for i, v := range pos {
+		c.Positions[i] = v
+	}
+	for _, prop := range p.findElement("vertex").Properties {
+		if prop.IsList || prop.Name == "x" || prop.Name == "y" || prop.Name == "z" {
+			continue
+		}
+		a := &Attribute{Name: prop.Name, Type: prop.Type, Values: make([]float64, len(pos))}
+		for i := range a.Values {
+			a.Values[i] = prop.float64At(i)
+		}
+		c.Attributes = append(c.Attributes, a)
+	}
+	return c, nil
+}
+
+func (c *Cloud) Len() int {
+	return len(c.Positions)
+}
+
+// Attribute returns the named attribute or nil.
+func (c *Cloud) Attribute(name string) *Attribute {
+	for _, a := range c.Attributes {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// AddAttribute appends an attribute, replacing one of the same name.
+func (c *Cloud) AddAttribute(name, typeName string, values []float64) error {
+	if len(values) != len(c.Positions) {
+		return errors.New("Got " + itoa(len(values)) + " values for " + itoa(len(c.Positions)) + " points")
+	}
+	if SizeOfType[typeName] == 0 {
+		return errors.New("Unknown type " + typeName)
+	}
+	if name == "x" || name == "y" || name == "z" || name == "" {
+		return errors.New("Invalid attribute name \"" + name + "\"")
+	}
+	a := &Attribute{Name: name, Type: typeName, Values: values}
+	for k, old := range c.Attributes {
+		if old.Name == name {
+			c.Attributes[k] = a
+			return nil
+		}
+	}
+	c.Attributes = append(c.Attributes, a)
+	return nil
+}
+
+// RemoveAttribute deletes the named attribute and reports whether it
+// existed.
+func (c *Cloud) RemoveAttribute(name string) bool {
+	for k, a := range c.Attributes {
+		if a.Name == name {
+			c.Attributes = append(c.Attributes[:k], c.Attributes[k+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ToPLY builds a binary little endian PLY with float x, y, z followed by
+// the attributes in their declared types. Values outside a type's range
+// are clamped.
+func (c *Cloud) ToPLY() (*PLY, error) {
+	n := len(c.Positions)
+	vertex := &Element{Name: "vertex", Size: n}
+	for j, name := range []string{"x", "y", "z"} {
+		prop := newProperty(name, "float", n)
+		prop.pos = j
+		for i, v := range c.Positions {
+			prop.setFloat64At(i, v[j])
+		}
+		vertex.Properties = append(vertex.Properties, prop)
+	}
+	for _, a := range c.Attributes {
+		if len(a.Values) != n {
+			return nil, errors.New("Attribute " + a.Name + " does not match the number of points")
+		}
+		if SizeOfType[a.Type] == 0 {
+			return nil, errors.New("Unknown type " + a.Type + " of attribute " + a.Name)
+		}
+		prop := newProperty(a.Name, a.Type, n)
+		prop.pos = len(vertex.Properties)
+		for i, v := range a.Values {
+			prop.setFloat64At(i, v)
+		}
+		vertex.Properties = append(vertex.Properties, prop)
+	}
+	return &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian, Elements: []*Element{vertex}}, nil
+}
@@ -0,0 +1,38 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCloud(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIVertices)); e != nil {
+		t.Fatal(e)
+	}
+	c, e := NewCloudFromPLY(p)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if c.Len() != 3 || len(c.Attributes) != 1 || c.Attribute("red").Values[2] != 30 || c.Attribute("red").Type != "uchar" {
+		t.Fatalf("unexpected cloud %+v", c)
+	}
+	if e := c.AddAttribute("confidence", "float", []float64{0.5, 1, 0}); e != nil {
+		t.Fatal(e)
+	}
+	if e := c.AddAttribute("bad", "float", []float64{1}); e == nil {
+		t.Error("expected length error")
+	}
+	if !c.RemoveAttribute("red") || c.RemoveAttribute("red") {
+		t.Error("unexpected RemoveAttribute result")
+	}
+	q, e := c.ToPLY()
+	if e != nil {
+		t.Fatal(e)
+	}
+	v := q.GetVertices()
+	if len(v.Properties) != 4 || v.Properties[3].Name != "confidence" || v.Properties[3].float64At(0) != 0.5 ||
+		v.findProperty("x").float64At(2) != -1.25 {
+		t.Errorf("unexpected PLY %+v", v)
+	}
+}
@@ -0,0 +1,85 @@
+package ply
+
+import (
+	"errors"
+	"sort"
+)
+
+// EuclideanClusterExtraction segments the vertices into clusters whose
+// points are linked by chains of points at most tolerance apart, keeping
+// the clusters of minSize to maxSize points; maxSize 0 means no limit.
+// Clusters hold ascending vertex indices and are ordered by decreasing
+// size, ties by their first vertex. ExtractVertices turns a cluster into
+// a PLY of its own and LabelClusters stores the clustering.
+func (p *PLY) EuclideanClusterExtraction(tolerance float64, minSize, maxSize int) ([][]int, error) {
+	if !(tolerance > 0) {
+		return nil, errors.New("Cluster tolerance must be positive")
+	}
+	if minSize < 0 || maxSize < 0 || maxSize > 0 && maxSize < minSize {
+		return nil, errors.New("Invalid cluster size range")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	tree := NewKDTree(pos)
+	seen := make([]bool, len(pos))
+	var clusters [][]int
+	var queue []int
+	for i, v := range pos {
+		if seen[i] || isInvalidPoint(v) {
+			continue
+		}
+		seen[i] = true
+		queue = append(queue[:0], i)
+		for k := 0; k < len(queue); k++ {
+			tree.within(pos[queue[k]], tolerance, func(j int) {
+				if !seen[j] {
+					seen[j] = true
+					queue = append(queue, j)
+				}
+			})
+		}
+		if len(queue) < minSize || maxSize > 0 && len(queue) > maxSize {
+			continue
+		}
+		cluster := append([]int(nil), queue...)
+		sort.Ints(cluster)
+		clusters = append(clusters, cluster)
+	}
+	sort.SliceStable(clusters, func(a, b int) bool { return len(clusters[a]) > len(clusters[b]) })
+	return clusters, nil
+}
+
+// LabelClusters stores the index of each vertex's cluster in an int
+// property, e.g. "label", created if needed, and -1 for vertices in no
+// cluster.
+func (p *PLY) LabelClusters(clusters [][]int, property string) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return errors.New("No vertex element")
+	}
+	labels := make([]float64, vertex.Size)
+	for i := range labels {
+		labels[i] = -1
+	}
+	for n, cluster := range clusters {
+		for _, i := range cluster {
+			if i < 0 || i >= vertex.Size {
+				return errors.New("Vertex " + itoa(i) + " outside the vertex element")
+			}
+			labels[i] = float64(n)
+		}
+	}
+	prop, e := vertex.ensureProperty(property, "int")
+	if e != nil {
+		return e
+	}
+	for i, v := range labels {
+		prop.setFloat64At(i, v)
+	}
+	return nil
+}
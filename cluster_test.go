@@ -0,0 +1,41 @@
+package ply
+
+import "testing"
+
+func TestEuclideanClusterExtraction(t *testing.T) {
+	var pos [][3]float64
+	// a chain of 10 points, a blob of 4 and an isolated point
+	for i := 0; i < 10; i++ {
+		pos = append(pos, [3]float64{float64(i) * 0.5, 0, 0})
+	}
+	for i := 0; i < 4; i++ {
+		pos = append(pos, [3]float64{20, float64(i) * 0.1, 0})
+	}
+	pos = append(pos, [3]float64{-10, 0, 0})
+	pos[3], pos[12] = pos[12], pos[3]
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	clusters, e := p.EuclideanClusterExtraction(0.6, 2, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(clusters) != 2 || len(clusters[0]) != 10 || len(clusters[1]) != 4 || clusters[1][0] != 3 {
+		t.Fatalf("unexpected clusters %v", clusters)
+	}
+	if clusters, _ = p.EuclideanClusterExtraction(0.6, 2, 5); len(clusters) != 1 || len(clusters[0]) != 4 {
+		t.Errorf("expected only the blob within the size range, got %v", clusters)
+	}
+	clusters, _ = p.EuclideanClusterExtraction(0.6, 1, 0)
+	if e := p.LabelClusters(clusters[:2], "label"); e != nil {
+		t.Fatal(e)
+	}
+	label := p.GetVertices().findProperty("label")
+	if label.Type != "int" || label.float64At(0) != 0 || label.float64At(3) != 1 || label.float64At(14) != -1 {
+		t.Errorf("unexpected labels %v", label.Data)
+	}
+	if part := p.ExtractVertices(clusters[1]); part.VerticesCount() != 4 {
+		t.Errorf("expected a PLY of the blob, got %d vertices", part.VerticesCount())
+	}
+}
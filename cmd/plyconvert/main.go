@@ -0,0 +1,168 @@
+// Command plyconvert converts a PLY file between ASCII and binary formats
+// and byte orders, optionally gzipped, keeping or renaming properties on
+// the way, or exports it to OBJ, STL, PCD or XYZ.
+//
+// Usage:
+//
+//	plyconvert [flags] input output
+//
+// The input may be gzipped. Either file may be "-" for standard input or
+// output. The output format follows the extension of the output file,
+// .ply[.gz], .obj, .stl, .pcd, .xyz or .txt, unless -to is given.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flywave/go-ply"
+)
+
+var formats = map[string]int{
+	"ascii":                ply.Ascii,
+	"binary":               ply.BinaryLittleEndian,
+	"binary_little_endian": ply.BinaryLittleEndian,
+	"binary_big_endian":    ply.BinaryBigEndian,
+}
+
+type options struct {
+	to     string
+	format string
+	gzip   bool
+	keep   string
+	rename string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var o options
+	flags := flag.NewFlagSet("plyconvert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&o.to, "to", "", "output `type`: ply, obj, stl, pcd or xyz; from the output extension by default")
+	flags.StringVar(&o.format, "format", "", "`encoding`: ascii, binary (little endian), binary_little_endian or binary_big_endian;\n"+
+		"the input's for PLY, binary for STL and PCD")
+	flags.BoolVar(&o.gzip, "gzip", false, "gzip PLY output, also done for a .gz output file")
+	flags.StringVar(&o.keep, "keep", "", "comma separated `element.property` list of the properties to keep, all by default")
+	flags.StringVar(&o.rename, "rename", "", "comma separated `element.old=new` list of properties to rename")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: plyconvert [flags] input output")
+		flags.PrintDefaults()
+	}
+	if e := flags.Parse(args); e != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	if e := convert(flags.Arg(0), flags.Arg(1), &o, stdin, stdout); e != nil {
+		fmt.Fprintf(stderr, "plyconvert: %v\n", e)
+		return 1
+	}
+	return 0
+}
+
+func convert(input, output string, o *options, stdin io.Reader, stdout io.Writer) error {
+	to := o.to
+	if to == "" {
+		to = outputType(output)
+	}
+	switch to {
+	case "ply", "obj", "stl", "pcd", "xyz":
+	default:
+		return errors.New("Unknown output type " + to)
+	}
+	format, ok := formats[o.format]
+	if o.format != "" && !ok {
+		return errors.New("Unknown format " + o.format)
+	}
+	opts := &ply.LoadOptions{}
+	if o.keep != "" {
+		opts.Properties = strings.Split(o.keep, ",")
+	}
+	p := new(ply.PLY)
+	var e error
+	if input == "-" {
+		e = p.ReadWithOptions(stdin, opts)
+	} else {
+		e = p.LoadWithOptions(input, opts)
+	}
+	if e != nil {
+		return e
+	}
+	if o.rename != "" {
+		for _, r := range strings.Split(o.rename, ",") {
+			if e := rename(p, r); e != nil {
+				return e
+			}
+		}
+	}
+	if o.format != "" {
+		if e := p.ConvertTo(format); e != nil {
+			return e
+		}
+	}
+	if output == "-" {
+		return write(stdout, p, to, o)
+	}
+	f, e := os.Create(output)
+	if e != nil {
+		return e
+	}
+	o.gzip = o.gzip || strings.HasSuffix(strings.ToLower(output), ".gz")
+	e = write(f, p, to, o)
+	if ce := f.Close(); e == nil {
+		e = ce
+	}
+	return e
+}
+
+func write(w io.Writer, p *ply.PLY, to string, o *options) error {
+	switch to {
+	case "obj":
+		return p.ToOBJ(w)
+	case "stl":
+		return p.ToSTL(w, o.format == "ascii")
+	case "pcd":
+		return p.ToPCD(w, o.format != "ascii")
+	case "xyz":
+		return p.ToXYZ(w, nil)
+	}
+	return p.WriteWithOptions(w, &ply.SaveOptions{Gzip: o.gzip})
+}
+
+// outputType derives the output type from the file extension, ply for
+// unknown ones.
+func outputType(name string) string {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(strings.ToLower(name), ".gz")))
+	switch ext {
+	case ".obj", ".stl", ".pcd", ".xyz":
+		return ext[1:]
+	case ".txt":
+		return "xyz"
+	}
+	return "ply"
+}
+
+// rename applies an element.old=new rename.
+func rename(p *ply.PLY, r string) error {
+	eq := strings.Index(r, "=")
+	dot := strings.Index(r, ".")
+	if eq < 0 || dot < 0 || dot > eq {
+		return errors.New("Invalid rename " + r + ", expected element.old=new")
+	}
+	for _, elem := range p.Elements {
+		if elem.Name == r[:dot] {
+			return elem.RenameProperty(r[dot+1:eq], r[eq+1:])
+		}
+	}
+	return errors.New("No element " + r[:dot])
+}
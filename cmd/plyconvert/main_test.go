@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flywave/go-ply"
+)
+
+const square = `ply
+format ascii 1.0
+element vertex 4
+property float x
+property float y
+property float z
+property uchar red
+element face 2
+property list uchar int vertex_indices
+end_header
+0 0 0 10
+1 0 0 20
+1 1 0 30
+0 1 0 40
+3 0 1 2
+3 0 2 3
+`
+
+func TestRun(t *testing.T) {
+	dir, e := ioutil.TempDir("", "plyconvert")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	input := filepath.Join(dir, "square.ply")
+	if e := ioutil.WriteFile(input, []byte(square), 0644); e != nil {
+		t.Fatal(e)
+	}
+	var stdout, stderr bytes.Buffer
+	output := filepath.Join(dir, "out.ply.gz")
+	args := []string{"-format", "binary_big_endian", "-rename", "vertex.red=intensity",
+		"-keep", "vertex.x,vertex.y,vertex.z,vertex.red", input, output}
+	if status := run(args, nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected success, got %d: %s", status, stderr.String())
+	}
+	p := new(ply.PLY)
+	if e := p.Load(output); e != nil {
+		t.Fatal(e)
+	}
+	if p.FileType != ply.BinaryBigEndian || len(p.Elements) != 1 {
+		t.Errorf("expected a big endian vertex-only file, got format %d with %d elements", p.FileType, len(p.Elements))
+	}
+	vertex := p.GetVertices()
+	if len(vertex.Properties) != 4 || vertex.Properties[3].Name != "intensity" {
+		t.Errorf("expected red renamed to intensity, got %+v", vertex.Properties[3])
+	}
+
+	// export from standard input to standard output
+	stdout.Reset()
+	if status := run([]string{"-to", "obj", "-", "-"}, strings.NewReader(square), &stdout, &stderr); status != 0 {
+		t.Fatalf("expected success, got %d: %s", status, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "f 1 2 3") {
+		t.Errorf("expected OBJ faces, got:\n%s", stdout.String())
+	}
+	stl := filepath.Join(dir, "square.stl")
+	if status := run([]string{"-format", "ascii", input, stl}, nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected success, got %d: %s", status, stderr.String())
+	}
+	if b, _ := ioutil.ReadFile(stl); !bytes.HasPrefix(b, []byte("solid")) {
+		t.Errorf("expected ASCII STL, got %q", b)
+	}
+
+	for _, args := range [][]string{
+		{"-to", "las", input, "-"},
+		{"-format", "binary_middle_endian", input, "-"},
+		{"-rename", "vertex.red", input, "-"},
+		{"-rename", "edge.a=b", input, "-"},
+		{filepath.Join(dir, "missing.ply"), "-"},
+	} {
+		stderr.Reset()
+		if status := run(args, nil, &stdout, &stderr); status != 1 || stderr.Len() == 0 {
+			t.Errorf("expected an error for %v, got %d", args, status)
+		}
+	}
+	if status := run([]string{input}, nil, &stdout, &stderr); status != 2 {
+		t.Errorf("expected usage error, got %d", status)
+	}
+}
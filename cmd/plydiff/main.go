@@ -0,0 +1,68 @@
+// Command plydiff compares two PLY files with ply.Diff and prints their
+// differences. Like diff, it exits with 0 when the files match within the
+// tolerances, 1 when they differ and 2 on errors.
+//
+// Usage:
+//
+//	plydiff [flags] a.ply b.ply
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/flywave/go-ply"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	var opts ply.DiffOptions
+	flags := flag.NewFlagSet("plydiff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Float64Var(&opts.Tolerance, "tolerance", 0, "largest absolute difference of equal values")
+	flags.Float64Var(&opts.RelativeTolerance, "relative", 0, "largest difference of equal values relative to their magnitude")
+	flags.IntVar(&opts.MaxValues, "max", 10, "differing values listed per property, all are counted")
+	flags.BoolVar(&opts.IgnoreComments, "ignore-comments", false, "ignore comments and obj_info items")
+	quiet := flags.Bool("q", false, "report only whether the files differ")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: plydiff [flags] a.ply b.ply")
+		flags.PrintDefaults()
+	}
+	if e := flags.Parse(args); e != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	if opts.MaxValues == 0 {
+		opts.MaxValues = -1
+	}
+	var plys [2]*ply.PLY
+	for k, name := range flags.Args() {
+		plys[k] = new(ply.PLY)
+		if e := plys[k].Load(name); e != nil {
+			fmt.Fprintf(stderr, "plydiff: %s: %v\n", name, e)
+			return 2
+		}
+	}
+	d := ply.Diff(plys[0], plys[1], &opts)
+	if d.Equal() {
+		return 0
+	}
+	if *quiet {
+		fmt.Fprintf(stdout, "%s and %s differ\n", flags.Arg(0), flags.Arg(1))
+		return 1
+	}
+	fmt.Fprintf(stdout, "--- %s\n+++ %s\n", flags.Arg(0), flags.Arg(1))
+	if e := d.WriteText(stdout); e != nil {
+		fmt.Fprintf(stderr, "plydiff: %v\n", e)
+		return 2
+	}
+	return 1
+}
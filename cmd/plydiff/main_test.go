@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const header = `ply
+format ascii 1.0
+element vertex 2
+property float x
+end_header
+`
+
+func TestRun(t *testing.T) {
+	dir, e := ioutil.TempDir("", "plydiff")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	a, b := filepath.Join(dir, "a.ply"), filepath.Join(dir, "b.ply")
+	if e := ioutil.WriteFile(a, []byte(header+"0\n1\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+	if e := ioutil.WriteFile(b, []byte(header+"0\n1.01\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{a, a}, &stdout, &stderr); status != 0 || stdout.Len() != 0 {
+		t.Errorf("expected identical files, got %d: %s", status, stdout.String())
+	}
+	if status := run([]string{a, b}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected differing files, got %d", status)
+	}
+	if want := "property vertex.x: 1 values differ"; !strings.Contains(stdout.String(), want) {
+		t.Errorf("expected %q in:\n%s", want, stdout.String())
+	}
+	if status := run([]string{"-tolerance", "0.1", a, b}, &stdout, &stderr); status != 0 {
+		t.Errorf("expected files equal within the tolerance, got %d", status)
+	}
+	stdout.Reset()
+	if status := run([]string{"-q", a, b}, &stdout, &stderr); status != 1 || !strings.HasSuffix(stdout.String(), "differ\n") {
+		t.Errorf("expected a brief report, got %d: %s", status, stdout.String())
+	}
+	if status := run([]string{a, filepath.Join(dir, "missing.ply")}, &stdout, &stderr); status != 2 {
+		t.Errorf("expected an error for a missing file, got %d", status)
+	}
+	if status := run([]string{a}, &stdout, &stderr); status != 2 {
+		t.Errorf("expected usage error, got %d", status)
+	}
+}
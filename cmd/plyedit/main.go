@@ -0,0 +1,280 @@
+// Command plyedit applies a chain of operations to PLY files, e.g. to
+// batch process the scans of a directory:
+//
+//	plyedit -op crop:-10,-10,0,10,10,5 -op downsample:0.05 -op normals:16 -o out scans/*.ply
+//
+// Operations are given by repeated -op flags, after those of a -spec file
+// holding one operation per line, and applied in order. Each takes its
+// arguments after a colon, separated by commas:
+//
+//	crop:minx,miny,minz,maxx,maxy,maxz  keep the vertices inside a box
+//	clip:nx,ny,nz,d                     keep the side of a plane the normal points to
+//	downsample:cell                     keep one vertex per voxel
+//	sample:fraction                     keep a random fraction of the vertices
+//	translate:x,y,z                     move the vertices
+//	scale:factor                        scale the vertices about the origin
+//	transform:m00,m01,...,m23           apply a row-major 3x4 affine matrix
+//	swapyz                              exchange the y and z axes
+//	merge:file.ply                      append the elements of another file
+//	normals[:k]                         compute normals from the faces, or from
+//	                                    the k nearest points (10) without faces
+//	cleanup                             drop degenerate faces and unused vertices
+//	removeinvalid                       drop vertices with NaN or infinite positions
+//	simplify:ratio                      reduce the faces to a fraction
+//
+// With a single input, -o names the output file or an existing directory;
+// with several, a directory receiving each result under its input's name.
+// Lines of a spec file starting with # are ignored.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/flywave/go-ply"
+)
+
+// opList collects repeated -op flags.
+type opList []string
+
+func (l *opList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *opList) Set(op string) error {
+	*l = append(*l, op)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	var ops opList
+	flags := flag.NewFlagSet("plyedit", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Var(&ops, "op", "`operation` to apply, repeatable")
+	spec := flags.String("spec", "", "`file` of operations, one per line, applied before -op")
+	output := flags.String("o", "", "output `file` or directory")
+	verbose := flags.Bool("v", false, "report the vertex and face counts after each operation")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: plyedit [flags] -o output input.ply...")
+		flags.PrintDefaults()
+	}
+	if e := flags.Parse(args); e != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || *output == "" {
+		flags.Usage()
+		return 2
+	}
+	if *spec != "" {
+		lines, e := readSpec(*spec)
+		if e != nil {
+			fmt.Fprintf(stderr, "plyedit: %v\n", e)
+			return 1
+		}
+		ops = append(opList(lines), ops...)
+	}
+	for _, op := range ops {
+		if e := checkOp(op); e != nil {
+			fmt.Fprintf(stderr, "plyedit: %v\n", e)
+			return 2
+		}
+	}
+	info, e := os.Stat(*output)
+	dir := e == nil && info.IsDir()
+	if flags.NArg() > 1 && !dir {
+		fmt.Fprintf(stderr, "plyedit: %s is not a directory\n", *output)
+		return 2
+	}
+	status := 0
+	for _, input := range flags.Args() {
+		out := *output
+		if dir {
+			out = filepath.Join(out, filepath.Base(input))
+		}
+		var log io.Writer
+		if *verbose {
+			log = stderr
+		}
+		if e := edit(input, out, ops, log); e != nil {
+			fmt.Fprintf(stderr, "plyedit: %s: %v\n", input, e)
+			status = 1
+		}
+	}
+	return status
+}
+
+func readSpec(name string) ([]string, error) {
+	f, e := os.Open(name)
+	if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+	var ops []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			ops = append(ops, line)
+		}
+	}
+	return ops, s.Err()
+}
+
+// edit loads input, applies ops in order and saves the result to output,
+// logging the counts after each operation to log if not nil.
+func edit(input, output string, ops []string, log io.Writer) error {
+	p := new(ply.PLY)
+	if e := p.Load(input); e != nil {
+		return e
+	}
+	for _, op := range ops {
+		q, e := apply(p, op)
+		if e != nil {
+			return errors.New(op + ": " + e.Error())
+		}
+		p = q
+		if log != nil {
+			faces := 0
+			for _, elem := range p.Elements {
+				if elem.Name == "face" {
+					faces = elem.Size
+				}
+			}
+			fmt.Fprintf(log, "%s: %s: %d vertices, %d faces\n", input, op, p.VerticesCount(), faces)
+		}
+	}
+	return p.Save(output)
+}
+
+// arities holds the accepted argument counts of every operation taking
+// numbers.
+var arities = map[string][]int{
+	"crop":          {6},
+	"clip":          {4},
+	"downsample":    {1},
+	"sample":        {1},
+	"translate":     {3},
+	"scale":         {1},
+	"transform":     {12},
+	"swapyz":        {0},
+	"normals":       {0, 1},
+	"cleanup":       {0},
+	"removeinvalid": {0},
+	"simplify":      {1},
+}
+
+// parseOp splits op into its name and arguments.
+func parseOp(op string) (string, string) {
+	if k := strings.Index(op, ":"); k >= 0 {
+		return op[:k], op[k+1:]
+	}
+	return op, ""
+}
+
+// checkOp validates an operation before any file is processed.
+func checkOp(op string) error {
+	name, arg := parseOp(op)
+	if name == "merge" {
+		if arg == "" {
+			return errors.New("Merge needs a file")
+		}
+		return nil
+	}
+	_, e := numbers(name, arg)
+	return e
+}
+
+// numbers parses the arguments of a numeric operation.
+func numbers(name, arg string) ([]float64, error) {
+	arities, ok := arities[name]
+	if !ok {
+		return nil, errors.New("Unknown operation " + name)
+	}
+	var values []float64
+	if arg != "" {
+		for _, field := range strings.Split(arg, ",") {
+			v, e := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if e != nil {
+				return nil, errors.New("Invalid argument " + field + " of " + name)
+			}
+			values = append(values, v)
+		}
+	}
+	for _, n := range arities {
+		if len(values) == n {
+			return values, nil
+		}
+	}
+	return nil, errors.New("Wrong number of arguments for " + name)
+}
+
+// apply runs a single operation, returning the edited PLY, which is p
+// itself for in-place operations.
+func apply(p *ply.PLY, op string) (*ply.PLY, error) {
+	name, arg := parseOp(op)
+	if name == "merge" {
+		q := new(ply.PLY)
+		if e := q.Load(arg); e != nil {
+			return nil, e
+		}
+		return ply.Merge(p, q)
+	}
+	v, e := numbers(name, arg)
+	if e != nil {
+		return nil, e
+	}
+	switch name {
+	case "crop":
+		return p.CropAABB([3]float64{v[0], v[1], v[2]}, [3]float64{v[3], v[4], v[5]})
+	case "clip":
+		return p.ClipPlane([3]float64{v[0], v[1], v[2]}, v[3])
+	case "downsample":
+		return p.VoxelDownsample(v[0])
+	case "sample":
+		return p.RandomSample(v[0], 0)
+	case "translate":
+		e = p.Transform([4][4]float64{{1, 0, 0, v[0]}, {0, 1, 0, v[1]}, {0, 0, 1, v[2]}, {0, 0, 0, 1}})
+	case "scale":
+		e = p.ScaleUnits(v[0])
+	case "transform":
+		e = p.Transform([4][4]float64{{v[0], v[1], v[2], v[3]}, {v[4], v[5], v[6], v[7]}, {v[8], v[9], v[10], v[11]}, {0, 0, 0, 1}})
+	case "swapyz":
+		e = p.SwapYZ()
+	case "normals":
+		if hasFaces(p) {
+			e = p.ComputeNormals(ply.AreaWeighted)
+		} else {
+			opts := &ply.NormalEstimationOptions{}
+			if len(v) == 1 {
+				opts.K = int(v[0])
+			}
+			e = p.EstimateNormals(opts)
+		}
+	case "cleanup":
+		_, e = p.Cleanup()
+	case "removeinvalid":
+		_, e = p.RemoveInvalidVertices()
+	case "simplify":
+		e = p.SimplifyRatio(v[0])
+	}
+	return p, e
+}
+
+func hasFaces(p *ply.PLY) bool {
+	for _, elem := range p.Elements {
+		if elem.Name == "face" && elem.Size > 0 {
+			return true
+		}
+	}
+	return false
+}
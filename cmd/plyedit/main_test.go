@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flywave/go-ply"
+)
+
+const square = `ply
+format ascii 1.0
+element vertex 4
+property float x
+property float y
+property float z
+element face 2
+property list uchar int vertex_indices
+end_header
+0 0 0
+1 0 0
+1 1 0
+0 1 0
+3 0 1 2
+3 0 2 3
+`
+
+func TestRun(t *testing.T) {
+	dir, e := ioutil.TempDir("", "plyedit")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	input := filepath.Join(dir, "square.ply")
+	if e := ioutil.WriteFile(input, []byte(square), 0644); e != nil {
+		t.Fatal(e)
+	}
+	spec := filepath.Join(dir, "pipeline.txt")
+	if e := ioutil.WriteFile(spec, []byte("# move up\ntranslate:0,0,2\n\nmerge:"+input+"\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+	var stderr bytes.Buffer
+	output := filepath.Join(dir, "out.ply")
+	args := []string{"-v", "-spec", spec, "-op", "scale:2", "-op", "crop:-1,-1,-1,3,3,1", "-op", "normals", "-o", output, input}
+	if status := run(args, &stderr); status != 0 {
+		t.Fatalf("expected success, got %d: %s", status, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "merge:"+input+": 8 vertices, 4 faces") {
+		t.Errorf("expected progress, got:\n%s", stderr.String())
+	}
+	p := new(ply.PLY)
+	if e := p.Load(output); e != nil {
+		t.Fatal(e)
+	}
+	// the merged copy is scaled and the raised one cropped away
+	pos, e := p.Positions()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(pos) != 4 || pos[2] != [3]float64{2, 2, 0} || len(p.ReadFaces()) != 2 {
+		t.Errorf("unexpected result %v", pos)
+	}
+	if n, e := p.Normals(); e != nil || n[0] != [3]float64{0, 0, 1} {
+		t.Errorf("expected normals, got %v %v", n, e)
+	}
+
+	// several inputs go to a directory
+	out := filepath.Join(dir, "out")
+	if e := os.Mkdir(out, 0755); e != nil {
+		t.Fatal(e)
+	}
+	if status := run([]string{"-op", "cleanup", "-o", out, input, output}, &stderr); status != 0 {
+		t.Fatalf("expected success, got %d: %s", status, stderr.String())
+	}
+	for _, name := range []string{"square.ply", "out.ply"} {
+		if _, e := os.Stat(filepath.Join(out, name)); e != nil {
+			t.Error(e)
+		}
+	}
+
+	for _, c := range []struct {
+		args   []string
+		status int
+	}{
+		{[]string{"-op", "twist:1", "-o", output, input}, 2},
+		{[]string{"-op", "crop:1,2", "-o", output, input}, 2},
+		{[]string{"-op", "scale:x", "-o", output, input}, 2},
+		{[]string{"-op", "merge", "-o", output, input}, 2},
+		{[]string{"-o", output, input, input}, 2},
+		{[]string{input}, 2},
+		{[]string{"-op", "downsample:-1", "-o", output, input}, 1},
+		{[]string{"-o", output, filepath.Join(dir, "missing.ply")}, 1},
+	} {
+		stderr.Reset()
+		if status := run(c.args, &stderr); status != c.status || stderr.Len() == 0 {
+			t.Errorf("expected status %d for %v, got %d", c.status, c.args, status)
+		}
+	}
+}
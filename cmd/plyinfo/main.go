@@ -0,0 +1,129 @@
+// Command plyinfo prints a summary of PLY files: their header, the rows of
+// each element, statistics of every scalar property, the bounds of the
+// vertices and the mesh quality counts of ply.Report.
+//
+// Usage:
+//
+//	plyinfo [-json] file.ply...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/flywave/go-ply"
+)
+
+// fileReport is the JSON output for one file.
+type fileReport struct {
+	File     string   `json:"file"`
+	Comments []string `json:"comments,omitempty"`
+	*ply.Report
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("plyinfo", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the reports as a JSON array")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: plyinfo [-json] file.ply...")
+		flags.PrintDefaults()
+	}
+	if e := flags.Parse(args); e != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	status := 0
+	var reports []fileReport
+	for k, name := range flags.Args() {
+		p := new(ply.PLY)
+		if e := p.Load(name); e != nil {
+			fmt.Fprintf(stderr, "plyinfo: %s: %v\n", name, e)
+			status = 1
+			continue
+		}
+		if *asJSON {
+			reports = append(reports, fileReport{name, p.Comments, p.Report()})
+			continue
+		}
+		if k > 0 {
+			fmt.Fprintln(stdout)
+		}
+		if e := writeInfo(stdout, name, p); e != nil {
+			fmt.Fprintf(stderr, "plyinfo: %v\n", e)
+			return 1
+		}
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if e := enc.Encode(reports); e != nil {
+			fmt.Fprintf(stderr, "plyinfo: %v\n", e)
+			return 1
+		}
+	}
+	return status
+}
+
+// writeInfo prints the header of p with a table of the properties of each
+// element, giving the statistics of scalar properties, followed by the
+// vertex bounds and mesh quality counts.
+func writeInfo(w io.Writer, name string, p *ply.PLY) error {
+	r := p.Report()
+	stats := make(map[string]ply.PropertyReport, len(r.Properties))
+	for _, pr := range r.Properties {
+		stats[pr.Element+"."+pr.Name] = pr
+	}
+	fmt.Fprintf(w, "%s\n", name)
+	fmt.Fprintf(w, "format: %s\n", r.Format)
+	for _, c := range p.Comments {
+		fmt.Fprintf(w, "comment: %s\n", c)
+	}
+	keys := make([]string, 0, len(p.ObjInfoItems))
+	for key := range p.ObjInfoItems {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "obj_info: %s %s\n", key, p.ObjInfoItems[key])
+	}
+	for _, elem := range p.Elements {
+		fmt.Fprintf(w, "element %s %d\n", elem.Name, elem.Size)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tproperty\ttype\tmin\tmax\tmean\tnan\tinf")
+		for _, prop := range elem.Properties {
+			if prop.IsList {
+				fmt.Fprintf(tw, "\t%s\tlist %s %s\n", prop.Name, prop.ListSizeType, prop.Type)
+				continue
+			}
+			pr := stats[elem.Name+"."+prop.Name]
+			fmt.Fprintf(tw, "\t%s\t%s\t%g\t%g\t%g\t%d\t%d\n",
+				prop.Name, prop.Type, pr.Min, pr.Max, pr.Mean, pr.NaN, pr.Inf)
+		}
+		if e := tw.Flush(); e != nil {
+			return e
+		}
+	}
+	if r.Bounds != nil {
+		fmt.Fprintf(w, "bounds: min %v max %v\n", r.Bounds.Min, r.Bounds.Max)
+	}
+	fmt.Fprintf(w, "vertices: %d, faces: %d\n", r.Vertices, r.Faces)
+	if r.Faces > 0 {
+		fmt.Fprintf(w, "degenerate faces: %d, boundary edges: %d, non-manifold edges: %d\n",
+			r.DegenerateFaces, r.BoundaryEdges, r.NonManifoldEdges)
+	}
+	_, e := fmt.Fprintf(w, "duplicate points: %d\n", r.DuplicatePoints)
+	return e
+}
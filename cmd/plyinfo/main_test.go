@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cube = `ply
+format ascii 1.0
+comment a unit square
+element vertex 4
+property float x
+property float y
+property float z
+property uchar red
+element face 2
+property list uchar int vertex_indices
+end_header
+0 0 0 10
+1 0 0 20
+1 1 0 30
+0 1 0 40
+3 0 1 2
+3 0 2 3
+`
+
+func TestRun(t *testing.T) {
+	dir, e := ioutil.TempDir("", "plyinfo")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "square.ply")
+	if e := ioutil.WriteFile(name, []byte(cube), 0644); e != nil {
+		t.Fatal(e)
+	}
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{name}, &stdout, &stderr); status != 0 {
+		t.Fatalf("expected success, got %d: %s", status, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"format: ascii",
+		"comment: a unit square",
+		"element vertex 4\n",
+		"  red       uchar  10   40   25    0    0\n",
+		"element face 2\n",
+		"  vertex_indices  list uchar int\n",
+		"bounds: min [0 0 0] max [1 1 0]",
+		"vertices: 4, faces: 2",
+		"boundary edges: 4",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	stdout.Reset()
+	if status := run([]string{"-json", name, filepath.Join(filepath.Dir(name), "missing.ply")}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected failure for a missing file, got %d", status)
+	}
+	var reports []struct {
+		File     string
+		Vertices int
+		Faces    int
+	}
+	if e := json.Unmarshal(stdout.Bytes(), &reports); e != nil {
+		t.Fatal(e)
+	}
+	if len(reports) != 1 || reports[0].File != name || reports[0].Vertices != 4 || reports[0].Faces != 2 {
+		t.Errorf("unexpected reports %+v", reports)
+	}
+	if !strings.Contains(stderr.String(), "missing.ply") {
+		t.Errorf("expected the missing file to be reported, got %q", stderr.String())
+	}
+	if status := run(nil, &stdout, &stderr); status != 2 {
+		t.Errorf("expected usage error, got %d", status)
+	}
+}
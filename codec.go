@@ -0,0 +1,44 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// TypeCodec handles a property type keyword the package does not know,
+// such as "int64" or a vendor type. Values are kept in memory as Size
+// bytes in the property's byte order, converted between byte orders by
+// reversing them, like the built-in types.
+type TypeCodec struct {
+	Size int
+	// Float and Signed describe the values for conversions, e.g. Row
+	// returns float64, int64 or uint64 accordingly.
+	Float  bool
+	Signed bool
+	// Decode and Encode convert a value to and from float64 for the
+	// numeric helpers; values beyond 2^53 lose precision there, while
+	// reading and writing files preserve them.
+	Decode func(b []byte, order binary.ByteOrder) float64
+	Encode func(b []byte, v float64, order binary.ByteOrder)
+	// Parse and Format convert an ASCII token. DefaultTokenizer only
+	// accepts decimal numbers, so other token syntaxes need a Tokenizer.
+	Parse  func(token string, order binary.ByteOrder) ([]byte, error)
+	Format func(b []byte, order binary.ByteOrder) string
+}
+
+var codecs = map[string]*TypeCodec{}
+
+// RegisterType makes name usable as a property type. Register types
+// during initialization, before files are read or written, as the
+// registry is not synchronized.
+func RegisterType(name string, c *TypeCodec) error {
+	if _, ok := SizeOfType[name]; ok && codecs[name] == nil {
+		return errors.New("Type " + name + " is built in")
+	}
+	if c == nil || c.Size <= 0 || c.Decode == nil || c.Encode == nil || c.Parse == nil || c.Format == nil {
+		return errors.New("Type " + name + " needs a size and all codec functions")
+	}
+	codecs[name] = c
+	SizeOfType[name] = c.Size
+	return nil
+}
@@ -0,0 +1,78 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var int64Codec = &TypeCodec{
+	Size:   8,
+	Signed: true,
+	Decode: func(b []byte, order binary.ByteOrder) float64 { return float64(int64(order.Uint64(b))) },
+	Encode: func(b []byte, v float64, order binary.ByteOrder) { order.PutUint64(b, uint64(int64(v))) },
+	Parse: func(token string, order binary.ByteOrder) ([]byte, error) {
+		n, e := strconv.ParseInt(token, 10, 64)
+		b := make([]byte, 8)
+		order.PutUint64(b, uint64(n))
+		return b, e
+	},
+	Format: func(b []byte, order binary.ByteOrder) string {
+		return strconv.FormatInt(int64(order.Uint64(b)), 10)
+	},
+}
+
+func TestRegisterType(t *testing.T) {
+	if e := RegisterType("int64", int64Codec); e != nil {
+		t.Fatal(e)
+	}
+	defer func() {
+		delete(codecs, "int64")
+		delete(SizeOfType, "int64")
+	}()
+	if e := RegisterType("float", int64Codec); e == nil {
+		t.Error("expected built-in types to be protected")
+	}
+	if e := RegisterType("int128", &TypeCodec{Size: 16}); e == nil {
+		t.Error("expected an error for missing codec functions")
+	}
+	src := `ply
+format ascii 1.0
+element vertex 2
+property float x
+property int64 id
+end_header
+1 9007199254740993
+2 -42
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	id := p.findElement("vertex").findProperty("id")
+	if id.float64At(1) != -42 {
+		t.Errorf("unexpected decoded value %v", id.float64At(1))
+	}
+	var bin bytes.Buffer
+	p.FileType = BinaryBigEndian
+	if e := p.Write(&bin); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&bin); e != nil {
+		t.Fatal(e)
+	}
+	q.FileType = Ascii
+	var out bytes.Buffer
+	if e := q.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.HasSuffix(out.String(), "end_header\n1 9007199254740993\n2 -42\n") {
+		t.Errorf("expected exact values to round-trip, got\n%s", out.String())
+	}
+	if _, e := toType("x", "int64"); e == nil {
+		t.Error("expected the codec's parse error")
+	}
+}
@@ -0,0 +1,141 @@
+package ply
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// Projector maps a point to image pixel coordinates, with y pointing down.
+// ok is false when the point is not visible.
+type Projector interface {
+	Project(v [3]float64) (x, y float64, ok bool)
+}
+
+// PinholeCamera projects through an ideal pinhole. Rotation and Translation
+// transform world into camera coordinates (c = R*v + T), with the camera
+// looking along +z.
+type PinholeCamera struct {
+	Fx, Fy, Cx, Cy float64
+	Rotation       [3][3]float64
+	Translation    [3]float64
+}
+
+func (c *PinholeCamera) Project(v [3]float64) (float64, float64, bool) {
+	var p [3]float64
+	for i := 0; i < 3; i++ {
+		p[i] = dot3(c.Rotation[i], v) + c.Translation[i]
+	}
+	if p[2] <= 0 {
+		return 0, 0, false
+	}
+	return c.Fx*p[0]/p[2] + c.Cx, c.Fy*p[1]/p[2] + c.Cy, true
+}
+
+// PlanarProjection projects orthogonally onto a plane through Origin. U and
+// V are the image x and y axes scaled to pixels per unit length.
+type PlanarProjection struct {
+	Origin [3]float64
+	U, V   [3]float64
+}
+
+func (pp *PlanarProjection) Project(v [3]float64) (float64, float64, bool) {
+	d := sub3(v, pp.Origin)
+	return dot3(d, pp.U), dot3(d, pp.V), true
+}
+
+// ColorizeFromImage samples img bilinearly at the projection of every
+// vertex and stores the result in the red, green and blue properties,
+// creating them as uchar if needed. Vertices projecting outside the image
+// keep their color. It returns the number of colored vertices.
+func (p *PLY) ColorizeFromImage(img image.Image, proj Projector) (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	if img == nil || proj == nil {
+		return 0, errors.New("ColorizeFromImage needs an image and a projector")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return 0, e
+	}
+	elem := p.findElement("vertex")
+	var rgb [3]*Property
+	for j, name := range []string{"red", "green", "blue"} {
+		if rgb[j], e = elem.ensureProperty(name, "uchar"); e != nil {
+			return 0, e
+		}
+		for i := range rgb[j].Data {
+			if rgb[j].Data[i] == nil {
+				rgb[j].setFloat64At(i, 0)
+			}
+		}
+	}
+	colored := 0
+	for i, v := range pos {
+		x, y, ok := proj.Project(v)
+		if !ok {
+			continue
+		}
+		c, ok := sampleBilinear(img, x, y)
+		if !ok {
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			if isFloat(rgb[j].Type) {
+				rgb[j].setFloat64At(i, c[j]/255)
+			} else {
+				rgb[j].setFloat64At(i, c[j])
+			}
+		}
+		colored++
+	}
+	return colored, nil
+}
+
+// sampleBilinear returns the 8-bit RGB color at continuous pixel position
+// (x, y), where pixel centers lie at half-integer coordinates.
+func sampleBilinear(img image.Image, x, y float64) ([3]float64, bool) {
+	b := img.Bounds()
+	if math.IsNaN(x) || math.IsNaN(y) || x < float64(b.Min.X) || y < float64(b.Min.Y) ||
+		x >= float64(b.Max.X) || y >= float64(b.Max.Y) {
+		return [3]float64{}, false
+	}
+	fx, fy := x-0.5, y-0.5
+	x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(x0), fy-float64(y0)
+	clampX := func(v int) int {
+		if v < b.Min.X {
+			return b.Min.X
+		}
+		if v >= b.Max.X {
+			return b.Max.X - 1
+		}
+		return v
+	}
+	clampY := func(v int) int {
+		if v < b.Min.Y {
+			return b.Min.Y
+		}
+		if v >= b.Max.Y {
+			return b.Max.Y - 1
+		}
+		return v
+	}
+	var c [3]float64
+	for _, s := range []struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - tx) * (1 - ty)},
+		{x0 + 1, y0, tx * (1 - ty)},
+		{x0, y0 + 1, (1 - tx) * ty},
+		{x0 + 1, y0 + 1, tx * ty},
+	} {
+		r, g, bl, _ := img.At(clampX(s.x), clampY(s.y)).RGBA()
+		c[0] += s.w * float64(r>>8)
+		c[1] += s.w * float64(g>>8)
+		c[2] += s.w * float64(bl>>8)
+	}
+	return c, true
+}
@@ -0,0 +1,39 @@
+package ply
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestColorizeFromImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{0, 255, 0, 255})
+	img.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	img.Set(1, 1, color.RGBA{255, 255, 255, 255})
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	// one pixel per unit, vertices land on pixel corners shifted to centers
+	proj := &PlanarProjection{Origin: [3]float64{-0.5, -0.5, 0}, U: [3]float64{1, 0, 0}, V: [3]float64{0, 1, 0}}
+	n, e := p.ColorizeFromImage(img, proj)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if n != 4 {
+		t.Errorf("expected 4 colored vertices, got %d", n)
+	}
+	v := p.GetVertices()
+	red, green, blue := v.findProperty("red"), v.findProperty("green"), v.findProperty("blue")
+	if red.float64At(0) != 255 || green.float64At(1) != 255 || blue.float64At(3) != 255 || red.float64At(2) != 255 {
+		t.Error("unexpected colors")
+	}
+	cam := &PinholeCamera{Fx: 1, Fy: 1, Cx: 1, Cy: 1,
+		Rotation: [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, Translation: [3]float64{0, 0, -1}}
+	if n, _ := p.ColorizeFromImage(img, cam); n != 0 {
+		t.Errorf("expected points behind the camera to be skipped, got %d", n)
+	}
+}
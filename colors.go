@@ -0,0 +1,225 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// Colormap maps a scalar in [0, 1] to a color by linear interpolation
+// between evenly spaced RGB stops with channels in [0, 1].
+type Colormap [][3]float64
+
+var (
+	// Viridis is the perceptually uniform default colormap of matplotlib.
+	Viridis = Colormap{
+		{0.267, 0.005, 0.329}, {0.278, 0.176, 0.482}, {0.231, 0.322, 0.545},
+		{0.173, 0.447, 0.557}, {0.129, 0.569, 0.549}, {0.157, 0.682, 0.502},
+		{0.369, 0.788, 0.384}, {0.678, 0.863, 0.188}, {0.992, 0.906, 0.145},
+	}
+	// Jet runs from dark blue through cyan and yellow to dark red.
+	Jet = Colormap{
+		{0, 0, 0.5}, {0, 0, 1}, {0, 0.5, 1}, {0, 1, 1}, {0.5, 1, 0.5},
+		{1, 1, 0}, {1, 0.5, 0}, {1, 0, 0}, {0.5, 0, 0},
+	}
+)
+
+// At returns the color for t, clamped to [0, 1].
+func (cm Colormap) At(t float64) [3]float64 {
+	if len(cm) == 0 {
+		return [3]float64{}
+	}
+	if !(t > 0) {
+		return cm[0]
+	}
+	if t >= 1 {
+		return cm[len(cm)-1]
+	}
+	f := t * float64(len(cm)-1)
+	k := int(f)
+	f -= float64(k)
+	var c [3]float64
+	for j := range c {
+		c[j] = cm[k][j]*(1-f) + cm[k+1][j]*f
+	}
+	return c
+}
+
+type ColormapOptions struct {
+	// Min and Max map to the ends of the colormap; the range of the
+	// values when both are zero.
+	Min, Max float64
+}
+
+// ColorizeByProperty colors each vertex by the named scalar property
+// through cmap, storing the result in the red, green and blue properties,
+// created as uchar if needed. Vertices whose value is NaN keep their
+// color.
+func (p *PLY) ColorizeByProperty(name string, cmap Colormap, opts *ColormapOptions) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if len(cmap) == 0 {
+		return errors.New("Empty colormap")
+	}
+	if opts == nil {
+		opts = &ColormapOptions{}
+	}
+	elem := p.findElement("vertex")
+	if elem == nil {
+		return errors.New("No vertex element")
+	}
+	values := elem.scalarProperties(name)
+	if values == nil {
+		return errors.New("Vertex element has no scalar " + name + " property")
+	}
+	lo, hi := opts.Min, opts.Max
+	if lo == 0 && hi == 0 {
+		lo, hi = math.Inf(1), math.Inf(-1)
+		for i := 0; i < elem.Size; i++ {
+			v := values[0].float64At(i)
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+	}
+	var rgb [3]*Property
+	for j, channel := range []string{"red", "green", "blue"} {
+		var e error
+		if rgb[j], e = elem.ensureProperty(channel, "uchar"); e != nil {
+			return e
+		}
+		for i := range rgb[j].Data {
+			if rgb[j].Data[i] == nil {
+				rgb[j].setFloat64At(i, 0)
+			}
+		}
+	}
+	for i := 0; i < elem.Size; i++ {
+		v := values[0].float64At(i)
+		if math.IsNaN(v) {
+			continue
+		}
+		t := 0.0
+		if hi > lo {
+			t = (v - lo) / (hi - lo)
+		}
+		c := cmap.At(t)
+		for j, prop := range rgb {
+			prop.setFloat64At(i, c[j]*colorScale(prop.Type))
+		}
+	}
+	return nil
+}
+
+// ConvertColors converts the red, green, blue and alpha vertex properties
+// to typeName, scaling between the full range of unsigned integral types,
+// e.g. 0 to 255 for uchar, and 0 to 1 for float types.
+func (p *PLY) ConvertColors(typeName string) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if r, ok := typeRanges[normalizeType(typeName)]; !ok || r[0] < 0 && !isFloat(typeName) {
+		return errors.New("Colors need an unsigned or float type, not " + typeName)
+	}
+	elem, channels, e := p.colorProperties()
+	if e != nil {
+		return e
+	}
+	for _, prop := range channels {
+		from, to := colorScale(prop.Type), colorScale(typeName)
+		values := make([]float64, elem.Size)
+		for i := range values {
+			values[i] = prop.float64At(i) / from * to
+		}
+		prop.Type = typeName
+		prop.setColumn(elem.Size, nil, func(i int) float64 { return values[i] })
+	}
+	return nil
+}
+
+// ApplyGamma raises the red, green and blue vertex channels, taken in
+// [0, 1], to the power gamma, keeping their types.
+func (p *PLY) ApplyGamma(gamma float64) error {
+	if !(gamma > 0) {
+		return errors.New("Gamma must be positive")
+	}
+	return p.mapColors(func(c float64) float64 {
+		return math.Pow(c, gamma)
+	})
+}
+
+// SRGBToLinear decodes the red, green and blue vertex channels from the
+// sRGB transfer function to linear light, e.g. before averaging colors.
+func (p *PLY) SRGBToLinear() error {
+	return p.mapColors(func(c float64) float64 {
+		if c <= 0.04045 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	})
+}
+
+// LinearToSRGB encodes linear red, green and blue vertex channels with the
+// sRGB transfer function, undoing SRGBToLinear.
+func (p *PLY) LinearToSRGB() error {
+	return p.mapColors(func(c float64) float64 {
+		if c <= 0.0031308 {
+			return c * 12.92
+		}
+		return 1.055*math.Pow(c, 1/2.4) - 0.055
+	})
+}
+
+// mapColors applies f to the red, green and blue channels normalized to
+// [0, 1], leaving alpha alone.
+func (p *PLY) mapColors(f func(c float64) float64) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	elem, channels, e := p.colorProperties()
+	if e != nil {
+		return e
+	}
+	for _, prop := range channels[:3] {
+		scale := colorScale(prop.Type)
+		for i := 0; i < elem.Size; i++ {
+			c := prop.float64At(i) / scale
+			if c < 0 {
+				c = 0
+			} else if c > 1 {
+				c = 1
+			}
+			prop.setFloat64At(i, f(c)*scale)
+		}
+	}
+	return nil
+}
+
+// colorProperties returns the vertex element with its red, green, blue
+// and, if present, alpha scalar properties.
+func (p *PLY) colorProperties() (*Element, []*Property, error) {
+	elem := p.findElement("vertex")
+	if elem == nil {
+		return nil, nil, errors.New("No vertex element")
+	}
+	rgb := elem.scalarProperties("red", "green", "blue")
+	if rgb == nil {
+		return nil, nil, errors.New("Vertex element has no scalar red, green, blue properties")
+	}
+	if alpha := elem.scalarProperties("alpha"); alpha != nil {
+		rgb = append(rgb, alpha[0])
+	}
+	return elem, rgb, nil
+}
+
+// colorScale returns the value of full intensity in a channel of
+// typeName.
+func colorScale(typeName string) float64 {
+	if isFloat(typeName) {
+		return 1
+	}
+	if r, ok := typeRanges[normalizeType(typeName)]; ok && r[1] > 0 {
+		return r[1]
+	}
+	return 255
+}
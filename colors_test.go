@@ -0,0 +1,114 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const testColoredPoints = `ply
+format ascii 1.0
+element vertex 3
+property float x
+property float y
+property float z
+property uchar red
+property uchar green
+property uchar blue
+property uchar alpha
+end_header
+0 0 0 0 128 255 255
+1 0 0 255 64 0 128
+2 0 0 10 20 30 0
+`
+
+func TestConvertColors(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testColoredPoints)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ConvertColors("float"); e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	green, alpha := v.findProperty("green"), v.findProperty("alpha")
+	if green.Type != "float" || math.Abs(green.float64At(0)-128.0/255) > 1e-6 || alpha.float64At(1) != float64(float32(128.0/255)) {
+		t.Errorf("unexpected normalized colors %v %v", green.float64At(0), alpha.float64At(1))
+	}
+	if e := p.ConvertColors("uchar"); e != nil {
+		t.Fatal(e)
+	}
+	if green.Type != "uchar" || green.float64At(0) != 128 || v.findProperty("blue").float64At(2) != 30 {
+		t.Error("expected uchar colors to survive the round trip")
+	}
+	if e := p.ConvertColors("short"); e == nil {
+		t.Error("expected an error for a signed color type")
+	}
+}
+
+func TestSRGBToLinear(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testColoredPoints)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.SRGBToLinear(); e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	// sRGB 128 is about 21.6% linear light
+	if got := v.findProperty("green").float64At(0); got != 55 {
+		t.Errorf("expected green 55, got %v", got)
+	}
+	if got := v.findProperty("alpha").float64At(1); got != 128 {
+		t.Errorf("expected alpha untouched, got %v", got)
+	}
+	if e := p.LinearToSRGB(); e != nil {
+		t.Fatal(e)
+	}
+	if got := v.findProperty("green").float64At(0); math.Abs(got-128) > 1 {
+		t.Errorf("expected green back near 128, got %v", got)
+	}
+	if e := p.ApplyGamma(1); e != nil {
+		t.Fatal(e)
+	}
+	if got := v.findProperty("red").float64At(1); got != 255 {
+		t.Errorf("expected gamma 1 to keep red, got %v", got)
+	}
+}
+
+func TestColorizeByProperty(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ColorizeByProperty("quality", Jet, nil); e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	red, blue := v.findProperty("red"), v.findProperty("blue")
+	// quality -1e-05 is the minimum and 3 the maximum
+	if blue.float64At(2) != 128 || red.float64At(2) != 0 || red.float64At(3) != 128 || blue.float64At(3) != 0 {
+		t.Errorf("unexpected colors %v %v", red.Data, blue.Data)
+	}
+	if e := p.ColorizeByProperty("quality", Viridis, &ColormapOptions{Min: 0, Max: 0.5}); e != nil {
+		t.Fatal(e)
+	}
+	if got := v.findProperty("green").float64At(3); got != 231 {
+		t.Errorf("expected values past Max to clamp to the last stop, got %v", got)
+	}
+	if e := p.ColorizeByProperty("missing", Jet, nil); e == nil {
+		t.Error("expected an error for a missing property")
+	}
+}
+
+func TestColormapAt(t *testing.T) {
+	if c := Jet.At(0.5); c != [3]float64{0.5, 1, 0.5} {
+		t.Errorf("unexpected middle of jet %v", c)
+	}
+	if c := Jet.At(1.0 / 16); c != [3]float64{0, 0, 0.75} {
+		t.Errorf("unexpected interpolated color %v", c)
+	}
+	if c := Viridis.At(math.NaN()); c != Viridis[0] {
+		t.Errorf("expected NaN to map to the first stop, got %v", c)
+	}
+}
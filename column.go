@@ -0,0 +1,270 @@
+package ply
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+// column carves the rows of one property out of shared chunks, so that
+// decoding a property costs one allocation per chunk instead of one per
+// row and its rows lie next to each other in memory.
+type column struct {
+	p    *PLY
+	buf  []byte
+	rows int // rows still to be decoded
+	size int // bytes per value, 0 for unknown types
+	// skip discards the values of a property left out by
+	// LoadOptions.Properties
+	skip bool
+}
+
+// newColumns returns the columns decoding rows rows of elem, skipping the
+// properties not in keep unless keep is nil.
+func newColumns(p *PLY, elem *Element, rows int, keep []bool) []column {
+	cols := make([]column, len(elem.Properties))
+	for k, prop := range elem.Properties {
+		cols[k] = column{p: p, rows: rows, size: SizeOfType[prop.Type],
+			skip: keep != nil && !keep[k]}
+	}
+	return cols
+}
+
+// take returns n bytes for the next row, capped at n.
+func (c *column) take(n int) []byte {
+	if len(c.buf) < n {
+		// size the chunk for the remaining rows, guessing list rows
+		// are as long as this one
+		size := slabSize
+		if c.rows < slabSize/n {
+			size = c.rows * n
+		}
+		if size < n {
+			size = n
+		}
+		c.buf = c.p.alloc(size)
+	}
+	if c.rows > 0 {
+		c.rows--
+	}
+	b := c.buf[:n:n]
+	c.buf = c.buf[n:]
+	return b
+}
+
+// readScalar reads a scalar row of size bytes.
+func (c *column) readScalar(r *bufio.Reader, size int) ([]byte, error) {
+	if c.skip {
+		return nil, discard(r, size)
+	}
+	b := c.take(size)
+	if _, e := io.ReadFull(r, b); e != nil {
+		return nil, e
+	}
+	return b, nil
+}
+
+// readList reads n list items of size bytes. Lists larger than
+// preallocBytes grow as their data arrives, so that a corrupt count cannot
+// allocate more than the input holds.
+func (c *column) readList(r *bufio.Reader, n, size int) ([]byte, error) {
+	if c.skip {
+		return nil, discard(r, n*size)
+	}
+	if n <= preallocBytes/size {
+		return c.readScalar(r, n*size)
+	}
+	var b []byte
+	for left := n * size; left > 0; {
+		k := left
+		if k > preallocBytes {
+			k = preallocBytes
+		}
+		l := len(b)
+		b = append(b, make([]byte, k)...)
+		if _, e := io.ReadFull(r, b[l:]); e != nil {
+			return nil, e
+		}
+		left -= k
+	}
+	return b[:len(b):len(b)], nil
+}
+
+// recordStride returns the bytes per row of an element holding only
+// scalar properties of known types, 0 otherwise.
+func (e *Element) recordStride() int {
+	stride := 0
+	for _, prop := range e.Properties {
+		size := SizeOfType[prop.Type]
+		if prop.IsList || size == 0 {
+			return 0
+		}
+		stride += size
+	}
+	return stride
+}
+
+// discard skips n bytes of r.
+func discard(r *bufio.Reader, n int) error {
+	if k, e := r.Discard(n); k < n {
+		if e == io.EOF && k > 0 {
+			e = io.ErrUnexpectedEOF
+		}
+		return e
+	}
+	return nil
+}
+
+// readListCount reads a list size of typeName without allocating.
+func readListCount(r *bufio.Reader, typeName string, order binary.ByteOrder) (int, error) {
+	size := 0
+	switch typeName {
+	case "int8", "char", "uint8", "uchar":
+		size = 1
+	case "int16", "short", "uint16", "ushort":
+		size = 2
+	case "int32", "int", "uint32", "uint":
+		size = 4
+	default:
+		return 0, errors.New("Invalid list size type " + typeName)
+	}
+	b, e := r.Peek(size)
+	if len(b) < size {
+		if e == io.EOF && len(b) > 0 {
+			e = io.ErrUnexpectedEOF
+		}
+		return 0, e
+	}
+	n := intValue(b, typeName, order)
+	r.Discard(size)
+	if n < 0 {
+		return 0, errors.New("Negative list size")
+	}
+	return int(n), nil
+}
+
+// toType parses an ASCII token of typeName to its little endian bytes.
+func toType(data, typeName string) ([]byte, error) {
+	size := SizeOfType[typeName]
+	if size == 0 {
+		return nil, errors.New("Invalid type " + typeName)
+	}
+	b := make([]byte, size)
+	if e := putType(b, data, typeName); e != nil {
+		return nil, e
+	}
+	return b, nil
+}
+
+// putType parses an ASCII token of typeName into b, which holds
+// SizeOfType[typeName] bytes, in little endian order.
+func putType(b []byte, data, typeName string) error {
+	switch typeName {
+	case "int8", "char":
+		n, e := parseIntToken(data, 8)
+		if e != nil {
+			return e
+		}
+		b[0] = byte(int8(n))
+	case "int16", "short":
+		n, e := parseIntToken(data, 16)
+		if e != nil {
+			return e
+		}
+		binary.LittleEndian.PutUint16(b, uint16(int16(n)))
+	case "int32", "int":
+		n, e := parseIntToken(data, 32)
+		if e != nil {
+			return e
+		}
+		binary.LittleEndian.PutUint32(b, uint32(int32(n)))
+	case "uint8", "uchar":
+		u, e := parseUintToken(data, 8)
+		if e != nil {
+			return e
+		}
+		b[0] = uint8(u)
+	case "uint16", "ushort":
+		u, e := parseUintToken(data, 16)
+		if e != nil {
+			return e
+		}
+		binary.LittleEndian.PutUint16(b, uint16(u))
+	case "uint32", "uint":
+		u, e := parseUintToken(data, 32)
+		if e != nil {
+			return e
+		}
+		binary.LittleEndian.PutUint32(b, uint32(u))
+	case "float32", "float":
+		f, e := strconv.ParseFloat(data, 32)
+		if e != nil && !isRangeError(e) {
+			return e
+		}
+		binary.LittleEndian.PutUint32(b, math.Float32bits(float32(f)))
+	case "float64", "double":
+		f, e := strconv.ParseFloat(data, 64)
+		if e != nil && !isRangeError(e) {
+			return e
+		}
+		binary.LittleEndian.PutUint64(b, math.Float64bits(f))
+	default:
+		c := codecs[typeName]
+		if c == nil {
+			return errors.New("Invalid type " + typeName)
+		}
+		v, e := c.Parse(data, binary.LittleEndian)
+		if e != nil {
+			return e
+		}
+		if len(v) != c.Size {
+			return errors.New("Type " + typeName + " parsed to " + itoa(len(v)) + " bytes")
+		}
+		copy(b, v)
+	}
+	return nil
+}
+
+// listColumn assembles the rows of a list property in one buffer growing
+// by append, so that built lists are as compact as decoded ones: each row
+// holds exactly its items, next to the previous row.
+type listColumn struct {
+	prop *Property
+	buf  []byte
+	ends []int
+}
+
+func newListColumn(prop *Property) *listColumn {
+	return &listColumn{prop: prop}
+}
+
+// add encodes values as the next row.
+func (c *listColumn) add(values []float64) {
+	size := SizeOfType[c.prop.Type]
+	l := len(c.buf)
+	c.buf = append(c.buf, make([]byte, len(values)*size)...)
+	for j, v := range values {
+		putFloat64(c.buf[l+j*size:l+(j+1)*size], v, c.prop.Type, c.prop.byteOrder())
+	}
+	c.ends = append(c.ends, len(c.buf))
+}
+
+// addRow appends an encoded row.
+func (c *listColumn) addRow(b []byte) {
+	c.buf = append(c.buf, b...)
+	c.ends = append(c.ends, len(c.buf))
+}
+
+// rows returns the rows added, each capped at its length.
+func (c *listColumn) rows() [][]byte {
+	rows := make([][]byte, len(c.ends))
+	start := 0
+	for i, end := range c.ends {
+		rows[i] = c.buf[start:end:end]
+		start = end
+	}
+	return rows
+}
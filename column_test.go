@@ -0,0 +1,81 @@
+package ply
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Decoded and built lists hold exactly their items, without a zero-filled
+// prefix or spare capacity.
+func TestListRowsCompact(t *testing.T) {
+	// a list longer than preallocBytes grows as it is read
+	long := make([]int, preallocBytes/4+10)
+	for k := range long {
+		long[k] = k % 3
+	}
+	faces := [][]int{{0, 1, 2}, {}, {2, 1, 0, 1}, long}
+	check := func(name string, prop *Property) {
+		for i, f := range faces {
+			row := prop.row(i)
+			if len(row) != 4*len(f) || cap(row) != len(row) {
+				t.Errorf("%s: row %d has length %d and capacity %d, expected %d", name, i, len(row), cap(row), 4*len(f))
+			}
+			got := prop.listIntsAt(i)
+			if len(got) != len(f) {
+				t.Errorf("%s: row %d has %d items, expected %d", name, i, len(got), len(f))
+				continue
+			}
+			for k := range f {
+				if got[k] != f[k] {
+					t.Errorf("%s: row %d item %d is %d, expected %d", name, i, k, got[k], f[k])
+					break
+				}
+			}
+		}
+	}
+	p := &PLY{FileType: BinaryLittleEndian, Elements: []*Element{faceElement(faces)}}
+	check("built", p.Elements[0].Properties[0])
+	for _, format := range []int{Ascii, BinaryLittleEndian, BinaryBigEndian} {
+		p.FileType = int8(format)
+		var buf bytes.Buffer
+		if e := p.Write(&buf); e != nil {
+			t.Fatal(e)
+		}
+		q := new(PLY)
+		if e := q.Read(&buf); e != nil {
+			t.Fatal(e)
+		}
+		name, _ := formatName(int8(format))
+		check(name, q.Elements[0].Properties[0])
+	}
+}
+
+// Lists decode with every integral count type, not only 4 byte counts, in
+// every format.
+func TestListCountTypes(t *testing.T) {
+	sizes := []int{3, 0, 4, 127}
+	for _, sizeType := range []string{"char", "uchar", "short", "ushort", "int", "uint"} {
+		for _, format := range []int{Ascii, BinaryLittleEndian, BinaryBigEndian} {
+			p, e := NewFixture(&FixtureOptions{Format: format, Vertices: 20, Faces: 8, FaceSizes: sizes, ListSizeType: sizeType})
+			if e != nil {
+				t.Fatal(e)
+			}
+			var buf bytes.Buffer
+			if e := p.Write(&buf); e != nil {
+				t.Fatal(e)
+			}
+			q := new(PLY)
+			if e := q.Read(&buf); e != nil {
+				t.Fatalf("%s, format %d: %v", sizeType, format, e)
+			}
+			want, got := p.ReadFaces(), q.ReadFaces()
+			idx := q.findElement("face").findProperty("vertex_indices")
+			for i := range want {
+				row := idx.row(i)
+				if len(row) != 4*sizes[i%len(sizes)] || cap(row) != len(row) || !equalInts(got[i], want[i]) {
+					t.Errorf("%s, format %d: row %d is %v, expected %v", sizeType, format, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
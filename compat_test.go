@@ -0,0 +1,39 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadVerticesCompat(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 2
+property uchar red
+property double z
+property short x
+property float y
+end_header
+1 0.5 -3 2.25
+2 1e-3 7 -1
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	vs := p.ReadVertices()
+	if vs[0][0] != -3 || vs[0][1] != 7 || vs[1][0] != 2.25 || vs[2][0] != 0.5 || vs[2][1] != 0.001 {
+		t.Errorf("unexpected vertices %v", vs)
+	}
+	if p.GetVertices() != p.Elements[0] || len(p.GetVertices().Properties[1].Data) != 2 {
+		t.Error("unexpected vertex element")
+	}
+	unnamed := strings.NewReplacer("red", "a", " z", " b", " x", " c", " y", " d").Replace(src)
+	q := new(PLY)
+	if e := q.Read(strings.NewReader(unnamed)); e != nil {
+		t.Fatal(e)
+	}
+	if vs := q.ReadVertices(); vs == nil || vs[0][1] != 2 || vs[2][0] != -3 {
+		t.Errorf("expected positional fallback, got %v", vs)
+	}
+}
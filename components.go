@@ -0,0 +1,143 @@
+package ply
+
+import "sort"
+
+// Components labels the parts of a mesh connected through shared
+// vertices. Labels number the components by decreasing face count, ties in
+// order of their first face, so 0 is the largest.
+type Components struct {
+	// Vertex holds the label of each vertex, -1 for vertices without
+	// faces.
+	Vertex []int
+	// Face holds the label of each face, -1 for faces with an invalid
+	// index or no corners.
+	Face []int
+	// Faces and Vertices count the faces and vertices of each component.
+	Faces, Vertices []int
+}
+
+// ConnectedComponents labels the vertices and faces of p by connectivity.
+func (p *PLY) ConnectedComponents() (*Components, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return nil, e
+	}
+	parent := make([]int, len(pos))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	valid := func(f []int) bool {
+		for _, v := range f {
+			if v < 0 || v >= len(pos) {
+				return false
+			}
+		}
+		return len(f) > 0
+	}
+	for _, f := range faces {
+		if !valid(f) {
+			continue
+		}
+		for _, v := range f[1:] {
+			a, b := find(f[0]), find(v)
+			if a != b {
+				parent[b] = a
+			}
+		}
+	}
+	// count the faces of each root, listed in order of their first face
+	var roots []int
+	faceCount := make(map[int]int)
+	for _, f := range faces {
+		if !valid(f) {
+			continue
+		}
+		r := find(f[0])
+		if faceCount[r] == 0 {
+			roots = append(roots, r)
+		}
+		faceCount[r]++
+	}
+	sort.SliceStable(roots, func(a, b int) bool { return faceCount[roots[a]] > faceCount[roots[b]] })
+	label := make(map[int]int, len(roots))
+	c := &Components{Vertex: make([]int, len(pos)), Face: make([]int, len(faces)),
+		Faces: make([]int, len(roots)), Vertices: make([]int, len(roots))}
+	for n, r := range roots {
+		label[r] = n
+		c.Faces[n] = faceCount[r]
+	}
+	for k, f := range faces {
+		c.Face[k] = -1
+		if valid(f) {
+			c.Face[k] = label[find(f[0])]
+		}
+	}
+	for i := range pos {
+		c.Vertex[i] = -1
+		if n, ok := label[find(i)]; ok {
+			c.Vertex[i] = n
+			c.Vertices[n]++
+		}
+	}
+	return c, nil
+}
+
+// KeepLargestComponent removes every component but the one with the most
+// faces. Vertices without faces are kept.
+func (p *PLY) KeepLargestComponent() error {
+	_, e := p.removeComponents(func(c *Components, n int) bool { return n > 0 })
+	return e
+}
+
+// RemoveSmallComponents removes the components with fewer than minFaces
+// faces, e.g. floating debris around a scanned object, and returns how
+// many were removed. Vertices without faces are kept.
+func (p *PLY) RemoveSmallComponents(minFaces int) (int, error) {
+	return p.removeComponents(func(c *Components, n int) bool { return c.Faces[n] < minFaces })
+}
+
+// removeComponents removes the vertices and faces of the components for
+// which drop returns true.
+func (p *PLY) removeComponents(drop func(c *Components, n int) bool) (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	c, e := p.ConnectedComponents()
+	if e != nil {
+		return 0, e
+	}
+	dropped := make([]bool, len(c.Faces))
+	removed := 0
+	for n := range dropped {
+		if drop(c, n) {
+			dropped[n] = true
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	if face := p.findElement("face"); face != nil {
+		var rows []int
+		for k, n := range c.Face {
+			if n < 0 || !dropped[n] {
+				rows = append(rows, k)
+			}
+		}
+		sub := face.selectRows(rows)
+		face.Properties, face.Size = sub.Properties, sub.Size
+	}
+	p.Elements = p.filterVertices(func(i int) bool { n := c.Vertex[i]; return n < 0 || !dropped[n] }).Elements
+	return removed, nil
+}
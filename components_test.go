@@ -0,0 +1,78 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+// a square of two triangles, a separate triangle, a face with an invalid
+// index and two loose vertices
+const testComponents = `ply
+format ascii 1.0
+element vertex 9
+property float x
+property float y
+property float z
+element face 4
+property list uchar int vertex_indices
+end_header
+0 0 0
+1 0 0
+1 1 0
+0 1 0
+5 5 5
+6 5 5
+5 6 5
+9 9 9
+7 7 7
+3 0 1 2
+3 0 2 3
+3 4 5 6
+3 1 9 2
+`
+
+func readComponents(t *testing.T) *PLY {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testComponents)); e != nil {
+		t.Fatal(e)
+	}
+	return p
+}
+
+func TestConnectedComponents(t *testing.T) {
+	p := readComponents(t)
+	c, e := p.ConnectedComponents()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(c.Faces) != 2 || c.Faces[0] != 2 || c.Faces[1] != 1 || c.Vertices[0] != 4 || c.Vertices[1] != 3 {
+		t.Errorf("unexpected component sizes %v %v", c.Faces, c.Vertices)
+	}
+	if c.Vertex[3] != 0 || c.Vertex[5] != 1 || c.Vertex[7] != -1 || c.Face[2] != 1 || c.Face[3] != -1 {
+		t.Errorf("unexpected labels %v %v", c.Vertex, c.Face)
+	}
+	if e := p.KeepLargestComponent(); e != nil {
+		t.Fatal(e)
+	}
+	if n := p.VerticesCount(); n != 6 {
+		t.Errorf("expected the square and both loose vertices left, got %d vertices", n)
+	}
+	if faces := p.ReadFaces(); len(faces) != 2 || faces[1][2] != 3 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+}
+
+func TestRemoveSmallComponents(t *testing.T) {
+	p := readComponents(t)
+	n, e := p.RemoveSmallComponents(3)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if n != 2 || p.VerticesCount() != 2 || len(p.ReadFaces()) != 0 {
+		t.Errorf("expected both components removed, got %d with %d vertices", n, p.VerticesCount())
+	}
+	p = readComponents(t)
+	if n, _ := p.RemoveSmallComponents(1); n != 0 || p.VerticesCount() != 9 {
+		t.Errorf("expected nothing removed, got %d", n)
+	}
+}
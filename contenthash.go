@@ -0,0 +1,50 @@
+package ply
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+)
+
+// ContentHash returns a SHA-256 digest of the elements, properties and
+// values of p. It is independent of the file format, byte order, list
+// count types and legacy type names, and ignores comments and obj_info,
+// so differently encoded copies of the same geometry hash alike.
+func (p *PLY) ContentHash() [sha256.Size]byte {
+	h := sha256.New()
+	for _, elem := range p.Elements {
+		writeHashString(h, elem.Name)
+		writeHashInt(h, elem.Size)
+		writeHashInt(h, len(elem.Properties))
+		for _, prop := range elem.Properties {
+			writeHashString(h, prop.Name)
+			writeHashString(h, typeDecl(prop.IsList, normalizeType(prop.Type)))
+		}
+		for _, prop := range elem.Properties {
+			size := SizeOfType[prop.Type]
+			for i := 0; i < elem.Size; i++ {
+				b := prop.rowInOrder(i, binary.LittleEndian)
+				if prop.IsList && size > 0 {
+					writeHashInt(h, len(b)/size)
+				}
+				h.Write(b)
+			}
+		}
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+func writeHashInt(h hash.Hash, n int) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(n))
+	h.Write(b[:])
+}
+
+// writeHashString writes s length-prefixed so that names cannot run into
+// each other.
+func writeHashString(h hash.Hash, s string) {
+	writeHashInt(h, len(s))
+	h.Write([]byte(s))
+}
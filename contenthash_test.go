@@ -0,0 +1,45 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestContentHash(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	want := p.ContentHash()
+	for _, format := range []int{BinaryBigEndian, BinaryLittleEndian} {
+		var out bytes.Buffer
+		if e := Convert(strings.NewReader(testASCIIMesh), &out, format); e != nil {
+			t.Fatal(e)
+		}
+		q := new(PLY)
+		if e := q.Read(&out); e != nil {
+			t.Fatal(e)
+		}
+		if q.ContentHash() != want {
+			t.Errorf("format %d: expected the same hash as the ASCII file", format)
+		}
+	}
+	legacy := strings.Replace(testASCIIMesh, "list uchar int", "list int int32", 1)
+	legacy = strings.Replace(legacy, "comment made by hand\n", "", 1)
+	q := new(PLY)
+	if e := q.Read(strings.NewReader(legacy)); e != nil {
+		t.Fatal(e)
+	}
+	if q.ContentHash() != want {
+		t.Error("expected list count types, type names and comments to be ignored")
+	}
+	changed := strings.Replace(testASCIIMesh, "0 1 0 3", "0 1 0 4", 1)
+	q = new(PLY)
+	if e := q.Read(strings.NewReader(changed)); e != nil {
+		t.Fatal(e)
+	}
+	if q.ContentHash() == want {
+		t.Error("expected a changed value to change the hash")
+	}
+}
@@ -0,0 +1,32 @@
+package ply
+
+import (
+	"errors"
+	"io"
+)
+
+// ConvertTo changes the format the PLY is written in. Element data is kept
+// in its decoded byte order and re-encoded by Write.
+func (p *PLY) ConvertTo(format int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if _, e := formatName(int8(format)); e != nil {
+		return e
+	}
+	p.FileType = int8(format)
+	return nil
+}
+
+// Convert reads a PLY from r and writes it to w in targetFormat, preserving
+// elements, property types, comments and obj_info items.
+func Convert(r io.Reader, w io.Writer, targetFormat int) error {
+	p := new(PLY)
+	if e := p.Read(r); e != nil {
+		return errors.New("Convert: " + e.Error())
+	}
+	if e := p.ConvertTo(targetFormat); e != nil {
+		return e
+	}
+	return p.Write(w)
+}
@@ -0,0 +1,50 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testASCIIMesh = `ply
+format ascii 1.0
+comment made by hand
+element vertex 4
+property float x
+property float y
+property float z
+property double quality
+element face 2
+property list uchar int vertex_indices
+property short flags
+end_header
+0 0 0 0.5
+1 0 0 0.25
+1 1 0 -1e-05
+0 1 0 3
+3 0 1 2 -7
+4 0 1 2 3 300
+`
+
+func TestConvertRoundTrip(t *testing.T) {
+	for _, format := range []int{BinaryLittleEndian, BinaryBigEndian} {
+		var bin bytes.Buffer
+		if e := Convert(strings.NewReader(testASCIIMesh), &bin, format); e != nil {
+			t.Fatal(e)
+		}
+		var ascii bytes.Buffer
+		if e := Convert(&bin, &ascii, Ascii); e != nil {
+			t.Fatal(e)
+		}
+		if ascii.String() != testASCIIMesh {
+			t.Errorf("format %d: round trip mismatch:\n%s", format, ascii.String())
+		}
+	}
+}
+
+func TestConvertToInvalid(t *testing.T) {
+	p := new(PLY)
+	if e := p.ConvertTo(7); e == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,32 @@
+package ply
+
+// CropAABB returns a copy of p keeping the vertices inside the box from
+// min to max, bounds included. Faces crossing the box are removed and the
+// remaining faces re-indexed.
+func (p *PLY) CropAABB(min, max [3]float64) (*PLY, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	return p.filterVertices(func(i int) bool { return insideBox(pos[i], min, max) }), nil
+}
+
+// ClipPlane returns a copy of p keeping the vertices v with
+// dot(normal, v) + d >= 0, i.e. on the side the normal points to. Faces
+// crossing the plane are removed and the remaining faces re-indexed.
+func (p *PLY) ClipPlane(normal [3]float64, d float64) (*PLY, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	return p.filterVertices(func(i int) bool { return dot3(normal, pos[i])+d >= 0 }), nil
+}
+
+func insideBox(v, min, max [3]float64) bool {
+	for j := 0; j < 3; j++ {
+		if !(v[j] >= min[j] && v[j] <= max[j]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCrop(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	q, e := p.CropAABB([3]float64{0, 0, 0}, [3]float64{1, 0.5, 1})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if q.VerticesCount() != 2 || len(q.ReadFaces()) != 0 {
+		t.Errorf("expected 2 vertices and no faces, got %d and %v", q.VerticesCount(), q.ReadFaces())
+	}
+	// keep x <= 0.5, dropping vertices 1 and 2
+	q, e = p.ClipPlane([3]float64{-1, 0, 0}, 0.5)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if q.VerticesCount() != 2 || len(q.ReadFaces()) != 0 {
+		t.Errorf("unexpected clip result %d %v", q.VerticesCount(), q.ReadFaces())
+	}
+	q, _ = p.ClipPlane([3]float64{0, 0, 1}, 0)
+	if q.VerticesCount() != 4 || len(q.ReadFaces()) != 2 {
+		t.Error("expected vertices on the plane to be kept")
+	}
+	if p.VerticesCount() != 4 {
+		t.Error("expected the source to be unchanged")
+	}
+	if e := q.ScaleUnits(10); e != nil {
+		t.Fatal(e)
+	}
+	if x := p.findElement("vertex").findProperty("x"); x.float64At(1) != 1 {
+		t.Errorf("expected scaling the copy to leave the source, got x %g", x.float64At(1))
+	}
+}
@@ -0,0 +1,54 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// ComputeCurvature derives two features from the covariance of the k
+// nearest points of every vertex, itself included, and stores them in
+// float curvature and roughness properties, created if needed. Curvature
+// is the surface variation λ₀ / (λ₀ + λ₁ + λ₂) of the eigenvalues in
+// ascending order, 0 on a plane and at most 1/3. Roughness is the distance
+// of the vertex to the plane best fitting its neighbors. Vertices with an
+// invalid position or fewer than three neighbors get NaN.
+func (p *PLY) ComputeCurvature(k int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if k < 3 {
+		return errors.New("Curvature needs at least 3 neighbors")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	tree := NewKDTree(pos)
+	vertex := p.findElement("vertex")
+	curvature, e := vertex.ensureProperty("curvature", "float")
+	if e != nil {
+		return e
+	}
+	roughness, e := vertex.ensureProperty("roughness", "float")
+	if e != nil {
+		return e
+	}
+	for i, v := range pos {
+		c, r := math.NaN(), math.NaN()
+		if !isInvalidPoint(v) {
+			if idx := tree.KNN(v, k); len(idx) >= 3 {
+				mean, cov := covariance3(pos, idx)
+				values, vectors := symmetricEigen3(cov)
+				if sum := values[0] + values[1] + values[2]; sum > 0 {
+					c = math.Max(values[0], 0) / sum
+				} else {
+					c = 0
+				}
+				r = math.Abs(dot3(sub3(v, mean), normalize3(vectors[0])))
+			}
+		}
+		curvature.setFloat64At(i, c)
+		roughness.setFloat64At(i, r)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package ply
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeCurvature(t *testing.T) {
+	var pos [][3]float64
+	// a flat patch next to a bump
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			pos = append(pos, [3]float64{float64(i), float64(j), 0})
+		}
+	}
+	pos[55][2] = 1
+	pos = append(pos, [3]float64{math.NaN(), 0, 0})
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ComputeCurvature(9); e != nil {
+		t.Fatal(e)
+	}
+	vertex := p.GetVertices()
+	curvature, roughness := vertex.findProperty("curvature"), vertex.findProperty("roughness")
+	if c := curvature.float64At(11); c != 0 || roughness.float64At(11) != 0 {
+		t.Errorf("expected a flat neighborhood, got curvature %v", c)
+	}
+	if c, r := curvature.float64At(55), roughness.float64At(55); !(c > 0.05 && c <= 1.0/3) || !(r > 0.5) {
+		t.Errorf("expected the bump to stand out, got curvature %v and roughness %v", c, r)
+	}
+	if c := curvature.float64At(100); !math.IsNaN(c) {
+		t.Errorf("expected NaN for an invalid position, got %v", c)
+	}
+	if e := p.ComputeCurvature(9); e != nil || len(vertex.Properties) != 5 {
+		t.Errorf("expected the properties reused, got %d properties", len(vertex.Properties))
+	}
+	if e := p.ComputeCurvature(2); e == nil {
+		t.Error("expected an error for too few neighbors")
+	}
+}
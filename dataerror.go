@@ -0,0 +1,81 @@
+package ply
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DataError reports an ASCII data line that could not be decoded.
+type DataError struct {
+	Filename string
+	// Line and Column are 1-based; Column is 0 when the token could not
+	// be located in the line, e.g. with a custom Tokenizer.
+	Line, Column int
+	Element      string
+	// Row is the 0-based row of Element.
+	Row      int
+	Property string
+	// Token is the offending value, empty if values are missing.
+	Token string
+	Err   error
+}
+
+func (e *DataError) Error() string {
+	pos := e.Filename + ":" + strconv.Itoa(e.Line)
+	if e.Column > 0 {
+		pos += ":" + strconv.Itoa(e.Column)
+	}
+	msg := pos + ": " + e.Element + " row " + strconv.Itoa(e.Row)
+	if e.Property != "" {
+		msg += " property " + e.Property
+	}
+	if e.Token != "" {
+		msg += " value " + strconv.Quote(e.Token)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// tokenColumn returns the 1-based byte column of words[k] in line, the
+// column just past the last token if k is out of range, or 0 if the tokens
+// cannot be found in order.
+func tokenColumn(line string, words []string, k int) int {
+	from := 0
+	for j := 0; j < len(words) && j <= k; j++ {
+		at := strings.Index(line[from:], words[j])
+		if at < 0 {
+			return 0
+		}
+		if j == k {
+			return from + at + 1
+		}
+		from += at + len(words[j])
+	}
+	return from + 1
+}
+
+// propertyAt returns the name of the property that words[k] of an ASCII
+// row belongs to, or "" if it cannot be told.
+func (e *Element) propertyAt(words []string, k int) string {
+	at := 0
+	for _, prop := range e.Properties {
+		if at > k {
+			break
+		}
+		end := at + 1
+		if prop.IsList {
+			if at == k {
+				return prop.Name
+			}
+			n, err := strconv.Atoi(words[at])
+			if err != nil || n < 0 {
+				return ""
+			}
+			end += n
+		}
+		if k < end {
+			return prop.Name
+		}
+		at = end
+	}
+	return ""
+}
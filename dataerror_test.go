@@ -0,0 +1,36 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataError(t *testing.T) {
+	cases := []struct {
+		old, new string
+		line     int
+		column   int
+		row      int
+		property string
+		token    string
+	}{
+		{"1 1 0 -1e-05", "1 1 0 abc", 15, 7, 2, "quality", "abc"},
+		{"4 0 1 2 3 300", "4 0 1 x 3 300", 18, 7, 1, "vertex_indices", "x"},
+		{"3 0 1 2 -7", "  3 0 1", 17, 8, 0, "vertex_indices", ""},
+	}
+	for _, c := range cases {
+		src := strings.Replace(testASCIIMesh, c.old, c.new, 1)
+		e := new(PLY).Read(strings.NewReader(src))
+		de, ok := e.(*DataError)
+		if !ok {
+			t.Fatalf("expected *DataError, got %v", e)
+		}
+		if de.Line != c.line || de.Column != c.column || de.Row != c.row ||
+			de.Property != c.property || de.Token != c.token {
+			t.Errorf("unexpected error %+v", de)
+		}
+		if !strings.HasPrefix(de.Error(), "<reader>:") {
+			t.Errorf("unexpected message %q", de.Error())
+		}
+	}
+}
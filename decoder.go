@@ -0,0 +1,141 @@
+package ply
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// slabSize is the largest chunk a column of rows is decoded into, and the
+// size of the buffers a Decoder pools.
+const slabSize = 1 << 16
+
+// Decoder reads PLY files one after another, reusing its read buffers and,
+// once the PLYs it decoded are closed, their row buffers. A Decoder is not
+// safe for concurrent use, but its PLYs may be closed from any goroutine.
+type Decoder struct {
+	r    io.Reader
+	opts *LoadOptions
+	br   *bufio.Reader
+	gz   *gzip.Reader
+	gzbr *bufio.Reader
+	// pooled is false for the one-off decoders of PLY.Read
+	pooled bool
+	mu     sync.Mutex
+	free   [][]byte
+}
+
+// NewDecoder returns a Decoder reading from r with opts, which may be nil.
+func NewDecoder(r io.Reader, opts *LoadOptions) *Decoder {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+	return &Decoder{r: r, opts: opts, pooled: true}
+}
+
+// Reset makes d read the next file from r, keeping its buffers.
+func (d *Decoder) Reset(r io.Reader) {
+	d.r = r
+}
+
+// Decode reads one file into p, like PLY.ReadWithOptions. Closing p hands
+// its row buffers back to d for the files decoded next, so p's rows, also
+// those shared by copies derived from p, must not be used after Close.
+func (d *Decoder) Decode(p *PLY) error {
+	if p.filename == "" {
+		p.filename = "<reader>"
+	}
+	return p.decode(d)
+}
+
+// reader returns the buffered source, unpacking gzip input.
+func (d *Decoder) reader() (*bufio.Reader, error) {
+	size := d.opts.BufferSize
+	if size <= 0 {
+		size = 4096
+	}
+	if d.br == nil || d.br.Size() != size {
+		d.br = bufio.NewReaderSize(d.r, size)
+	} else {
+		d.br.Reset(d.r)
+	}
+	magic, _ := d.br.Peek(2)
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return d.br, nil
+	}
+	if d.gz == nil {
+		gz, e := gzip.NewReader(d.br)
+		if e != nil {
+			return nil, e
+		}
+		d.gz = gz
+	} else if e := d.gz.Reset(d.br); e != nil {
+		return nil, e
+	}
+	if d.gzbr == nil || d.gzbr.Size() != size {
+		d.gzbr = bufio.NewReaderSize(d.gz, size)
+	} else {
+		d.gzbr.Reset(d.gz)
+	}
+	return d.gzbr, nil
+}
+
+// alloc returns a chunk of n bytes for the rows of a column. When p was
+// decoded by a pooled Decoder, full chunks are slabs and small ones are
+// carved from a shared slab, so that Close can hand them back.
+func (p *PLY) alloc(n int) []byte {
+	d := p.decoder
+	if d == nil || !d.pooled || n != slabSize && n > slabSize/16 {
+		return make([]byte, n)
+	}
+	if n == slabSize {
+		return p.takeSlab()
+	}
+	if len(p.slab) < n {
+		p.slab = p.takeSlab()
+	}
+	b := p.slab[:n:n]
+	p.slab = p.slab[n:]
+	return b
+}
+
+// takeSlab returns a slab from p's decoder, reused when one is free.
+func (p *PLY) takeSlab() []byte {
+	d := p.decoder
+	d.mu.Lock()
+	var s []byte
+	if k := len(d.free); k > 0 {
+		s, d.free = d.free[k-1], d.free[:k-1]
+	} else {
+		s = make([]byte, slabSize)
+	}
+	d.mu.Unlock()
+	if p.slabs == nil {
+		p.slabs = &slabSet{}
+	}
+	p.slabs.slabs = append(p.slabs.slabs, s)
+	return s
+}
+
+// slabSet holds the slabs taken by one load. Plain copies of the PLY share
+// it, so it records being released to hand its slabs back only once.
+type slabSet struct {
+	slabs    [][]byte
+	released bool
+}
+
+// releaseSlabs hands p's slabs back to its decoder unless done before.
+func (p *PLY) releaseSlabs() {
+	if d, set := p.decoder, p.slabs; d != nil && d.pooled && set != nil {
+		d.mu.Lock()
+		if !set.released {
+			for _, s := range set.slabs {
+				d.free = append(d.free, s[:cap(s)])
+			}
+			set.released = true
+		}
+		d.mu.Unlock()
+	}
+	p.decoder, p.slabs, p.slab = nil, nil, nil
+}
@@ -0,0 +1,54 @@
+package ply
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	src := new(PLY)
+	if e := src.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	src.FileType = BinaryLittleEndian
+	var bin, gz bytes.Buffer
+	src.Write(&bin)
+	zw := gzip.NewWriter(&gz)
+	zw.Write(bin.Bytes())
+	zw.Close()
+
+	d := NewDecoder(bytes.NewReader(bin.Bytes()), &LoadOptions{BufferSize: 16})
+	p := new(PLY)
+	if e := d.Decode(p); e != nil {
+		t.Fatal(e)
+	}
+	if p.ContentHash() != src.ContentHash() {
+		t.Error("expected the decoded file to match")
+	}
+	br := d.br
+	c := *p
+	p.Close()
+	c.Close()
+	if len(d.free) != 1 {
+		t.Fatalf("expected closing p and a copy to return one slab, got %d", len(d.free))
+	}
+
+	for _, data := range [][]byte{gz.Bytes(), bin.Bytes()} {
+		d.Reset(bytes.NewReader(data))
+		q := new(PLY)
+		if e := d.Decode(q); e != nil {
+			t.Fatal(e)
+		}
+		if q.ContentHash() != src.ContentHash() || d.br != br || len(d.free) != 0 {
+			t.Error("expected the buffers to be reused")
+		}
+		q.Close()
+	}
+
+	d.Reset(strings.NewReader("ply\nformat ascii 1.0\nend"))
+	if e := d.Decode(new(PLY)); e == nil {
+		t.Error("expected an error for a truncated header")
+	}
+}
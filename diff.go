@@ -0,0 +1,268 @@
+package ply
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+type DiffOptions struct {
+	// Tolerance is the largest absolute difference of two values that
+	// still counts as equal.
+	Tolerance float64
+	// RelativeTolerance also counts values as equal whose difference is
+	// at most this fraction of the larger magnitude.
+	RelativeTolerance float64
+	// MaxValues bounds the differing values listed per property, 10 by
+	// default; all of them are counted. Negative lists none.
+	MaxValues int
+	// IgnoreComments leaves comments and obj_info items out.
+	IgnoreComments bool
+}
+
+// DiffReport lists the differences found by Diff. It holds only elements
+// and properties that differ.
+type DiffReport struct {
+	// Header lists differences of format, comments and obj_info items,
+	// e.g. "- comment made by hand" for a comment only a has.
+	Header   []string
+	Elements []ElementDiff
+}
+
+// ElementDiff describes an element that differs. Rows is -1 for the side
+// without the element.
+type ElementDiff struct {
+	Name       string
+	RowsA      int
+	RowsB      int
+	Properties []PropertyDiff
+}
+
+// PropertyDiff describes a property that differs in type or values. The
+// type is empty for the side without the property; lists read as e.g.
+// "list uchar int". Values are compared over the rows both sides have,
+// as numbers, so that a property converted to another type only differs
+// by its rounding.
+type PropertyDiff struct {
+	Name  string
+	TypeA string
+	TypeB string
+	// Count is the number of differing values, a list of another length
+	// counting once.
+	Count int
+	// MaxDelta is the largest difference of the differing values, leaving
+	// out values compared with NaN.
+	MaxDelta float64
+	Values   []ValueDiff
+}
+
+// ValueDiff is a differing value. Item indexes list items, -1 for scalars
+// and for lists of different lengths, which A and B then hold.
+type ValueDiff struct {
+	Row  int
+	Item int
+	A    float64
+	B    float64
+}
+
+// Equal reports whether no differences were found.
+func (d *DiffReport) Equal() bool {
+	return len(d.Header) == 0 && len(d.Elements) == 0
+}
+
+// Diff compares a with b: their format, comments and obj_info items, the
+// elements and properties they declare, matched by name, and every value
+// within the tolerances of opts. NaN equals NaN.
+func Diff(a, b *PLY, opts *DiffOptions) *DiffReport {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	o := *opts
+	if o.MaxValues == 0 {
+		o.MaxValues = 10
+	}
+	d := &DiffReport{}
+	fa, _ := formatName(a.FileType)
+	fb, _ := formatName(b.FileType)
+	if fa != fb {
+		d.Header = append(d.Header, "format "+fa+" != "+fb)
+	}
+	if !o.IgnoreComments {
+		d.Header = append(d.Header, diffStrings("comment", a.Comments, b.Comments)...)
+		d.Header = append(d.Header, diffStrings("obj_info", objInfoLines(a), objInfoLines(b))...)
+	}
+	for _, ea := range a.Elements {
+		eb := b.findElement(ea.Name)
+		if eb == nil {
+			d.Elements = append(d.Elements, ElementDiff{Name: ea.Name, RowsA: ea.Size, RowsB: -1})
+			continue
+		}
+		if ed := diffElement(ea, eb, &o); ed.RowsA != ed.RowsB || len(ed.Properties) > 0 {
+			d.Elements = append(d.Elements, ed)
+		}
+	}
+	for _, eb := range b.Elements {
+		if a.findElement(eb.Name) == nil {
+			d.Elements = append(d.Elements, ElementDiff{Name: eb.Name, RowsA: -1, RowsB: eb.Size})
+		}
+	}
+	return d
+}
+
+func diffElement(ea, eb *Element, opts *DiffOptions) ElementDiff {
+	ed := ElementDiff{Name: ea.Name, RowsA: ea.Size, RowsB: eb.Size}
+	rows := ea.Size
+	if eb.Size < rows {
+		rows = eb.Size
+	}
+	for _, pa := range ea.Properties {
+		pb := eb.findProperty(pa.Name)
+		if pb == nil {
+			ed.Properties = append(ed.Properties, PropertyDiff{Name: pa.Name, TypeA: propertyType(pa)})
+			continue
+		}
+		pd := PropertyDiff{Name: pa.Name, TypeA: propertyType(pa), TypeB: propertyType(pb)}
+		// add records a differing value, or list length if not value
+		add := func(v ValueDiff, value bool) {
+			pd.Count++
+			if delta := math.Abs(v.A - v.B); value && delta > pd.MaxDelta {
+				pd.MaxDelta = delta
+			}
+			if len(pd.Values) < opts.MaxValues {
+				pd.Values = append(pd.Values, v)
+			}
+		}
+		for i := 0; i < rows; i++ {
+			if !pa.IsList && !pb.IsList {
+				if va, vb := pa.float64At(i), pb.float64At(i); !opts.equal(va, vb) {
+					add(ValueDiff{i, -1, va, vb}, true)
+				}
+				continue
+			}
+			la, lb := diffItems(pa, i), diffItems(pb, i)
+			if len(la) != len(lb) {
+				add(ValueDiff{i, -1, float64(len(la)), float64(len(lb))}, false)
+				continue
+			}
+			for k := range la {
+				if !opts.equal(la[k], lb[k]) {
+					add(ValueDiff{i, k, la[k], lb[k]}, true)
+				}
+			}
+		}
+		if pd.Count > 0 || pd.TypeA != pd.TypeB {
+			ed.Properties = append(ed.Properties, pd)
+		}
+	}
+	for _, pb := range eb.Properties {
+		if ea.findProperty(pb.Name) == nil {
+			ed.Properties = append(ed.Properties, PropertyDiff{Name: pb.Name, TypeB: propertyType(pb)})
+		}
+	}
+	return ed
+}
+
+// diffItems returns row i of prop as a list, a scalar being a list of one.
+func diffItems(prop *Property, i int) []float64 {
+	if prop.IsList {
+		return prop.listFloat64At(i)
+	}
+	return []float64{prop.float64At(i)}
+}
+
+func (opts *DiffOptions) equal(a, b float64) bool {
+	if a == b || math.IsNaN(a) && math.IsNaN(b) {
+		return true
+	}
+	delta := math.Abs(a - b)
+	return delta <= opts.Tolerance || delta <= opts.RelativeTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func propertyType(prop *Property) string {
+	if prop.IsList {
+		return "list " + prop.ListSizeType + " " + prop.Type
+	}
+	return prop.Type
+}
+
+func objInfoLines(p *PLY) []string {
+	lines := make([]string, 0, len(p.ObjInfoItems))
+	for k, v := range p.ObjInfoItems {
+		lines = append(lines, k+" "+v)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// diffStrings lists the lines only a or only b holds, counting repeats.
+func diffStrings(kind string, a, b []string) []string {
+	count := make(map[string]int)
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		count[s]--
+	}
+	var diffs []string
+	for _, s := range a {
+		if count[s] > 0 {
+			count[s]--
+			diffs = append(diffs, "- "+kind+" "+s)
+		}
+	}
+	for _, s := range b {
+		if count[s] < 0 {
+			count[s]++
+			diffs = append(diffs, "+ "+kind+" "+s)
+		}
+	}
+	return diffs
+}
+
+// WriteText writes the differences, one per line: lines starting with -
+// and + hold what only a or only b has, like a unified diff.
+func (d *DiffReport) WriteText(w io.Writer) error {
+	var buf strings.Builder
+	for _, h := range d.Header {
+		fmt.Fprintln(&buf, h)
+	}
+	for _, ed := range d.Elements {
+		switch {
+		case ed.RowsB < 0:
+			fmt.Fprintf(&buf, "- element %s %d\n", ed.Name, ed.RowsA)
+			continue
+		case ed.RowsA < 0:
+			fmt.Fprintf(&buf, "+ element %s %d\n", ed.Name, ed.RowsB)
+			continue
+		case ed.RowsA != ed.RowsB:
+			fmt.Fprintf(&buf, "element %s: %d != %d rows\n", ed.Name, ed.RowsA, ed.RowsB)
+		}
+		for _, pd := range ed.Properties {
+			switch {
+			case pd.TypeB == "":
+				fmt.Fprintf(&buf, "- property %s.%s %s\n", ed.Name, pd.Name, pd.TypeA)
+				continue
+			case pd.TypeA == "":
+				fmt.Fprintf(&buf, "+ property %s.%s %s\n", ed.Name, pd.Name, pd.TypeB)
+				continue
+			case pd.TypeA != pd.TypeB:
+				fmt.Fprintf(&buf, "property %s.%s: type %s != %s\n", ed.Name, pd.Name, pd.TypeA, pd.TypeB)
+			}
+			if pd.Count == 0 {
+				continue
+			}
+			fmt.Fprintf(&buf, "property %s.%s: %d values differ, max delta %g\n", ed.Name, pd.Name, pd.Count, pd.MaxDelta)
+			for _, v := range pd.Values {
+				if v.Item < 0 {
+					fmt.Fprintf(&buf, "\trow %d: %g != %g\n", v.Row, v.A, v.B)
+				} else {
+					fmt.Fprintf(&buf, "\trow %d item %d: %g != %g\n", v.Row, v.Item, v.A, v.B)
+				}
+			}
+		}
+	}
+	_, e := io.WriteString(w, buf.String())
+	return e
+}
@@ -0,0 +1,116 @@
+package ply
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	read := func(src string) *PLY {
+		p := new(PLY)
+		if e := p.Read(strings.NewReader(src)); e != nil {
+			t.Fatal(e)
+		}
+		return p
+	}
+	a := read(`ply
+format ascii 1.0
+comment shared
+comment only a
+element vertex 3
+property float x
+property float y
+property uchar red
+element face 2
+property list uchar int vertex_indices
+element edge 0
+property int vertex1
+end_header
+0 0 10
+1 0 20
+0 1 30
+3 0 1 2
+3 0 2 1
+`)
+	b := read(`ply
+format ascii 1.0
+comment shared
+element vertex 3
+property double x
+property float y
+property float z
+element face 2
+property list uchar int vertex_indices
+end_header
+0 0 0
+1.0001 0 0
+0 nan 0
+3 0 1 2
+4 0 2 1 0
+`)
+	if d := Diff(a, a, nil); !d.Equal() {
+		t.Errorf("expected no differences, got %+v", d)
+	}
+	d := Diff(a, b, &DiffOptions{Tolerance: 1e-3})
+	if len(d.Header) != 1 || d.Header[0] != "- comment only a" {
+		t.Errorf("unexpected header differences %q", d.Header)
+	}
+	if len(d.Elements) != 3 {
+		t.Fatalf("expected vertex, face and edge differences, got %+v", d.Elements)
+	}
+	vertex := d.Elements[0]
+	if vertex.Name != "vertex" || len(vertex.Properties) != 4 {
+		t.Fatalf("unexpected vertex differences %+v", vertex)
+	}
+	// x differs in type only within the tolerance
+	if x := vertex.Properties[0]; x.TypeA != "float" || x.TypeB != "double" || x.Count != 0 {
+		t.Errorf("unexpected x difference %+v", x)
+	}
+	if y := vertex.Properties[1]; y.Count != 1 || y.Values[0].Row != 2 || y.Values[0].A != 1 || !math.IsNaN(y.Values[0].B) {
+		t.Errorf("unexpected y difference %+v", y)
+	}
+	if red, z := vertex.Properties[2], vertex.Properties[3]; red.TypeB != "" || z.TypeA != "" || z.TypeB != "float" {
+		t.Errorf("unexpected missing properties %+v %+v", red, z)
+	}
+	if h := Diff(a, b, &DiffOptions{IgnoreComments: true}).Header; len(h) != 0 {
+		t.Errorf("expected comments to be ignored, got %q", h)
+	}
+	face := d.Elements[1]
+	if face.Name != "face" || len(face.Properties) != 1 {
+		t.Fatalf("unexpected face differences %+v", face)
+	}
+	if f := face.Properties[0]; f.Count != 1 || f.Values[0] != (ValueDiff{1, -1, 3, 4}) || f.MaxDelta != 0 {
+		t.Errorf("unexpected face difference %+v", f)
+	}
+	if edge := d.Elements[2]; edge.Name != "edge" || edge.RowsA != 0 || edge.RowsB != -1 {
+		t.Errorf("unexpected edge difference %+v", edge)
+	}
+	d = Diff(a, b, &DiffOptions{RelativeTolerance: 1e-3, MaxValues: -1})
+	if x := d.Elements[0].Properties[0]; x.Count != 0 {
+		t.Errorf("expected x within the relative tolerance, got %+v", x)
+	}
+	d = Diff(a, b, nil)
+	if x := d.Elements[0].Properties[0]; x.Count != 1 || math.Abs(x.MaxDelta-1e-4) > 1e-7 {
+		t.Errorf("expected x to differ without tolerance, got %+v", x)
+	}
+	var buf bytes.Buffer
+	if e := d.WriteText(&buf); e != nil {
+		t.Fatal(e)
+	}
+	for _, want := range []string{
+		"- comment only a\n",
+		"property vertex.x: type float != double\n",
+		"property vertex.y: 1 values differ",
+		"\trow 2: 1 != NaN\n",
+		"- property vertex.red uchar\n",
+		"+ property vertex.z float\n",
+		"\trow 1: 3 != 4\n",
+		"- element edge 0\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
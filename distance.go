@@ -0,0 +1,123 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// DistanceStats summarizes the distances of the vertices of a PLY to a
+// reference. Vertices with an invalid position are left out.
+type DistanceStats struct {
+	Count int
+	Mean  float64
+	RMS   float64
+	// Hausdorff is the largest distance, the directed Hausdorff distance
+	// to the reference. The symmetric one is the larger of both
+	// directions.
+	Hausdorff float64
+}
+
+// CloudToCloudDistance stores the distance of every vertex of p to the
+// nearest vertex of ref in a float property, e.g. "distance", created if
+// needed, and summarizes them. Vertices with an invalid position get NaN.
+func (p *PLY) CloudToCloudDistance(ref *PLY, property string) (DistanceStats, error) {
+	points, e := ref.vertexPositions()
+	if e != nil {
+		return DistanceStats{}, e
+	}
+	tree := NewKDTree(points)
+	if tree.Len() == 0 {
+		return DistanceStats{}, errors.New("Reference has no valid vertices")
+	}
+	return p.storeDistances(property, func(v [3]float64) float64 {
+		_, d := tree.NearestNeighbor(v)
+		return d
+	})
+}
+
+// CloudToMeshDistance stores the distance of every vertex of p to the
+// nearest point on the faces of ref, e.g. a CAD model, like
+// CloudToCloudDistance. Polygons are fan-triangulated.
+func (p *PLY) CloudToMeshDistance(ref *PLY, property string) (DistanceStats, error) {
+	points, e := ref.vertexPositions()
+	if e != nil {
+		return DistanceStats{}, e
+	}
+	faces, e := ref.faceIndices()
+	if e != nil {
+		return DistanceStats{}, e
+	}
+	var tris [][3]int
+	var centroids [][3]float64
+	// reach is the largest distance of a corner to its centroid
+	reach := 0.0
+	for _, f := range faces {
+		for _, t := range fanTriangles(f) {
+			if !validTriangle(t, points) {
+				continue
+			}
+			a, b, c := points[t[0]], points[t[1]], points[t[2]]
+			if isInvalidPoint(a) || isInvalidPoint(b) || isInvalidPoint(c) {
+				continue
+			}
+			m := scale3(add3(a, add3(b, c)), 1.0/3)
+			for _, v := range [3][3]float64{a, b, c} {
+				reach = math.Max(reach, length3(sub3(v, m)))
+			}
+			tris = append(tris, t)
+			centroids = append(centroids, m)
+		}
+	}
+	if len(tris) == 0 {
+		return DistanceStats{}, errors.New("Reference has no valid faces")
+	}
+	tree := NewKDTree(centroids)
+	distance := func(v [3]float64, k int) float64 {
+		t := tris[k]
+		return length3(sub3(v, closestOnTriangle(v, points[t[0]], points[t[1]], points[t[2]])))
+	}
+	return p.storeDistances(property, func(v [3]float64) float64 {
+		// a triangle closer than the one of the nearest centroid has its
+		// centroid within reach of that distance
+		k, _ := tree.NearestNeighbor(v)
+		best := distance(v, k)
+		tree.within(v, best+reach, func(k int) {
+			best = math.Min(best, distance(v, k))
+		})
+		return best
+	})
+}
+
+// storeDistances stores distance of every valid vertex position in the
+// float property and summarizes them.
+func (p *PLY) storeDistances(property string, distance func(v [3]float64) float64) (DistanceStats, error) {
+	if p.frozen {
+		return DistanceStats{}, ErrFrozen
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return DistanceStats{}, e
+	}
+	prop, e := p.findElement("vertex").ensureProperty(property, "float")
+	if e != nil {
+		return DistanceStats{}, e
+	}
+	var stats DistanceStats
+	sum, sum2 := 0.0, 0.0
+	for i, v := range pos {
+		d := math.NaN()
+		if !isInvalidPoint(v) {
+			d = distance(v)
+			stats.Count++
+			sum += d
+			sum2 += d * d
+			stats.Hausdorff = math.Max(stats.Hausdorff, d)
+		}
+		prop.setFloat64At(i, d)
+	}
+	if stats.Count > 0 {
+		stats.Mean = sum / float64(stats.Count)
+		stats.RMS = math.Sqrt(sum2 / float64(stats.Count))
+	}
+	return stats, nil
+}
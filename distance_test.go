@@ -0,0 +1,75 @@
+package ply
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCloudToCloudDistance(t *testing.T) {
+	p, ref := new(PLY), new(PLY)
+	if e := p.SetPositions([][3]float64{{0, 0, 1}, {3, 0, 0}, {math.NaN(), 0, 0}}); e != nil {
+		t.Fatal(e)
+	}
+	if e := ref.SetPositions([][3]float64{{0, 0, 0}, {1, 0, 0}}); e != nil {
+		t.Fatal(e)
+	}
+	stats, e := p.CloudToCloudDistance(ref, "distance")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if stats.Count != 2 || stats.Mean != 1.5 || stats.Hausdorff != 2 || stats.RMS != math.Sqrt(2.5) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	d := p.findElement("vertex").findProperty("distance")
+	if d.Type != "float" || d.float64At(0) != 1 || d.float64At(1) != 2 || !math.IsNaN(d.float64At(2)) {
+		t.Errorf("unexpected distances %v %v %v", d.float64At(0), d.float64At(1), d.float64At(2))
+	}
+}
+
+func TestCloudToMeshDistance(t *testing.T) {
+	ref := gridMesh(t, 4)
+	pos, e := ref.vertexPositions()
+	if e != nil {
+		t.Fatal(e)
+	}
+	b := positionBounds(pos)
+	mid := scale3(add3(b.Min, b.Max), 0.5)
+	p := new(PLY)
+	// above the middle of the grid, beside an edge and off a corner
+	points := [][3]float64{
+		add3(mid, [3]float64{0, 0, 0.5}),
+		{b.Max[0] + 2, mid[1], 0},
+		{b.Min[0] - 3, b.Min[1] - 4, 0},
+	}
+	if e := p.SetPositions(points); e != nil {
+		t.Fatal(e)
+	}
+	stats, e := p.CloudToMeshDistance(ref, "deviation")
+	if e != nil {
+		t.Fatal(e)
+	}
+	d := p.findElement("vertex").findProperty("deviation")
+	for i, want := range []float64{0.5, 2, 5} {
+		if got := d.float64At(i); math.Abs(got-want) > 1e-6 {
+			t.Errorf("expected distance %v for point %d, got %v", want, i, got)
+		}
+	}
+	if stats.Count != 3 || math.Abs(stats.Hausdorff-5) > 1e-6 || math.Abs(stats.Mean-2.5) > 1e-6 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	// sampling the mesh itself gives no deviation
+	sampled, e := ref.SampleSurface(200)
+	if e != nil {
+		t.Fatal(e)
+	}
+	stats, e = sampled.CloudToMeshDistance(ref, "distance")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if stats.Hausdorff > 1e-6 {
+		t.Errorf("expected samples on the mesh, got %+v", stats)
+	}
+	if _, e := p.CloudToMeshDistance(p, "distance"); e == nil {
+		t.Error("expected an error for a reference without faces")
+	}
+}
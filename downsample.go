@@ -0,0 +1,283 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// Aggregations combining the values of the points in one voxel.
+const (
+	AggregateFirst = iota
+	AggregateMean
+	AggregateMedian
+	AggregateMax
+	// AggregateMode picks the most frequent value, the smallest on ties,
+	// for classification labels.
+	AggregateMode
+)
+
+type VoxelOptions struct {
+	// Aggregation maps scalar property names, including x, y and z, to
+	// their aggregation; others use Default.
+	Aggregation map[string]int
+	Default     int
+}
+
+func (opts *VoxelOptions) aggregation(name string) int {
+	if a, ok := opts.Aggregation[name]; ok {
+		return a
+	}
+	return opts.Default
+}
+
+// VoxelDownsample returns a copy of p keeping, for each cube of side
+// cellSize, the first vertex inside it with all its properties. Faces
+// referencing a dropped vertex are removed.
+func (p *PLY) VoxelDownsample(cellSize float64) (*PLY, error) {
+	return p.VoxelDownsampleWithOptions(cellSize, nil)
+}
+
+// VoxelDownsampleWithOptions is VoxelDownsample with the scalar properties
+// of each kept vertex aggregated over its voxel, e.g. mean positions and
+// colors with the mode of a class label. Lists keep the first vertex's.
+func (p *PLY) VoxelDownsampleWithOptions(cellSize float64, opts *VoxelOptions) (*PLY, error) {
+	if opts == nil {
+		opts = &VoxelOptions{}
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	groups, e := voxelGroups(pos, cellSize)
+	if e != nil {
+		return nil, e
+	}
+	rows := make([]int, len(groups))
+	for k, g := range groups {
+		rows[k] = g[0]
+	}
+	q := p.keepVertexRows(rows)
+	src := p.findElement("vertex")
+	for _, prop := range q.findElement("vertex").Properties {
+		mode := opts.aggregation(prop.Name)
+		if prop.IsList || mode == AggregateFirst {
+			continue
+		}
+		// the aggregate replaces the value of the first vertex of each voxel
+		values := src.findProperty(prop.Name)
+		for k, g := range groups {
+			v := make([]float64, len(g))
+			for n, i := range g {
+				v[n] = values.float64At(i)
+			}
+			prop.Data[k] = encodeFloat64(aggregate(v, mode), prop.Type, prop.byteOrder())
+		}
+	}
+	return q, nil
+}
+
+// RandomSample returns a copy of p keeping a random fraction of the
+// vertices in their original order. The same seed selects the same rows.
+func (p *PLY) RandomSample(fraction float64, seed int64) (*PLY, error) {
+	n := p.VerticesCount()
+	count, e := sampleCount(n, fraction)
+	if e != nil {
+		return nil, e
+	}
+	return p.RandomSampleN(count, seed)
+}
+
+// RandomSampleN is like RandomSample but keeps count vertices, or all of
+// them if there are fewer.
+func (p *PLY) RandomSampleN(count int, seed int64) (*PLY, error) {
+	if p.findElement("vertex") == nil {
+		return nil, errors.New("No vertex element")
+	}
+	return p.keepVertexRows(sampleRows(p.VerticesCount(), count, seed)), nil
+}
+
+// VoxelDownsample is the Cloud counterpart of PLY.VoxelDownsample.
+func (c *Cloud) VoxelDownsample(cellSize float64) (*Cloud, error) {
+	return c.VoxelDownsampleWithOptions(cellSize, nil)
+}
+
+// VoxelDownsampleWithOptions is the Cloud counterpart of
+// PLY.VoxelDownsampleWithOptions.
+func (c *Cloud) VoxelDownsampleWithOptions(cellSize float64, opts *VoxelOptions) (*Cloud, error) {
+	if opts == nil {
+		opts = &VoxelOptions{}
+	}
+	pos := make([][3]float64, len(c.Positions))
+	for i, v := range c.Positions {
+		pos[i] = v
+	}
+	groups, e := voxelGroups(pos, cellSize)
+	if e != nil {
+		return nil, e
+	}
+	rows := make([]int, len(groups))
+	for k, g := range groups {
+		rows[k] = g[0]
+	}
+	sub := c.selectRows(rows)
+	combine := func(name string, value func(i int) float64, set func(k int, v float64)) {
+		mode := opts.aggregation(name)
+		if mode == AggregateFirst {
+			return
+		}
+		for k, g := range groups {
+			v := make([]float64, len(g))
+			for n, i := range g {
+				v[n] = value(i)
+			}
+			set(k, aggregate(v, mode))
+		}
+	}
+	for j, name := range []string{"x", "y", "z"} {
+		j := j
+		combine(name, func(i int) float64 { return c.Positions[i][j] },
+			func(k int, v float64) { sub.Positions[k][j] = v })
+	}
+	for n, a := range c.Attributes {
+		a, sa := a, sub.Attributes[n]
+		combine(a.Name, func(i int) float64 { return a.Values[i] },
+			func(k int, v float64) { sa.Values[k] = v })
+	}
+	return sub, nil
+}
+
+// RandomSample is the Cloud counterpart of PLY.RandomSample.
+func (c *Cloud) RandomSample(fraction float64, seed int64) (*Cloud, error) {
+	count, e := sampleCount(c.Len(), fraction)
+	if e != nil {
+		return nil, e
+	}
+	return c.RandomSampleN(count, seed), nil
+}
+
+// RandomSampleN is the Cloud counterpart of PLY.RandomSampleN.
+func (c *Cloud) RandomSampleN(count int, seed int64) *Cloud {
+	return c.selectRows(sampleRows(c.Len(), count, seed))
+}
+
+// keepVertexRows returns a copy of p holding the given ascending vertex
+// rows.
+func (p *PLY) keepVertexRows(rows []int) *PLY {
+	keep := make(map[int]bool, len(rows))
+	for _, i := range rows {
+		keep[i] = true
+	}
+	return p.filterVertices(func(i int) bool { return keep[i] })
+}
+
+// selectRows returns a copy of c holding the given points.
+func (c *Cloud) selectRows(rows []int) *Cloud {
+	sub := &Cloud{Positions: make([]Vec3, len(rows))}
+	for n, i := range rows {
+		sub.Positions[n] = c.Positions[i]
+	}
+	for _, a := range c.Attributes {
+		sa := &Attribute{Name: a.Name, Type: a.Type, Values: make([]float64, len(rows))}
+		for n, i := range rows {
+			sa.Values[n] = a.Values[i]
+		}
+		sub.Attributes = append(sub.Attributes, sa)
+	}
+	return sub
+}
+
+// voxelKey returns the integer cell holding v.
+func voxelKey(v [3]float64, cellSize float64) [3]int64 {
+	return [3]int64{
+		int64(math.Floor(v[0] / cellSize)),
+		int64(math.Floor(v[1] / cellSize)),
+		int64(math.Floor(v[2] / cellSize)),
+	}
+}
+
+// voxelGroups returns the rows in each occupied cell, ascending and
+// ordered by their first row.
+func voxelGroups(pos [][3]float64, cellSize float64) ([][]int, error) {
+	if !(cellSize > 0) {
+		return nil, errors.New("Cell size must be positive")
+	}
+	index := make(map[[3]int64]int)
+	var groups [][]int
+	for i, v := range pos {
+		key := voxelKey(v, cellSize)
+		k, ok := index[key]
+		if !ok {
+			k = len(groups)
+			index[key] = k
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], i)
+	}
+	return groups, nil
+}
+
+// aggregate combines values by mode; it may reorder values.
+func aggregate(values []float64, mode int) float64 {
+	switch mode {
+	case AggregateMean:
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	case AggregateMedian:
+		sort.Float64s(values)
+		n := len(values)
+		if n%2 == 1 {
+			return values[n/2]
+		}
+		return (values[n/2-1] + values[n/2]) / 2
+	case AggregateMax:
+		max := values[0]
+		for _, v := range values[1:] {
+			if v > max {
+				max = v
+			}
+		}
+		return max
+	case AggregateMode:
+		sort.Float64s(values)
+		best, bestCount := values[0], 0
+		for i := 0; i < len(values); {
+			j := i
+			for j < len(values) && values[j] == values[i] {
+				j++
+			}
+			if j-i > bestCount {
+				best, bestCount = values[i], j-i
+			}
+			i = j
+		}
+		return best
+	}
+	return values[0]
+}
+
+func sampleCount(n int, fraction float64) (int, error) {
+	if !(fraction >= 0 && fraction <= 1) {
+		return 0, errors.New("Sample fraction " +
+			strconv.FormatFloat(fraction, 'g', -1, 64) + " outside [0, 1]")
+	}
+	return int(fraction*float64(n) + 0.5), nil
+}
+
+// sampleRows picks count of n rows at random and returns them ascending.
+func sampleRows(n, count int, seed int64) []int {
+	if count > n {
+		count = n
+	}
+	if count < 0 {
+		count = 0
+	}
+	rows := rand.New(rand.NewSource(seed)).Perm(n)[:count]
+	sort.Ints(rows)
+	return rows
+}
@@ -0,0 +1,109 @@
+package ply
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDownsample(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("ply\nformat ascii 1.0\nelement vertex 40\nproperty float x\nproperty float y\nproperty float z\nproperty ushort intensity\nend_header\n")
+	for i := 0; i < 40; i++ {
+		// four points per unit cell along x
+		src.WriteString(strconv.FormatFloat(float64(i)/4, 'f', -1, 64) + " 0.5 0.5 " + strconv.Itoa(i) + "\n")
+	}
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src.String())); e != nil {
+		t.Fatal(e)
+	}
+	q, e := p.VoxelDownsample(1)
+	if e != nil {
+		t.Fatal(e)
+	}
+	intensity := q.Elements[0].findProperty("intensity")
+	if q.VerticesCount() != 10 || intensity.float64At(1) != 4 {
+		t.Errorf("expected the first point of 10 cells, got %d", q.VerticesCount())
+	}
+	if _, e := p.VoxelDownsample(0); e == nil {
+		t.Error("expected an error for a zero cell size")
+	}
+
+	a, _ := p.RandomSample(0.25, 7)
+	b, _ := p.RandomSampleN(10, 7)
+	if a.VerticesCount() != 10 || a.ContentHash() != b.ContentHash() {
+		t.Error("expected the same seed to select the same 10 rows")
+	}
+	x := a.ReadVertices()[0]
+	for i := 1; i < len(x); i++ {
+		if x[i] <= x[i-1] {
+			t.Fatal("expected rows in their original order")
+		}
+	}
+	if _, e := p.RandomSample(1.5, 7); e == nil {
+		t.Error("expected an error for a fraction above 1")
+	}
+
+	c, e := NewCloudFromPLY(p)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vc, _ := c.VoxelDownsample(2)
+	if vc.Len() != 5 || vc.Attribute("intensity").Values[1] != 8 {
+		t.Errorf("unexpected cloud downsample %v", vc.Positions)
+	}
+	if rc, _ := c.RandomSample(0.5, 1); rc.Len() != 20 || len(rc.Attribute("intensity").Values) != 20 {
+		t.Error("expected attributes to be sampled with the points")
+	}
+}
+
+func TestVoxelAggregation(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 5
+property float x
+property float y
+property float z
+property uchar class
+property float intensity
+end_header
+0.1 0 0 2 1
+0.3 0 0 5 9
+0.8 0 0 5 2
+0.9 0 0 2 4
+1.5 0 0 7 3
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	opts := &VoxelOptions{
+		Aggregation: map[string]int{"class": AggregateMode, "intensity": AggregateMedian},
+		Default:     AggregateMean,
+	}
+	q, e := p.VoxelDownsampleWithOptions(1, opts)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vertex := q.findElement("vertex")
+	x, class, intensity := vertex.findProperty("x"), vertex.findProperty("class"), vertex.findProperty("intensity")
+	if q.VerticesCount() != 2 || float32(x.float64At(0)) != 0.525 || class.float64At(0) != 2 ||
+		intensity.float64At(0) != 3 || x.float64At(1) != 1.5 {
+		t.Errorf("unexpected aggregation x=%v class=%v intensity=%v",
+			x.float64At(0), class.float64At(0), intensity.float64At(0))
+	}
+	if p.findElement("vertex").findProperty("class").float64At(0) != 2 ||
+		p.findElement("vertex").findProperty("x").float64At(0) != float64(float32(0.1)) {
+		t.Error("expected the source to be unchanged")
+	}
+	opts.Aggregation["intensity"] = AggregateMax
+	c, _ := NewCloudFromPLY(p)
+	vc, e := c.VoxelDownsampleWithOptions(1, opts)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if float32(vc.Positions[0][0]) != 0.525 || vc.Attribute("intensity").Values[0] != 9 ||
+		vc.Attribute("class").Values[0] != 2 {
+		t.Errorf("unexpected cloud aggregation %v", vc.Positions[0])
+	}
+}
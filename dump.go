@@ -0,0 +1,83 @@
+package ply
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// maximum number of list items shown per cell before eliding
+const dumpListItems = 8
+
+// Dump writes a table of the element's rows with one column per property.
+// When the element has more than 2*maxRows rows only the first and last
+// maxRows are shown; maxRows <= 0 dumps every row.
+func (e *Element) Dump(w io.Writer, maxRows int) error {
+	fmt.Fprintf(w, "element %s (%d rows)\n", e.Name, e.Size)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	cols := []string{"#"}
+	types := []string{""}
+	for _, prop := range e.Properties {
+		cols = append(cols, prop.Name)
+		if prop.IsList {
+			types = append(types, "list "+prop.ListSizeType+" "+prop.Type)
+		} else {
+			types = append(types, prop.Type)
+		}
+	}
+	fmt.Fprintln(tw, strings.Join(cols, "\t")+"\t")
+	fmt.Fprintln(tw, strings.Join(types, "\t")+"\t")
+	if maxRows <= 0 || e.Size <= 2*maxRows {
+		for i := 0; i < e.Size; i++ {
+			dumpRow(tw, e, i)
+		}
+	} else {
+		for i := 0; i < maxRows; i++ {
+			dumpRow(tw, e, i)
+		}
+		dots := make([]string, len(cols))
+		for i := range dots {
+			dots[i] = "..."
+		}
+		fmt.Fprintln(tw, strings.Join(dots, "\t")+"\t")
+		for i := e.Size - maxRows; i < e.Size; i++ {
+			dumpRow(tw, e, i)
+		}
+	}
+	return tw.Flush()
+}
+
+func dumpRow(w io.Writer, e *Element, i int) {
+	cells := []string{itoa(i)}
+	for _, prop := range e.Properties {
+		cells = append(cells, dumpCell(prop, i))
+	}
+	fmt.Fprintln(w, strings.Join(cells, "\t")+"\t")
+}
+
+func dumpCell(prop *Property, i int) string {
+	data := prop.row(i)
+	if data == nil {
+		return "-"
+	}
+	size := SizeOfType[prop.Type]
+	if size == 0 {
+		return "?"
+	}
+	if !prop.IsList {
+		if len(data) != size {
+			return "?"
+		}
+		return formatValue(data, prop.Type, prop.byteOrder())
+	}
+	n := len(data) / size
+	items := make([]string, 0, n)
+	for j := 0; j < n && j < dumpListItems; j++ {
+		items = append(items, formatValue(data[j*size:(j+1)*size], prop.Type, prop.byteOrder()))
+	}
+	if n > dumpListItems {
+		items = append(items, "...")
+	}
+	return "[" + strings.Join(items, " ") + "]"
+}
@@ -0,0 +1,31 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestElementDump(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIVertices)); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := p.Elements[0].Dump(&buf, 1); e != nil {
+		t.Fatal(e)
+	}
+	out := buf.String()
+	t.Log("\n" + out)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 lines, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "element vertex (3 rows)") {
+		t.Errorf("unexpected title %q", lines[0])
+	}
+	if !strings.Contains(lines[3], "10") || !strings.Contains(lines[4], "...") ||
+		!strings.Contains(lines[5], "-1.25") || strings.Contains(out, "0.5") {
+		t.Errorf("unexpected rows:\n%s", out)
+	}
+}
@@ -0,0 +1,94 @@
+package ply
+
+import "errors"
+
+// faceEdges returns the undirected edges of faces, each with its smaller
+// vertex first, in order of first use, and how many faces use each.
+// Edges from a vertex to itself are skipped.
+func faceEdges(faces [][]int) ([][2]int, map[[2]int]int) {
+	var edges [][2]int
+	counts := make(map[[2]int]int)
+	for _, f := range faces {
+		for k := range f {
+			a, b := f[k], f[(k+1)%len(f)]
+			if a == b {
+				continue
+			}
+			if a > b {
+				a, b = b, a
+			}
+			edge := [2]int{a, b}
+			if counts[edge] == 0 {
+				edges = append(edges, edge)
+			}
+			counts[edge]++
+		}
+	}
+	return edges, counts
+}
+
+// Wireframe derives the unique edges of the faces and stores them as an
+// edge element with int vertex1 and vertex2 properties, replacing any
+// existing edge element. dropFaces removes the face element, leaving a
+// lightweight wireframe. It returns the number of edges.
+func (p *PLY) Wireframe(dropFaces bool) (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return 0, e
+	}
+	edges, _ := faceEdges(faces)
+	elem := &Element{Name: "edge", Size: len(edges)}
+	v1, v2 := newProperty("vertex1", "int", len(edges)), newProperty("vertex2", "int", len(edges))
+	v2.pos = 1
+	for i, edge := range edges {
+		v1.setFloat64At(i, float64(edge[0]))
+		v2.setFloat64At(i, float64(edge[1]))
+	}
+	elem.Properties = []*Property{v1, v2}
+	var elements []*Element
+	for _, other := range p.Elements {
+		switch {
+		case other.Name == "edge":
+		case other.Name == "face" && dropFaces:
+			elements = append(elements, elem)
+		case other.Name == "face":
+			elements = append(elements, other, elem)
+		default:
+			elements = append(elements, other)
+		}
+	}
+	if len(elements) == 0 {
+		return 0, errors.New("No face element")
+	}
+	p.Elements = elements
+	return len(edges), nil
+}
+
+// ReadEdges returns the vertex pairs of the edge element, from vertex1 and
+// vertex2 or else its first two scalar properties, or nil if there is no
+// such element.
+func (p *PLY) ReadEdges() [][2]int {
+	elem := p.findElement("edge")
+	if elem == nil {
+		return nil
+	}
+	props := elem.scalarProperties("vertex1", "vertex2")
+	if props == nil {
+		for _, prop := range elem.Properties {
+			if !prop.IsList && len(props) < 2 {
+				props = append(props, prop)
+			}
+		}
+		if len(props) < 2 {
+			return nil
+		}
+	}
+	edges := make([][2]int, elem.Size)
+	for i := range edges {
+		edges[i] = [2]int{int(props[0].float64At(i)), int(props[1].float64At(i))}
+	}
+	return edges
+}
@@ -0,0 +1,53 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWireframe(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	n, e := p.Wireframe(false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	edge := p.findElement("edge")
+	if n != 5 || edge == nil || edge.Size != 5 || p.Elements[2] != edge {
+		t.Fatalf("expected 5 edges after the faces, got %d", n)
+	}
+	want := [][2]float64{{0, 1}, {1, 2}, {0, 2}, {2, 3}, {0, 3}}
+	for i, w := range want {
+		if a, b := edge.findProperty("vertex1").float64At(i), edge.findProperty("vertex2").float64At(i); a != w[0] || b != w[1] {
+			t.Errorf("edge %d: got %v %v, want %v", i, a, b, w)
+		}
+	}
+
+	if edges := p.ReadEdges(); len(edges) != 5 || edges[4] != [2]int{0, 3} {
+		t.Errorf("unexpected edges %v", edges)
+	}
+
+	if n, e = p.Wireframe(true); e != nil || n != 5 {
+		t.Fatal("expected rebuilding to replace the edge element", e)
+	}
+	if p.findElement("face") != nil || len(p.Elements) != 2 {
+		t.Error("expected the faces to be dropped")
+	}
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&out); e != nil {
+		t.Fatal(e)
+	}
+	if q.ContentHash() != p.ContentHash() {
+		t.Error("expected the wireframe to survive a round trip")
+	}
+	if _, e := p.Wireframe(false); e == nil {
+		t.Error("expected an error without faces")
+	}
+}
@@ -0,0 +1,317 @@
+package ply
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// UnknownCount marks an element of an Encoder header whose row count is
+// only known once its rows are written.
+const UnknownCount = -1
+
+// countWidth is the width of the space-padded count written for elements
+// of unknown size, enough for any uint32. Padding goes before the digits,
+// where header parsers see it as separating whitespace.
+const countWidth = 10
+
+type EncoderOptions struct {
+	// ASCII controls the formatting of ASCII output.
+	ASCII *ASCIIOptions
+	// Values replaces NaN and ±Inf in the float values of EncodeRow and
+	// fails on values outside their type with ErrorOutOfRange, see
+	// ValuePolicy. Its Types are not used, the header giving the types.
+	Values *ValuePolicy
+	// TempDir is where rows are spooled when counts are unknown and the
+	// writer cannot seek, the system default when empty.
+	TempDir string
+}
+
+// Encoder writes a PLY file row by row without holding its data, e.g. for
+// points produced by a sensor. The header is written first; rows then go
+// to the first element not yet complete, in header order. Elements of
+// UnknownCount size take rows until NextElement or Close, which then
+// writes their count back into the space reserved for it in the header.
+// When the writer cannot seek, the rows are instead spooled to a temporary
+// file and copied after the header, with the final counts, by Close.
+type Encoder struct {
+	w      io.Writer
+	bw     *bufio.Writer
+	header *PLY
+	opts   *EncoderOptions
+	out    binary.ByteOrder
+	// rows counts the rows written to each element, of which current is
+	// the one being written
+	rows    []int
+	current int
+	// offsets locates the counts of unknown size elements in the output
+	offsets map[int]int64
+	base    int64
+	// spool holds the rows until Close when counts are unknown and w
+	// cannot seek
+	spool   *os.File
+	scratch *Element
+	closed  bool
+}
+
+// NewEncoder writes the header of a file laid out like header, which
+// gives the format, comments, elements and their counts; its rows are
+// ignored. Elements may have UnknownCount size.
+func NewEncoder(w io.Writer, header *PLY, opts *EncoderOptions) (*Encoder, error) {
+	if opts == nil {
+		opts = &EncoderOptions{}
+	}
+	enc := &Encoder{w: w, bw: bufio.NewWriter(w), header: header, opts: opts,
+		out: binary.LittleEndian, rows: make([]int, len(header.Elements)),
+		offsets: make(map[int]int64)}
+	if header.FileType == BinaryBigEndian {
+		enc.out = binary.BigEndian
+	}
+	// write the header with zero counts, then widen those of unknown size
+	h := *header
+	h.Elements = make([]*Element, len(header.Elements))
+	for k, elem := range header.Elements {
+		if elem.Size < 0 && elem.Size != UnknownCount {
+			return nil, errors.New("Invalid count for element " + elem.Name)
+		}
+		h.Elements[k] = &Element{Name: elem.Name, Properties: elem.Properties}
+		if elem.Size > 0 {
+			h.Elements[k].Size = elem.Size
+		}
+	}
+	var buf bytes.Buffer
+	hw := bufio.NewWriter(&buf)
+	if e := writeHeader(&h, hw); e != nil {
+		return nil, e
+	}
+	hw.Flush()
+	lines := strings.SplitAfter(buf.String(), "\n")
+	var text strings.Builder
+	k := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "element ") {
+			if header.Elements[k].Size == UnknownCount {
+				line = strings.TrimSuffix(line, "0\n")
+				enc.offsets[k] = int64(text.Len() + len(line))
+				line += strings.Repeat(" ", countWidth-1) + "0\n"
+			}
+			k++
+		}
+		text.WriteString(line)
+	}
+	if len(enc.offsets) > 0 {
+		base := int64(-1)
+		if s, ok := w.(io.WriteSeeker); ok {
+			if b, e := s.Seek(0, io.SeekCurrent); e == nil {
+				base = b
+			}
+		}
+		if base < 0 {
+			// e.g. a pipe, or a writer that is not a file at all
+			f, e := ioutil.TempFile(opts.TempDir, "ply")
+			if e != nil {
+				return nil, e
+			}
+			enc.spool = f
+			enc.bw = bufio.NewWriter(f)
+			return enc, nil
+		}
+		enc.base = base
+	}
+	if _, e := enc.bw.WriteString(text.String()); e != nil {
+		return nil, e
+	}
+	return enc, nil
+}
+
+// EncodeRow writes a row with values keyed by property name, as
+// Element.AppendRow takes them.
+func (enc *Encoder) EncodeRow(values map[string]interface{}) error {
+	k, e := enc.next()
+	if e != nil {
+		return e
+	}
+	elem := enc.header.Elements[k]
+	if enc.scratch == nil || enc.scratch.Name != elem.Name {
+		enc.scratch = &Element{Name: elem.Name}
+		for _, prop := range elem.Properties {
+			enc.scratch.Properties = append(enc.scratch.Properties, &Property{Name: prop.Name,
+				IsList: prop.IsList, Type: prop.Type, ListSizeType: prop.ListSizeType, order: enc.out})
+		}
+	}
+	for _, prop := range enc.scratch.Properties {
+		prop.Data = prop.Data[:0]
+	}
+	enc.scratch.Size = 0
+	if enc.opts.Values != nil {
+		if values, e = enc.opts.Values.fitRow(elem, values); e != nil {
+			return e
+		}
+	}
+	if e = enc.scratch.AppendRow(values); e != nil {
+		return e
+	}
+	return enc.writeRows(k, enc.scratch)
+}
+
+// EncodeColumnChunk writes the rows of chunk, an element with the same
+// name and properties as the one being written, e.g. part of a file read
+// by a Decoder. Rows beyond a known count are an error and none are then
+// written.
+func (enc *Encoder) EncodeColumnChunk(chunk *Element) error {
+	if chunk.Size == 0 {
+		return nil
+	}
+	k, e := enc.next()
+	if e != nil {
+		return e
+	}
+	elem := enc.header.Elements[k]
+	if chunk.Name != elem.Name || len(chunk.Properties) != len(elem.Properties) {
+		return errors.New("Chunk " + chunk.Name + " does not match element " + elem.Name)
+	}
+	for j, prop := range chunk.Properties {
+		want := elem.Properties[j]
+		if prop.Name != want.Name || prop.IsList != want.IsList || prop.Type != want.Type ||
+			prop.IsList && prop.ListSizeType != want.ListSizeType {
+			return errors.New("Property " + prop.Name + " does not match element " + elem.Name)
+		}
+	}
+	if elem.Size != UnknownCount && enc.rows[k]+chunk.Size > elem.Size {
+		return errors.New("Chunk exceeds the " + itoa(elem.Size) + " rows of element " + elem.Name)
+	}
+	return enc.writeRows(k, chunk)
+}
+
+// NextElement ends the element being written, the first one not ended
+// yet even when its rows are complete, which it must be unless of unknown
+// count.
+func (enc *Encoder) NextElement() error {
+	if enc.closed {
+		return ErrClosed
+	}
+	k := enc.current
+	if k >= len(enc.rows) {
+		return errors.New("All elements are complete")
+	}
+	if e := enc.complete(k); e != nil {
+		return e
+	}
+	enc.current = k + 1
+	return nil
+}
+
+// Flush writes the buffered rows to the underlying writer.
+func (enc *Encoder) Flush() error {
+	return enc.bw.Flush()
+}
+
+// Close checks that every element is complete, flushes the rows and
+// writes back the counts of unknown size elements. It does not close the
+// underlying writer. Rows can still be added after Close failed on an
+// incomplete element.
+func (enc *Encoder) Close() error {
+	if enc.closed {
+		return ErrClosed
+	}
+	for k := enc.current; k < len(enc.rows); k++ {
+		if e := enc.complete(k); e != nil {
+			return e
+		}
+	}
+	enc.closed = true
+	if e := enc.bw.Flush(); e != nil {
+		return e
+	}
+	if enc.spool != nil {
+		return enc.copySpool()
+	}
+	if len(enc.offsets) == 0 {
+		return nil
+	}
+	s := enc.w.(io.WriteSeeker)
+	for k, off := range enc.offsets {
+		count := itoa(enc.rows[k])
+		if len(count) > countWidth {
+			return errors.New("Too many rows in element " + enc.header.Elements[k].Name)
+		}
+		if _, e := s.Seek(enc.base+off, io.SeekStart); e != nil {
+			return e
+		}
+		if _, e := io.WriteString(s, strings.Repeat(" ", countWidth-len(count))+count); e != nil {
+			return e
+		}
+	}
+	_, e := s.Seek(0, io.SeekEnd)
+	return e
+}
+
+// copySpool writes the header with the final counts and the spooled rows
+// to the writer, removing the spool.
+func (enc *Encoder) copySpool() error {
+	f := enc.spool
+	defer os.Remove(f.Name())
+	defer f.Close()
+	h := *enc.header
+	h.Elements = make([]*Element, len(enc.header.Elements))
+	for k, elem := range enc.header.Elements {
+		h.Elements[k] = &Element{Name: elem.Name, Size: enc.rows[k], Properties: elem.Properties}
+	}
+	bw := bufio.NewWriter(enc.w)
+	if e := writeHeader(&h, bw); e != nil {
+		return e
+	}
+	if _, e := f.Seek(0, io.SeekStart); e != nil {
+		return e
+	}
+	if _, e := bw.ReadFrom(f); e != nil {
+		return e
+	}
+	return bw.Flush()
+}
+
+// next returns the element the next rows go to, skipping complete ones.
+func (enc *Encoder) next() (int, error) {
+	if enc.closed {
+		return 0, ErrClosed
+	}
+	for enc.current < len(enc.rows) {
+		size := enc.header.Elements[enc.current].Size
+		if size == UnknownCount || enc.rows[enc.current] < size {
+			return enc.current, nil
+		}
+		enc.current++
+	}
+	return 0, errors.New("All elements are complete")
+}
+
+func (enc *Encoder) complete(k int) error {
+	elem := enc.header.Elements[k]
+	if elem.Size != UnknownCount && enc.rows[k] < elem.Size {
+		return errors.New("Element " + elem.Name + " has " + itoa(enc.rows[k]) +
+			" of its " + itoa(elem.Size) + " rows")
+	}
+	return nil
+}
+
+// writeRows writes the rows of elem as rows of element k.
+func (enc *Encoder) writeRows(k int, elem *Element) error {
+	for i := 0; i < elem.Size; i++ {
+		var e error
+		if enc.header.FileType == Ascii {
+			e = writeASCIIRow(elem, i, enc.bw, enc.opts.ASCII)
+		} else {
+			e = writeBinaryRow(elem, i, enc.out, enc.bw)
+		}
+		if e != nil {
+			return e
+		}
+		enc.rows[k]++
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package ply
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	for _, format := range []int8{Ascii, BinaryLittleEndian, BinaryBigEndian} {
+		header := MeshBasic.New(nil)
+		header.FileType = format
+		header.Comments = []string{"streamed"}
+		header.Elements[0].Size = 3
+		header.Elements[1].Size = 1
+		var buf bytes.Buffer
+		enc, e := NewEncoder(&buf, header, nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		for i := 0; i < 3; i++ {
+			if e := enc.EncodeRow(map[string]interface{}{"x": float32(i), "z": 0.5}); e != nil {
+				t.Fatal(e)
+			}
+		}
+		if e := enc.Close(); e == nil {
+			t.Error("expected an error for a missing face")
+		}
+		if e := enc.EncodeRow(map[string]interface{}{"vertex_indices": []int{0, 1, 2}}); e != nil {
+			t.Fatal(e)
+		}
+		if e := enc.EncodeRow(map[string]interface{}{"vertex_indices": []int{0}}); e == nil {
+			t.Error("expected an error for a row beyond the counts")
+		}
+		if e := enc.Close(); e != nil {
+			t.Fatal(e)
+		}
+		p := new(PLY)
+		if e := p.Read(&buf); e != nil {
+			t.Fatal(e)
+		}
+		v := p.ReadVertices()
+		if v[0][2] != 2 || v[2][1] != 0.5 || len(p.ReadFaces()) != 1 || p.ReadFaces()[0][2] != 2 ||
+			p.Comments[0] != "streamed" {
+			t.Errorf("format %d: unexpected result %v %v", format, v, p.ReadFaces())
+		}
+	}
+}
+
+func TestEncoderUnknownCount(t *testing.T) {
+	src := new(PLY)
+	if e := src.Read(strings.NewReader(benchMesh(20))); e != nil {
+		t.Fatal(e)
+	}
+	header := *src
+	header.Elements = []*Element{{Name: "vertex", Size: UnknownCount, Properties: src.Elements[0].Properties},
+		{Name: "face", Size: UnknownCount, Properties: src.Elements[1].Properties}}
+	// a writer that cannot seek gets the rows after Close
+	var buf bytes.Buffer
+	enc, e := NewEncoder(&buf, &header, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.EncodeColumnChunk(src.Elements[0]); e != nil || buf.Len() != 0 {
+		t.Fatal("expected the rows to be spooled", e)
+	}
+	enc.NextElement()
+	enc.EncodeColumnChunk(src.Elements[1])
+	spool := enc.spool.Name()
+	if e := enc.Close(); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&buf); e != nil || q.ContentHash() != src.ContentHash() {
+		t.Error("expected the spooled file to match", e)
+	}
+	if _, e := os.Stat(spool); !os.IsNotExist(e) {
+		t.Error("expected the spool to be removed")
+	}
+
+	dir, e := ioutil.TempDir("", "ply")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "stream.ply")
+	file, e := os.Create(name)
+	if e != nil {
+		t.Fatal(e)
+	}
+	enc, e = NewEncoder(file, &header, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	// the vertices in two chunks, the faces in one
+	vertex := src.Elements[0]
+	for _, rows := range [][]int{{0, 1, 2, 3, 4, 5, 6}, {7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}} {
+		if e := enc.EncodeColumnChunk(vertex.selectRows(rows)); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if e := enc.EncodeColumnChunk(src.Elements[1]); e == nil {
+		t.Error("expected an error for a chunk of another element")
+	}
+	if e := enc.NextElement(); e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.EncodeColumnChunk(src.Elements[1]); e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.Close(); e != nil {
+		t.Fatal(e)
+	}
+	file.Close()
+
+	p := new(PLY)
+	if e := p.Load(name); e != nil {
+		t.Fatal(e)
+	}
+	if p.VerticesCount() != 20 || p.ContentHash() != src.ContentHash() {
+		t.Errorf("expected the streamed file to match, got %d vertices", p.VerticesCount())
+	}
+	raw, _ := ioutil.ReadFile(name)
+	if !bytes.Contains(raw, []byte("element vertex         20\n")) {
+		t.Error("expected a padded count")
+	}
+}
+
+func TestEncoderNextElementAfterComplete(t *testing.T) {
+	header := MeshBasic.New(nil)
+	header.Elements[0].Size = 3
+	header.Elements[1].Size = UnknownCount
+	var buf bytes.Buffer
+	enc, e := NewEncoder(&buf, header, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	for i := 0; i < 3; i++ {
+		enc.EncodeRow(map[string]interface{}{"x": float32(i)})
+	}
+	// ends the complete vertices, not the faces following them
+	if e := enc.NextElement(); e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.EncodeRow(map[string]interface{}{"vertex_indices": []int{0, 1, 2}}); e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.Close(); e != nil {
+		t.Fatal(e)
+	}
+	p := new(PLY)
+	if e := p.Read(&buf); e != nil {
+		t.Fatal(e)
+	}
+	if len(p.ReadFaces()) != 1 {
+		t.Errorf("expected one face, got %v", p.ReadFaces())
+	}
+}
@@ -0,0 +1,54 @@
+package ply
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maximum number of offending rows listed by FaceIndexError.Error
+const faceIndexErrorRows = 10
+
+type FaceIndexError struct {
+	VertexCount int
+	// Rows holds the face rows referencing vertices outside
+	// [0, VertexCount), in ascending order.
+	Rows []int
+}
+
+func (e *FaceIndexError) Error() string {
+	rows := make([]string, 0, faceIndexErrorRows)
+	for i, row := range e.Rows {
+		if i == faceIndexErrorRows {
+			rows = append(rows, "...")
+			break
+		}
+		rows = append(rows, strconv.Itoa(row))
+	}
+	return strconv.Itoa(len(e.Rows)) + " faces reference vertices outside [0, " +
+		strconv.Itoa(e.VertexCount) + "): rows " + strings.Join(rows, ", ")
+}
+
+func checkFaceIndices(p *PLY) error {
+	face := p.findElement("face")
+	if face == nil {
+		return nil
+	}
+	prop := p.faceIndexProperty(face)
+	if prop == nil {
+		return nil
+	}
+	n := p.VerticesCount()
+	var rows []int
+	for i := 0; i < face.Size; i++ {
+		for _, v := range prop.listFloat64At(i) {
+			if !(v >= 0 && v < float64(n)) {
+				rows = append(rows, i)
+				break
+			}
+		}
+	}
+	if rows != nil {
+		return &FaceIndexError{VertexCount: n, Rows: rows}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckFaceIndices(t *testing.T) {
+	src := strings.Replace(testASCIIMesh, "3 0 1 2 -7", "3 0 4 2 -7", 1)
+	src = strings.Replace(src, "4 0 1 2 3 300", "4 0 1 -1 3 300", 1)
+	if e := new(PLY).Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	e := new(PLY).ReadWithOptions(strings.NewReader(src), &LoadOptions{CheckFaceIndices: true})
+	fe, ok := e.(*FaceIndexError)
+	if !ok {
+		t.Fatalf("expected FaceIndexError, got %v", e)
+	}
+	if fe.VertexCount != 4 || len(fe.Rows) != 2 || fe.Rows[0] != 0 || fe.Rows[1] != 1 {
+		t.Errorf("unexpected error %+v", fe)
+	}
+	if e := new(PLY).ReadWithOptions(strings.NewReader(testASCIIMesh), &LoadOptions{CheckFaceIndices: true}); e != nil {
+		t.Error(e)
+	}
+}
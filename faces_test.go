@@ -0,0 +1,30 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadFacesNames(t *testing.T) {
+	src := strings.Replace(testASCIIMesh, "vertex_indices", "vertex_list", 1)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	faces := p.ReadFaces()
+	if len(faces) != 2 || len(faces[1]) != 4 || faces[1][3] != 3 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	p.FaceIndexNames = []string{"corners"}
+	if faces := p.ReadFaces(); len(faces) != 2 {
+		t.Error("expected fallback to the only list property")
+	}
+	p.Elements[1].Properties = append(p.Elements[1].Properties, newListProperty("other", "uchar", "int", 2))
+	if faces := p.ReadFaces(); faces != nil {
+		t.Errorf("expected ambiguous lists to be ignored, got %v", faces)
+	}
+	p.FaceIndexNames = []string{"other", "vertex_list"}
+	if faces := p.ReadFaces(); len(faces) != 2 || len(faces[0]) != 0 {
+		t.Errorf("expected configured name to win, got %v", faces)
+	}
+}
@@ -0,0 +1,121 @@
+package ply
+
+import "errors"
+
+// FilterByProperty returns a copy of p keeping the rows of element whose
+// scalar property passes predicate, e.g. the vertices above a confidence
+// threshold. Filtering vertices removes the faces referencing a dropped
+// vertex and re-indexes the remaining ones; other elements are shared
+// with p.
+func (p *PLY) FilterByProperty(element, property string, predicate func(float64) bool) (*PLY, error) {
+	elem := p.findElement(element)
+	if elem == nil {
+		return nil, errors.New("No element " + element)
+	}
+	props := elem.scalarProperties(property)
+	if props == nil {
+		return nil, errors.New("Element " + element + " has no scalar " + property + " property")
+	}
+	keep := func(i int) bool { return predicate(props[0].float64At(i)) }
+	if element == "vertex" {
+		return p.filterVertices(keep), nil
+	}
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	copy(q.Elements, p.Elements)
+	var rows []int
+	for i := 0; i < elem.Size; i++ {
+		if keep(i) {
+			rows = append(rows, i)
+		}
+	}
+	for k := range q.Elements {
+		if q.Elements[k] == elem {
+			q.Elements[k] = elem.selectRows(rows)
+			break
+		}
+	}
+	return q, nil
+}
+
+// filterVertices returns a copy of p holding only the vertices for which
+// keep returns true. Faces referencing a dropped vertex are removed and the
+// remaining faces re-indexed; other elements are shared with p.
+func (p *PLY) filterVertices(keep func(i int) bool) *PLY {
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	copy(q.Elements, p.Elements)
+	vertexAt := -1
+	for k, elem := range p.Elements {
+		if elem.Name == "vertex" {
+			vertexAt = k
+			break
+		}
+	}
+	if vertexAt < 0 {
+		return q
+	}
+	vertex := p.Elements[vertexAt]
+	remap := make([]int, vertex.Size)
+	var rows []int
+	for i := range remap {
+		remap[i] = -1
+		if keep(i) {
+			remap[i] = len(rows)
+			rows = append(rows, i)
+		}
+	}
+	q.Elements[vertexAt] = vertex.selectRows(rows)
+	for k, elem := range p.Elements {
+		if elem.Name != "face" {
+			continue
+		}
+		idx := p.faceIndexProperty(elem)
+		if idx == nil {
+			continue
+		}
+		var faceRows []int
+		faceData := newListColumn(idx)
+		for i := 0; i < elem.Size; i++ {
+			values := idx.listFloat64At(i)
+			ok := true
+			for j, v := range values {
+				n := int(v)
+				if n < 0 || n >= len(remap) || float64(n) != v || remap[n] < 0 {
+					ok = false
+					break
+				}
+				values[j] = float64(remap[n])
+			}
+			if ok {
+				faceRows = append(faceRows, i)
+				faceData.add(values)
+			}
+		}
+		sub := elem.selectRows(faceRows)
+		for _, prop := range sub.Properties {
+			if prop.Name == idx.Name {
+				prop.Data = faceData.rows()
+			}
+		}
+		q.Elements[k] = sub
+	}
+	return q
+}
+
+// selectRows returns a copy of e holding the given rows, copied into one
+// buffer per property so that writing the copy leaves e unchanged.
+func (e *Element) selectRows(rows []int) *Element {
+	sub := &Element{Name: e.Name, Size: len(rows)}
+	for _, prop := range e.Properties {
+		sp := *prop
+		data := newListColumn(&sp)
+		for _, i := range rows {
+			data.addRow(prop.row(i))
+		}
+		// the rows replace any undecoded block of the copy
+		sp.Data, sp.block = data.rows(), nil
+		sub.Properties = append(sub.Properties, &sp)
+	}
+	return sub
+}
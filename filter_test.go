@@ -0,0 +1,49 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFilterByProperty(t *testing.T) {
+	p := new(PLY)
+	if e := p.ReadWithOptions(bytes.NewReader(benchBinary(t, 6)), &LoadOptions{Lazy: true}); e != nil {
+		t.Fatal(e)
+	}
+	q, e := p.FilterByProperty("vertex", "red", func(v float64) bool { return v >= 1 })
+	if e != nil {
+		t.Fatal(e)
+	}
+	if n := q.GetVertices().Size; n != 5 {
+		t.Errorf("expected 5 vertices, got %d", n)
+	}
+	if x := q.GetVertices().findProperty("x"); len(x.Data) != 5 || x.float64At(0) != float64(float32(0.143)) {
+		t.Errorf("unexpected x after filtering a lazy load: %d rows", len(x.Data))
+	}
+	faces := q.ReadFaces()
+	if len(faces) != 3 || faces[0][0] != 0 || faces[2][2] != 4 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if p.GetVertices().Size != 6 {
+		t.Error("expected the input left untouched")
+	}
+
+	p = new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	q, e = p.FilterByProperty("face", "flags", func(v float64) bool { return v > 0 })
+	if e != nil {
+		t.Fatal(e)
+	}
+	if faces := q.ReadFaces(); len(faces) != 1 || len(faces[0]) != 4 || q.GetVertices().Size != 4 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if _, e = p.FilterByProperty("vertex", "vertex_indices", nil); e == nil {
+		t.Error("expected an error for a missing scalar property")
+	}
+	if _, e = p.FilterByProperty("edge", "flags", nil); e == nil {
+		t.Error("expected an error for a missing element")
+	}
+}
@@ -0,0 +1,169 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+)
+
+type FixtureOptions struct {
+	// Format is the file type: Ascii, BinaryLittleEndian or
+	// BinaryBigEndian.
+	Format int
+	// Vertices is the number of vertices, with float x, y and z in [0, 1).
+	Vertices int
+	// Types adds a vertex property of each type, named "value_" and the
+	// type, e.g. value_ushort. The first two rows hold the lowest and
+	// highest value of the type, the others random ones.
+	Types []string
+	// Specials stores NaN, +Inf and -Inf in the rows following the bounds
+	// of float properties.
+	Specials bool
+	// Faces is the number of faces, whose corners are random vertices.
+	// Without faces there is no face element.
+	Faces int
+	// FaceSizes is cycled through for the number of corners of each face,
+	// {3} by default; 0 writes empty lists.
+	FaceSizes []int
+	// ListSizeType and ListItemType are the types of the vertex_indices
+	// list, uchar and int by default.
+	ListSizeType string
+	ListItemType string
+	// Seed seeds the random values, the same seed giving the same file.
+	Seed int64
+}
+
+// NewFixture synthesizes a PLY as described by opts, e.g. to generate
+// test files covering the types and list layouts a reader must handle.
+func NewFixture(opts *FixtureOptions) (*PLY, error) {
+	if opts == nil {
+		opts = &FixtureOptions{}
+	}
+	o := *opts
+	if o.FaceSizes == nil {
+		o.FaceSizes = []int{3}
+	}
+	if o.ListSizeType == "" {
+		o.ListSizeType = "uchar"
+	}
+	if o.ListItemType == "" {
+		o.ListItemType = "int"
+	}
+	if _, e := formatName(int8(o.Format)); e != nil {
+		return nil, e
+	}
+	if o.Vertices < 0 || o.Faces < 0 {
+		return nil, errors.New("Fixture sizes must not be negative")
+	}
+	for _, t := range append([]string{o.ListSizeType, o.ListItemType}, o.Types...) {
+		if _, ok := typeRanges[normalizeType(t)]; !ok {
+			return nil, errors.New("Unknown type " + t)
+		}
+	}
+	if isFloat(o.ListSizeType) {
+		return nil, errors.New("List size type must be integral, not " + o.ListSizeType)
+	}
+	maxSize := 0
+	for _, n := range o.FaceSizes {
+		if n < 0 {
+			return nil, errors.New("Face sizes must not be negative")
+		}
+		if n > maxSize {
+			maxSize = n
+		}
+	}
+	if o.Faces > 0 && len(o.FaceSizes) == 0 {
+		return nil, errors.New("No face sizes")
+	}
+	if o.Faces > 0 && maxSize > 0 {
+		if o.Vertices == 0 {
+			return nil, errors.New("Faces need vertices")
+		}
+		if _, ok := fitValue(float64(maxSize), o.ListSizeType); !ok {
+			return nil, errors.New("Face size " + itoa(maxSize) + " does not fit " + o.ListSizeType)
+		}
+		if _, ok := fitValue(float64(o.Vertices-1), o.ListItemType); !ok {
+			return nil, errors.New("Vertex index " + itoa(o.Vertices-1) + " does not fit " + o.ListItemType)
+		}
+	}
+	r := rand.New(rand.NewSource(o.Seed))
+	vertex := &Element{Name: "vertex", Size: o.Vertices}
+	for _, name := range []string{"x", "y", "z"} {
+		prop := newProperty(name, "float", o.Vertices)
+		for i := range prop.Data {
+			prop.Data[i] = encodeFloat64(r.Float64(), "float", binary.LittleEndian)
+		}
+		vertex.Properties = append(vertex.Properties, prop)
+	}
+	for _, t := range o.Types {
+		prop := newProperty("value_"+t, t, o.Vertices)
+		bounds := typeRanges[normalizeType(t)]
+		for i := range prop.Data {
+			v := 0.0
+			switch {
+			case i < 2:
+				v = bounds[i]
+			case o.Specials && isFloat(t) && i < 5:
+				v = [3]float64{math.NaN(), math.Inf(1), math.Inf(-1)}[i-2]
+			case isFloat(t):
+				v = r.NormFloat64() * 1000
+			default:
+				v = math.Floor(bounds[0] + r.Float64()*(bounds[1]-bounds[0]+1))
+			}
+			prop.Data[i] = encodeFloat64(v, t, binary.LittleEndian)
+		}
+		vertex.Properties = append(vertex.Properties, prop)
+	}
+	for k, prop := range vertex.Properties {
+		prop.pos = k
+	}
+	order := binary.ByteOrder(binary.LittleEndian)
+	if o.Format == BinaryBigEndian {
+		order = binary.BigEndian
+	}
+	p := &PLY{FileType: int8(o.Format), byteOrder: order, Elements: []*Element{vertex}}
+	if o.Faces > 0 {
+		indices := newListProperty("vertex_indices", o.ListSizeType, o.ListItemType, o.Faces)
+		for i := range indices.Data {
+			corners := make([]int, o.FaceSizes[i%len(o.FaceSizes)])
+			for k := range corners {
+				corners[k] = r.Intn(o.Vertices)
+			}
+			indices.setListIntsAt(i, corners)
+		}
+		p.Elements = append(p.Elements, &Element{Name: "face", Size: o.Faces, Properties: []*Property{indices}})
+	}
+	return p, nil
+}
+
+// FixtureCorpus returns the fixtures the package tests its readers and
+// writers with: in every format, one with a property of each type name,
+// and one for each pair of integral list size and item types, with empty
+// faces and faces of up to 127 corners.
+func FixtureCorpus() map[string]*FixtureOptions {
+	corpus := make(map[string]*FixtureOptions)
+	formats := map[int]string{Ascii: "ascii", BinaryLittleEndian: "binary_little_endian", BinaryBigEndian: "binary_big_endian"}
+	integral := []string{"char", "uchar", "short", "ushort", "int", "uint"}
+	for format, name := range formats {
+		corpus[name+"_types"] = &FixtureOptions{
+			Format:   format,
+			Vertices: 20,
+			Types:    append(append([]string{}, Types[1:]...), OldTypes[1:]...),
+			Specials: true,
+		}
+		for _, size := range integral {
+			for _, item := range integral {
+				corpus[name+"_list_"+size+"_"+item] = &FixtureOptions{
+					Format:       format,
+					Vertices:     20,
+					Faces:        8,
+					FaceSizes:    []int{3, 0, 4, 127},
+					ListSizeType: size,
+					ListItemType: item,
+				}
+			}
+		}
+	}
+	return corpus
+}
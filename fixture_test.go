@@ -0,0 +1,85 @@
+package ply
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestFixtureCorpus(t *testing.T) {
+	corpus := FixtureCorpus()
+	names := make([]string, 0, len(corpus))
+	for name := range corpus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) != 3*(1+36) {
+		t.Errorf("expected 111 fixtures, got %d", len(names))
+	}
+	for _, name := range names {
+		p, e := NewFixture(corpus[name])
+		if e != nil {
+			t.Fatalf("%s: %v", name, e)
+		}
+		var buf bytes.Buffer
+		if e := p.Write(&buf); e != nil {
+			t.Fatalf("%s: %v", name, e)
+		}
+		for _, lazy := range []bool{false, true} {
+			q := new(PLY)
+			if e := q.ReadWithOptions(bytes.NewReader(buf.Bytes()), &LoadOptions{Lazy: lazy}); e != nil {
+				t.Fatalf("%s: %v", name, e)
+			}
+			if d := Diff(p, q, nil); !d.Equal() {
+				var text bytes.Buffer
+				d.WriteText(&text)
+				t.Errorf("%s does not round trip:\n%s", name, text.String())
+			}
+		}
+	}
+}
+
+func TestNewFixture(t *testing.T) {
+	opts := &FixtureOptions{Format: BinaryBigEndian, Vertices: 300, Types: []string{"ushort", "double"}, Specials: true,
+		Faces: 4, FaceSizes: []int{300, 3}, ListSizeType: "ushort", ListItemType: "uint"}
+	p, e := NewFixture(opts)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vertex := p.GetVertices()
+	if len(vertex.Properties) != 5 || vertex.Properties[3].Name != "value_ushort" {
+		t.Fatalf("unexpected vertex properties %+v", vertex.Properties)
+	}
+	if u := vertex.Properties[3]; u.float64At(0) != 0 || u.float64At(1) != math.MaxUint16 {
+		t.Errorf("expected the bounds of ushort first, got %v %v", u.float64At(0), u.float64At(1))
+	}
+	if d := vertex.Properties[4]; !math.IsNaN(d.float64At(2)) || !math.IsInf(d.float64At(3), 1) || !math.IsInf(d.float64At(4), -1) {
+		t.Errorf("expected special values, got %v %v %v", d.float64At(2), d.float64At(3), d.float64At(4))
+	}
+	faces := p.ReadFaces()
+	if len(faces) != 4 || len(faces[0]) != 300 || len(faces[1]) != 3 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	q, _ := NewFixture(opts)
+	if !Diff(p, q, nil).Equal() {
+		t.Error("expected the same fixture for the same seed")
+	}
+	if p, e := NewFixture(nil); e != nil || len(p.Elements) != 1 || p.FileType != BinaryBigEndian {
+		t.Errorf("unexpected default fixture %v %v", p, e)
+	}
+	for _, bad := range []*FixtureOptions{
+		{Format: 7},
+		{Vertices: -1},
+		{Types: []string{"long"}},
+		{Vertices: 3, Faces: 1, ListSizeType: "float"},
+		{Vertices: 3, Faces: 1, FaceSizes: []int{256}},
+		{Vertices: 300, Faces: 1, ListItemType: "uchar"},
+		{Faces: 1},
+		{Vertices: 3, Faces: 1, FaceSizes: []int{}},
+	} {
+		if _, e := NewFixture(bad); e == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package ply
+
+import "errors"
+
+// Float32s returns the values of a scalar property converted to float32.
+// Float properties stored in the host byte order are copied in bulk rather
+// than decoded value by value; build with the purego tag to disable this.
+func (e *Element) Float32s(name string) ([]float32, error) {
+	prop, err := e.floatProperty(name)
+	if err != nil {
+		return nil, err
+	}
+	return prop.float32s(e.Size), nil
+}
+
+// Float64s is like Float32s but returns float64 values.
+func (e *Element) Float64s(name string) ([]float64, error) {
+	prop, err := e.floatProperty(name)
+	if err != nil {
+		return nil, err
+	}
+	return prop.float64s(e.Size), nil
+}
+
+func (e *Element) floatProperty(name string) (*Property, error) {
+	prop := e.findProperty(name)
+	if prop == nil {
+		return nil, errors.New("No property " + name + " in element " + e.Name)
+	}
+	if prop.IsList {
+		return nil, errors.New("Property " + name + " is a list")
+	}
+	return prop, nil
+}
+
+// float32s returns the first n rows of a scalar property, NaN where a row
+// is missing.
+func (p *Property) float32s(n int) []float32 {
+	values := make([]float32, n)
+	if (p.Type == "float32" || p.Type == "float") && p.copyRaw(float32Bytes(values), n) {
+		return values
+	}
+	for i := range values {
+		values[i] = float32(p.float64At(i))
+	}
+	return values
+}
+
+// float64s is the float64 counterpart of float32s.
+func (p *Property) float64s(n int) []float64 {
+	values := make([]float64, n)
+	if (p.Type == "float64" || p.Type == "double") && p.copyRaw(float64Bytes(values), n) {
+		return values
+	}
+	for i := range values {
+		values[i] = p.float64At(i)
+	}
+	return values
+}
+
+// copyRaw copies the first n rows of p into dst when their bytes are the
+// in-memory representation of dst's values, that is when dst is not nil
+// and p is stored in the host byte order. It reports false, with dst
+// possibly partly written, when a row is missing.
+func (p *Property) copyRaw(dst []byte, n int) bool {
+	if n == 0 {
+		return true
+	}
+	if dst == nil || p.byteOrder() != hostOrder {
+		return false
+	}
+	size := len(dst) / n
+	if p.isPacked(p.column, n, size, 0) {
+		copy(dst, p.column)
+		return true
+	}
+	for i := 0; i < n; i++ {
+		b := p.row(i)
+		if len(b) != size {
+			return false
+		}
+		copy(dst[i*size:], b)
+	}
+	return true
+}
+
+// SetFloat32 replaces the values of a scalar property, one per row,
+// converting them to the property's type: integer types round and clamp
+// them. The property gets new rows, so copies sharing the old ones are
+// not affected.
+func (p *PLY) SetFloat32(element, property string, values []float32) error {
+	prop, e := p.settableProperty(element, property, len(values))
+	if e != nil {
+		return e
+	}
+	raw := []byte(nil)
+	if prop.Type == "float32" || prop.Type == "float" {
+		raw = float32Bytes(values)
+	}
+	prop.setColumn(len(values), raw, func(i int) float64 { return float64(values[i]) })
+	return nil
+}
+
+// SetFloat64 is like SetFloat32 with float64 values.
+func (p *PLY) SetFloat64(element, property string, values []float64) error {
+	prop, e := p.settableProperty(element, property, len(values))
+	if e != nil {
+		return e
+	}
+	raw := []byte(nil)
+	if prop.Type == "float64" || prop.Type == "double" {
+		raw = float64Bytes(values)
+	}
+	prop.setColumn(len(values), raw, func(i int) float64 { return values[i] })
+	return nil
+}
+
+// SetInt32 is like SetFloat32 with int32 values.
+func (p *PLY) SetInt32(element, property string, values []int32) error {
+	prop, e := p.settableProperty(element, property, len(values))
+	if e != nil {
+		return e
+	}
+	prop.setColumn(len(values), nil, func(i int) float64 { return float64(values[i]) })
+	return nil
+}
+
+// SetUint8 is like SetFloat32 with uint8 values, e.g. colors.
+func (p *PLY) SetUint8(element, property string, values []uint8) error {
+	prop, e := p.settableProperty(element, property, len(values))
+	if e != nil {
+		return e
+	}
+	raw := []byte(nil)
+	if prop.Type == "uint8" || prop.Type == "uchar" {
+		raw = values
+	}
+	prop.setColumn(len(values), raw, func(i int) float64 { return float64(values[i]) })
+	return nil
+}
+
+// settableProperty returns the scalar property that n values replace.
+func (p *PLY) settableProperty(element, property string, n int) (*Property, error) {
+	if p.frozen {
+		return nil, ErrFrozen
+	}
+	elem := p.findElement(element)
+	if elem == nil {
+		return nil, errors.New("No element " + element)
+	}
+	prop, e := elem.floatProperty(property)
+	if e != nil {
+		return nil, e
+	}
+	if n != elem.Size {
+		return nil, errors.New("Got " + itoa(n) + " values for " + itoa(elem.Size) + " rows")
+	}
+	return prop, nil
+}
+
+// setColumn replaces the rows of p with n values packed in a new column,
+// copying raw when it is their encoding in the host byte order.
+func (p *Property) setColumn(n int, raw []byte, value func(i int) float64) {
+	size := SizeOfType[p.Type]
+	buf := make([]byte, n*size)
+	order := p.byteOrder()
+	if raw != nil && (size == 1 || order == hostOrder) {
+		copy(buf, raw)
+	} else {
+		for i := 0; i < n; i++ {
+			putFloat64(buf[i*size:], value(i), p.Type, order)
+		}
+	}
+	data := make([][]byte, n)
+	for i := range data {
+		data[i] = buf[i*size : (i+1)*size : (i+1)*size]
+	}
+	p.Data, p.column, p.block = data, buf, nil
+}
@@ -0,0 +1,13 @@
+//go:build purego
+// +build purego
+
+package ply
+
+import "encoding/binary"
+
+// hostOrder is nil: without unsafe, float rows are decoded value by value.
+var hostOrder binary.ByteOrder
+
+func float32Bytes(v []float32) []byte { return nil }
+
+func float64Bytes(v []float64) []byte { return nil }
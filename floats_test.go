@@ -0,0 +1,111 @@
+package ply
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFloat32s(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 3
+property float x
+property double quality
+property uchar red
+property list uchar int ids
+end_header
+0.5 1.25 7 1 0
+-2 1e300 255 0
+3.75 -0.5 0 2 1 2
+`
+	for _, fileType := range []int8{BinaryLittleEndian, BinaryBigEndian} {
+		a := new(PLY)
+		if e := a.Read(strings.NewReader(src)); e != nil {
+			t.Fatal(e)
+		}
+		a.FileType = fileType
+		var buf bytes.Buffer
+		a.Write(&buf)
+		p := new(PLY)
+		if e := p.Read(&buf); e != nil {
+			t.Fatal(e)
+		}
+		vertex := p.findElement("vertex")
+		x, _ := vertex.Float32s("x")
+		q, _ := vertex.Float64s("quality")
+		red, _ := vertex.Float32s("red")
+		if len(x) != 3 || x[1] != -2 || x[2] != 3.75 || q[1] != 1e300 || q[2] != -0.5 || red[1] != 255 {
+			t.Errorf("%d: unexpected values %v %v %v", fileType, x, q, red)
+		}
+		vertex.SetLayout(ColumnMajor)
+		if x, _ := vertex.Float32s("x"); x[0] != 0.5 {
+			t.Errorf("%d: unexpected packed values %v", fileType, x)
+		}
+	}
+
+	p := new(PLY)
+	p.Read(strings.NewReader(src))
+	vertex := p.findElement("vertex")
+	if _, e := vertex.Float32s("ids"); e == nil {
+		t.Error("expected an error for a list")
+	}
+	if _, e := vertex.Float64s("y"); e == nil {
+		t.Error("expected an error for a missing property")
+	}
+	x := vertex.findProperty("x")
+	x.Data = x.Data[:2]
+	if v, _ := vertex.Float32s("x"); v[0] != 0.5 || !math.IsNaN(float64(v[2])) {
+		t.Errorf("expected NaN for a missing row, got %v", v)
+	}
+}
+
+func TestSetFloat32(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	shared := p.keepVertexRows([]int{0, 1, 2, 3})
+	if e := p.SetFloat32("vertex", "x", []float32{5, 6, 7, 8.5}); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.SetFloat64("vertex", "quality", []float64{1, 2, 3, 1e300}); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.SetInt32("face", "flags", []int32{40000, -3}); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.SetUint8("vertex", "y", []uint8{9, 8, 7, 6}); e != nil {
+		t.Fatal(e)
+	}
+	p.FileType = BinaryBigEndian
+	var buf bytes.Buffer
+	p.Write(&buf)
+	q := new(PLY)
+	if e := q.Read(&buf); e != nil {
+		t.Fatal(e)
+	}
+	vertex, face := q.findElement("vertex"), q.findElement("face")
+	x, _ := vertex.Float32s("x")
+	y, _ := vertex.Float32s("y")
+	quality, _ := vertex.Float64s("quality")
+	flags, _ := face.Float64s("flags")
+	if x[3] != 8.5 || y[0] != 9 || quality[3] != 1e300 || flags[0] != 32767 || flags[1] != -3 {
+		t.Errorf("unexpected values %v %v %v %v", x, y, quality, flags)
+	}
+	if x, _ := shared.findElement("vertex").Float32s("x"); x[0] != 0 {
+		t.Error("expected copies sharing the old rows to be unchanged")
+	}
+
+	for _, e := range []error{
+		p.SetFloat32("vertex", "x", []float32{1}),
+		p.SetFloat32("face", "vertex_indices", []float32{1, 2}),
+		p.SetFloat64("edge", "x", nil),
+		p.Freeze().SetUint8("vertex", "y", []uint8{1, 2, 3, 4}),
+	} {
+		if e == nil {
+			t.Error("expected an error")
+		}
+	}
+}
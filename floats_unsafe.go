@@ -0,0 +1,45 @@
+//go:build !purego
+// +build !purego
+
+package ply
+
+import (
+	"encoding/binary"
+	"reflect"
+	"unsafe"
+)
+
+// hostOrder is the byte order of the machine, nil when float rows must be
+// decoded value by value.
+var hostOrder = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// float32Bytes returns the memory of v as bytes.
+func float32Bytes(v []float32) []byte {
+	if len(v) == 0 {
+		return nil
+	}
+	return rawBytes(unsafe.Pointer(&v[0]), len(v)*4)
+}
+
+// float64Bytes returns the memory of v as bytes.
+func float64Bytes(v []float64) []byte {
+	if len(v) == 0 {
+		return nil
+	}
+	return rawBytes(unsafe.Pointer(&v[0]), len(v)*8)
+}
+
+func rawBytes(p unsafe.Pointer, n int) []byte {
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = uintptr(p)
+	h.Len = n
+	h.Cap = n
+	return b
+}
@@ -0,0 +1,48 @@
+package ply
+
+import "errors"
+
+// ErrFrozen is returned when modifying a PLY or element made by Freeze.
+var ErrFrozen = errors.New("PLY is frozen")
+
+// Freeze returns an immutable deep copy of p that any number of
+// goroutines may read concurrently. Each property's rows are packed into
+// one buffer, so that Column needs no repacking. Methods modifying it
+// return ErrFrozen, or panic with it when they have no error result.
+// Copies derived from it, e.g. by CropAABB, share its data and are frozen
+// too. Slices returned by its accessors must not be modified.
+//
+// Without Freeze, a PLY may be read concurrently as long as no goroutine
+// modifies it: reading methods, Column and Records on packed elements
+// included, do not change it. A PLY loaded with LoadOptions.Lazy decodes
+// its properties on first use, so it must be decoded by DecodeAll first.
+func (p *PLY) Freeze() *PLY {
+	q := p.shallowCopy()
+	q.Comments = append([]string(nil), p.Comments...)
+	q.FaceIndexNames = append([]string(nil), p.FaceIndexNames...)
+	q.LoadErrors = append([]error(nil), p.LoadErrors...)
+	q.header = append([]string(nil), p.header...)
+	if p.ObjInfoItems != nil {
+		q.ObjInfoItems = make(map[string]string, len(p.ObjInfoItems))
+		for k, v := range p.ObjInfoItems {
+			q.ObjInfoItems[k] = v
+		}
+	}
+	q.Elements = make([]*Element, len(p.Elements))
+	for k, elem := range p.Elements {
+		c := &Element{Name: elem.Name, Size: elem.Size, frozen: true}
+		for _, prop := range elem.Properties {
+			cp := *prop
+			cp.packColumn(elem.Size)
+			c.Properties = append(c.Properties, &cp)
+		}
+		q.Elements[k] = c
+	}
+	q.frozen = true
+	return q
+}
+
+// Frozen reports whether p was made by Freeze.
+func (p *PLY) Frozen() bool {
+	return p.frozen
+}
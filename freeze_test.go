@@ -0,0 +1,65 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	f := p.Freeze()
+	if !f.Frozen() || p.Frozen() || f.ContentHash() != p.ContentHash() {
+		t.Fatal("expected a frozen copy with the same content")
+	}
+	p.findElement("vertex").findProperty("x").setFloat64At(1, 9)
+	if f.ReadVertices()[0][1] != 1 {
+		t.Error("expected the snapshot to be independent of p")
+	}
+
+	if e := f.Triangulate(FanTriangulation); e != ErrFrozen {
+		t.Errorf("expected ErrFrozen, got %v", e)
+	}
+	if _, e := f.WeldVertices(1); e != ErrFrozen {
+		t.Errorf("expected ErrFrozen, got %v", e)
+	}
+	if e := f.findElement("vertex").AddProperty("w", "float", make([]float64, 4)); e != ErrFrozen {
+		t.Errorf("expected ErrFrozen, got %v", e)
+	}
+	if e := f.Read(strings.NewReader(testASCIIMesh)); e != ErrFrozen {
+		t.Errorf("expected ErrFrozen reading, got %v", e)
+	}
+	func() {
+		defer func() {
+			if recover() != ErrFrozen {
+				t.Error("expected Canonicalize to panic with ErrFrozen")
+			}
+		}()
+		f.Canonicalize()
+	}()
+	if q, _ := f.CropAABB([3]float64{0, 0, 0}, [3]float64{1, 1, 1}); !q.Frozen() {
+		t.Error("expected derived copies to be frozen")
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vertex := f.findElement("vertex")
+			vertex.Column("quality")
+			vertex.Records()
+			f.ReadFaces()
+			f.ContentHash()
+			f.Write(new(bytes.Buffer))
+		}()
+	}
+	wg.Wait()
+	if records, stride := f.findElement("vertex").Records(); stride != 20 || len(records) != 80 {
+		t.Errorf("unexpected records of stride %d", stride)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build gofuzz
+// +build gofuzz
+
+package ply
+
+import (
+	"bytes"
+	"io/ioutil"
+)
+
+// Fuzz is the go-fuzz entry point: loading and writing back must never
+// panic, whatever the input.
+func Fuzz(data []byte) int {
+	p := new(PLY)
+	opts := &LoadOptions{CheckFaceIndices: true, Input: &InputPolicy{MaxElementSize: 1 << 20, MaxListLength: 1 << 10}}
+	if p.ReadWithOptions(bytes.NewReader(data), opts) != nil {
+		return 0
+	}
+	p.ReadFaces()
+	p.Write(ioutil.Discard)
+	return 1
+}
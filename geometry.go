@@ -0,0 +1,181 @@
+package ply
+
+import (
+	"math"
+	"sort"
+)
+
+func sub3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func add3(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func scale3(a [3]float64, s float64) [3]float64 {
+	return [3]float64{a[0] * s, a[1] * s, a[2] * s}
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross3(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func length3(a [3]float64) float64 {
+	return math.Sqrt(dot3(a, a))
+}
+
+// normalize3 returns a unit vector, or the zero vector for zero input.
+func normalize3(a [3]float64) [3]float64 {
+	l := length3(a)
+	if l == 0 {
+		return [3]float64{}
+	}
+	return scale3(a, 1/l)
+}
+
+func triangleNormal(a, b, c [3]float64) [3]float64 {
+	return normalize3(cross3(sub3(b, a), sub3(c, a)))
+}
+
+// newellNormal returns the area vector of a possibly non-planar polygon,
+// twice as long as its area. All indices must be valid.
+func newellNormal(face []int, pos [][3]float64) [3]float64 {
+	var n [3]float64
+	for k := range face {
+		a, b := pos[face[k]], pos[face[(k+1)%len(face)]]
+		n[0] += (a[1] - b[1]) * (a[2] + b[2])
+		n[1] += (a[2] - b[2]) * (a[0] + b[0])
+		n[2] += (a[0] - b[0]) * (a[1] + b[1])
+	}
+	return n
+}
+
+// fanTriangles splits a polygon into triangles sharing its first vertex.
+func fanTriangles(face []int) [][3]int {
+	if len(face) < 3 {
+		return nil
+	}
+	tris := make([][3]int, 0, len(face)-2)
+	for k := 1; k+1 < len(face); k++ {
+		tris = append(tris, [3]int{face[0], face[k], face[k+1]})
+	}
+	return tris
+}
+
+// symmetricEigen3 returns the eigenvalues of the symmetric matrix a in
+// ascending order and the matching unit eigenvectors, by Jacobi rotations.
+func symmetricEigen3(a [3][3]float64) ([3]float64, [3][3]float64) {
+	v := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	for sweep := 0; sweep < 50; sweep++ {
+		off := a[0][1]*a[0][1] + a[0][2]*a[0][2] + a[1][2]*a[1][2]
+		diag := a[0][0]*a[0][0] + a[1][1]*a[1][1] + a[2][2]*a[2][2]
+		if off <= 1e-30*diag || off == 0 {
+			break
+		}
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < 3; k++ {
+					a[k][p], a[k][q] = c*a[k][p]-s*a[k][q], s*a[k][p]+c*a[k][q]
+				}
+				for k := 0; k < 3; k++ {
+					a[p][k], a[q][k] = c*a[p][k]-s*a[q][k], s*a[p][k]+c*a[q][k]
+					v[k][p], v[k][q] = c*v[k][p]-s*v[k][q], s*v[k][p]+c*v[k][q]
+				}
+			}
+		}
+	}
+	values := [3]float64{a[0][0], a[1][1], a[2][2]}
+	var vectors [3][3]float64
+	order := [3]int{0, 1, 2}
+	sort.Slice(order[:], func(i, j int) bool { return values[order[i]] < values[order[j]] })
+	var sorted [3]float64
+	for i, k := range order {
+		sorted[i] = values[k]
+		vectors[i] = [3]float64{v[0][k], v[1][k], v[2][k]}
+	}
+	return sorted, vectors
+}
+
+// covariance3 returns the centroid and covariance matrix of the points at
+// indices idx.
+func covariance3(pos [][3]float64, idx []int) ([3]float64, [3][3]float64) {
+	var mean [3]float64
+	for _, i := range idx {
+		mean = add3(mean, pos[i])
+	}
+	mean = scale3(mean, 1/float64(len(idx)))
+	var c [3][3]float64
+	for _, i := range idx {
+		d := sub3(pos[i], mean)
+		for r := 0; r < 3; r++ {
+			for s := r; s < 3; s++ {
+				c[r][s] += d[r] * d[s]
+			}
+		}
+	}
+	for r := 0; r < 3; r++ {
+		for s := r; s < 3; s++ {
+			c[r][s] /= float64(len(idx))
+			c[s][r] = c[r][s]
+		}
+	}
+	return mean, c
+}
+
+// closestOnTriangle returns the point of triangle abc closest to p, after
+// Ericson, Real-Time Collision Detection, 5.1.5.
+func closestOnTriangle(p, a, b, c [3]float64) [3]float64 {
+	ab, ac, ap := sub3(b, a), sub3(c, a), sub3(p, a)
+	d1, d2 := dot3(ab, ap), dot3(ac, ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+	bp := sub3(p, b)
+	d3, d4 := dot3(ab, bp), dot3(ac, bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return add3(a, scale3(ab, d1/(d1-d3)))
+	}
+	cp := sub3(p, c)
+	d5, d6 := dot3(ab, cp), dot3(ac, cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return add3(a, scale3(ac, d2/(d2-d6)))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return add3(b, scale3(sub3(c, b), (d4-d3)/((d4-d3)+(d5-d6))))
+	}
+	denom := va + vb + vc
+	if denom == 0 {
+		// degenerate triangle not caught above
+		return a
+	}
+	v, w := vb/denom, vc/denom
+	return add3(a, add3(scale3(ab, v), scale3(ac, w)))
+}
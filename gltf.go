@@ -0,0 +1,502 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+const (
+	glbMagic     = 0x46546C67
+	glbChunkJSON = 0x4E4F534A
+	glbChunkBIN  = 0x004E4942
+
+	gltfByte          = 5120
+	gltfUnsignedByte  = 5121
+	gltfShort         = 5122
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+	gltfFloat         = 5126
+
+	gltfPoints    = 0
+	gltfTriangles = 4
+
+	gltfArrayBuffer        = 34962
+	gltfElementArrayBuffer = 34963
+)
+
+type gltfDoc struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       *int             `json:"scene,omitempty"`
+	Scenes      []gltfScene      `json:"scenes,omitempty"`
+	Nodes       []gltfNode       `json:"nodes,omitempty"`
+	Meshes      []gltfMesh       `json:"meshes,omitempty"`
+	Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Mesh *int `json:"mesh,omitempty"`
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Mode       *int           `json:"mode,omitempty"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int      `json:"bufferView,omitempty"`
+	ByteOffset    int       `json:"byteOffset,omitempty"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+var gltfComponents = map[string]int{"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4}
+
+var gltfComponentSize = map[int]int{
+	gltfByte: 1, gltfUnsignedByte: 1, gltfShort: 2, gltfUnsignedShort: 2, gltfUnsignedInt: 4, gltfFloat: 4,
+}
+
+type glbBuilder struct {
+	doc gltfDoc
+	bin bytes.Buffer
+}
+
+// addAccessor appends data as a new buffer view and accessor and returns
+// the accessor index.
+func (g *glbBuilder) addAccessor(data []byte, componentType, count int, typ string, normalized bool, target int) int {
+	for g.bin.Len()%4 != 0 {
+		g.bin.WriteByte(0)
+	}
+	view := len(g.doc.BufferViews)
+	g.doc.BufferViews = append(g.doc.BufferViews, gltfBufferView{
+		ByteOffset: g.bin.Len(), ByteLength: len(data), Target: target})
+	g.bin.Write(data)
+	g.doc.Accessors = append(g.doc.Accessors, gltfAccessor{
+		BufferView: &view, ComponentType: componentType, Normalized: normalized, Count: count, Type: typ})
+	return len(g.doc.Accessors) - 1
+}
+
+// ToGLB writes vertex positions, normals (nx, ny, nz), colors (red, green,
+// blue and optional alpha) and triangulated faces as a binary glTF 2.0
+// asset with a single mesh. Files without faces become a point primitive.
+func (p *PLY) ToGLB(w io.Writer) error {
+	vertex := p.findElement("vertex")
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	g := &glbBuilder{}
+	g.doc.Asset = gltfAsset{Version: "2.0", Generator: "go-ply"}
+	prim := gltfPrimitive{Attributes: map[string]int{}}
+
+	buf := make([]byte, 12*len(pos))
+	b := positionBounds(pos)
+	for i, v := range pos {
+		for j := 0; j < 3; j++ {
+			binary.LittleEndian.PutUint32(buf[i*12+j*4:], math.Float32bits(float32(v[j])))
+		}
+	}
+	prim.Attributes["POSITION"] = g.addAccessor(buf, gltfFloat, len(pos), "VEC3", false, gltfArrayBuffer)
+	if b != nil {
+		acc := &g.doc.Accessors[prim.Attributes["POSITION"]]
+		for j := 0; j < 3; j++ {
+			acc.Min = append(acc.Min, float64(float32(b.Min[j])))
+			acc.Max = append(acc.Max, float64(float32(b.Max[j])))
+		}
+	}
+	if normals := vertex.scalarProperties("nx", "ny", "nz"); normals != nil {
+		buf := make([]byte, 12*len(pos))
+		for i := range pos {
+			n := normalize3([3]float64{normals[0].float64At(i), normals[1].float64At(i), normals[2].float64At(i)})
+			for j := 0; j < 3; j++ {
+				binary.LittleEndian.PutUint32(buf[i*12+j*4:], math.Float32bits(float32(n[j])))
+			}
+		}
+		prim.Attributes["NORMAL"] = g.addAccessor(buf, gltfFloat, len(pos), "VEC3", false, gltfArrayBuffer)
+	}
+	if colors := vertex.scalarProperties("red", "green", "blue"); colors != nil {
+		if alpha := vertex.scalarProperties("alpha"); alpha != nil {
+			colors = append(colors, alpha[0])
+		}
+		typ := "VEC3"
+		if len(colors) == 4 {
+			typ = "VEC4"
+		}
+		if colors[0].Type == "uchar" || colors[0].Type == "uint8" {
+			// padded to four-byte vertex strides as required by the spec
+			stride := (len(colors) + 3) &^ 3
+			buf := make([]byte, stride*len(pos))
+			for i := range pos {
+				for j, c := range colors {
+					buf[i*stride+j] = byte(clampFloat64(c.float64At(i), 0, 255))
+				}
+			}
+			idx := g.addAccessor(buf, gltfUnsignedByte, len(pos), typ, true, gltfArrayBuffer)
+			if stride != len(colors) {
+				g.doc.BufferViews[*g.doc.Accessors[idx].BufferView].ByteStride = stride
+			}
+			prim.Attributes["COLOR_0"] = idx
+		} else {
+			buf := make([]byte, 4*len(colors)*len(pos))
+			for i := range pos {
+				for j, c := range colors {
+					v := clampFloat64(c.float64At(i), 0, 1)
+					binary.LittleEndian.PutUint32(buf[(i*len(colors)+j)*4:], math.Float32bits(float32(v)))
+				}
+			}
+			prim.Attributes["COLOR_0"] = g.addAccessor(buf, gltfFloat, len(pos), typ, false, gltfArrayBuffer)
+		}
+	}
+	mode := gltfPoints
+	if p.findElement("face") != nil {
+		tris, _, e := p.stlTriangles()
+		if e != nil {
+			return e
+		}
+		buf := make([]byte, 12*len(tris))
+		for i, t := range tris {
+			for j := 0; j < 3; j++ {
+				binary.LittleEndian.PutUint32(buf[i*12+j*4:], uint32(t[j]))
+			}
+		}
+		idx := g.addAccessor(buf, gltfUnsignedInt, 3*len(tris), "SCALAR", false, gltfElementArrayBuffer)
+		prim.Indices = &idx
+		mode = gltfTriangles
+	}
+	prim.Mode = &mode
+	mesh, scene := 0, 0
+	g.doc.Meshes = []gltfMesh{{Primitives: []gltfPrimitive{prim}}}
+	g.doc.Nodes = []gltfNode{{Mesh: &mesh}}
+	g.doc.Scenes = []gltfScene{{Nodes: []int{0}}}
+	g.doc.Scene = &scene
+	for g.bin.Len()%4 != 0 {
+		g.bin.WriteByte(0)
+	}
+	g.doc.Buffers = []gltfBuffer{{ByteLength: g.bin.Len()}}
+	js, e := json.Marshal(&g.doc)
+	if e != nil {
+		return e
+	}
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+	total := 12 + 8 + len(js) + 8 + g.bin.Len()
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint32(header[0:], glbMagic)
+	binary.LittleEndian.PutUint32(header[4:], 2)
+	binary.LittleEndian.PutUint32(header[8:], uint32(total))
+	if _, e := w.Write(header); e != nil {
+		return e
+	}
+	if e := writeGLBChunk(w, glbChunkJSON, js); e != nil {
+		return e
+	}
+	return writeGLBChunk(w, glbChunkBIN, g.bin.Bytes())
+}
+
+func writeGLBChunk(w io.Writer, typ uint32, data []byte) error {
+	head := make([]byte, 8)
+	binary.LittleEndian.PutUint32(head[0:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(head[4:], typ)
+	if _, e := w.Write(head); e != nil {
+		return e
+	}
+	_, e := w.Write(data)
+	return e
+}
+
+// FromGLB reads the POSITION, NORMAL, COLOR_0 and indices accessors of all
+// point and triangle primitives of a binary glTF 2.0 asset into a PLY.
+// Node transforms are not applied.
+func FromGLB(r io.Reader) (*PLY, error) {
+	data, e := ioutil.ReadAll(r)
+	if e != nil {
+		return nil, e
+	}
+	if len(data) < 12 || binary.LittleEndian.Uint32(data) != glbMagic {
+		return nil, errors.New("Not a binary glTF file")
+	}
+	if binary.LittleEndian.Uint32(data[4:]) != 2 {
+		return nil, errors.New("Unsupported glTF version")
+	}
+	var js, bin []byte
+	for off := 12; off+8 <= len(data); {
+		n := int(binary.LittleEndian.Uint32(data[off:]))
+		typ := binary.LittleEndian.Uint32(data[off+4:])
+		if n < 0 || off+8+n > len(data) {
+			return nil, errors.New("Truncated glTF chunk")
+		}
+		switch typ {
+		case glbChunkJSON:
+			js = data[off+8 : off+8+n]
+		case glbChunkBIN:
+			if bin == nil {
+				bin = data[off+8 : off+8+n]
+			}
+		}
+		off += 8 + (n+3)&^3
+	}
+	var doc gltfDoc
+	if e := json.Unmarshal(js, &doc); e != nil {
+		return nil, errors.New("Invalid glTF JSON: " + e.Error())
+	}
+
+	var pos, normals, colors [][]float64
+	var faces [][]int
+	hasNormal, hasColor, hasAlpha := false, false, false
+	for _, mesh := range doc.Meshes {
+		for _, prim := range mesh.Primitives {
+			mode := gltfTriangles
+			if prim.Mode != nil {
+				mode = *prim.Mode
+			}
+			if mode != gltfTriangles && mode != gltfPoints {
+				return nil, errors.New("Unsupported glTF primitive mode " + itoa(mode))
+			}
+			idx, ok := prim.Attributes["POSITION"]
+			if !ok {
+				continue
+			}
+			if idx < 0 || idx >= len(doc.Accessors) || doc.Accessors[idx].Type != "VEC3" {
+				return nil, errors.New("glTF POSITION must be a VEC3 accessor")
+			}
+			p, e := readGLTFAccessor(&doc, bin, idx)
+			if e != nil {
+				return nil, e
+			}
+			base := len(pos)
+			pos = append(pos, p...)
+			var n, c [][]float64
+			if idx, ok := prim.Attributes["NORMAL"]; ok {
+				if n, e = readGLTFAccessor(&doc, bin, idx); e != nil {
+					return nil, e
+				}
+				hasNormal = true
+			}
+			if idx, ok := prim.Attributes["COLOR_0"]; ok {
+				if c, e = readGLTFAccessor(&doc, bin, idx); e != nil {
+					return nil, e
+				}
+				hasColor = true
+				hasAlpha = hasAlpha || doc.Accessors[idx].Type == "VEC4"
+			}
+			for i := range p {
+				if i < len(n) {
+					normals = append(normals, n[i])
+				} else {
+					normals = append(normals, nil)
+				}
+				if i < len(c) {
+					colors = append(colors, c[i])
+				} else {
+					colors = append(colors, nil)
+				}
+			}
+			if mode != gltfTriangles {
+				continue
+			}
+			var indices []int
+			if prim.Indices != nil {
+				values, e := readGLTFAccessor(&doc, bin, *prim.Indices)
+				if e != nil {
+					return nil, e
+				}
+				for _, v := range values {
+					indices = append(indices, int(v[0]))
+				}
+			} else {
+				for i := range p {
+					indices = append(indices, i)
+				}
+			}
+			for k := 0; k+2 < len(indices); k += 3 {
+				f := []int{base + indices[k], base + indices[k+1], base + indices[k+2]}
+				for _, v := range f {
+					if v < base || v >= len(pos) {
+						return nil, errors.New("glTF index out of range")
+					}
+				}
+				faces = append(faces, f)
+			}
+		}
+	}
+
+	vertex := &Element{Name: "vertex", Size: len(pos)}
+	names := []string{"x", "y", "z"}
+	if hasNormal {
+		names = append(names, "nx", "ny", "nz")
+	}
+	for _, name := range names {
+		vertex.Properties = append(vertex.Properties, newProperty(name, "float", len(pos)))
+	}
+	channels := 0
+	if hasColor {
+		channels = 3
+		if hasAlpha {
+			channels = 4
+		}
+		for _, name := range []string{"red", "green", "blue", "alpha"}[:channels] {
+			vertex.Properties = append(vertex.Properties, newProperty(name, "uchar", len(pos)))
+		}
+	}
+	for i := range pos {
+		values := append([]float64{}, pos[i][:3]...)
+		if hasNormal {
+			n := make([]float64, 3)
+			copy(n, normals[i])
+			values = append(values, n...)
+		}
+		if hasColor {
+			c := []float64{1, 1, 1, 1}
+			copy(c, colors[i])
+			for _, v := range c[:channels] {
+				values = append(values, clampFloat64(v, 0, 1)*255)
+			}
+		}
+		for j, v := range values {
+			vertex.Properties[j].setFloat64At(i, v)
+		}
+	}
+	p := &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian}
+	p.Elements = append(p.Elements, vertex)
+	if len(faces) > 0 {
+		p.Elements = append(p.Elements, faceElement(faces))
+	}
+	return p, nil
+}
+
+// maxGLTFCount returns the largest count of the accessors with a buffer
+// view that the binary chunk can hold.
+func maxGLTFCount(doc *gltfDoc, bin []byte) int {
+	max := 0
+	for _, acc := range doc.Accessors {
+		if acc.BufferView != nil && acc.Count <= len(bin) && acc.Count > max {
+			max = acc.Count
+		}
+	}
+	return max
+}
+
+// readGLTFAccessor decodes an accessor into count tuples, applying
+// normalization of integer components.
+func readGLTFAccessor(doc *gltfDoc, bin []byte, index int) ([][]float64, error) {
+	if index < 0 || index >= len(doc.Accessors) {
+		return nil, errors.New("glTF accessor out of range")
+	}
+	acc := doc.Accessors[index]
+	comps := gltfComponents[acc.Type]
+	size := gltfComponentSize[acc.ComponentType]
+	if comps == 0 || size == 0 || acc.Count < 0 {
+		return nil, errors.New("Unsupported glTF accessor " + itoa(index))
+	}
+	if acc.BufferView == nil {
+		// accessors without a buffer view are all zeros, as long as
+		// accessors holding data
+		if acc.Count > maxGLTFCount(doc, bin) {
+			return nil, errors.New("glTF accessor " + itoa(index) + " without buffer view exceeds the data")
+		}
+		values := make([][]float64, acc.Count)
+		for i := range values {
+			values[i] = make([]float64, comps)
+		}
+		return values, nil
+	}
+	if *acc.BufferView < 0 || *acc.BufferView >= len(doc.BufferViews) {
+		return nil, errors.New("glTF buffer view out of range")
+	}
+	view := doc.BufferViews[*acc.BufferView]
+	if view.Buffer != 0 {
+		return nil, errors.New("External glTF buffers are not supported")
+	}
+	stride := view.ByteStride
+	if stride == 0 {
+		stride = comps * size
+	}
+	if view.ByteOffset < 0 || view.ByteLength < 0 || acc.ByteOffset < 0 || stride < comps*size {
+		return nil, errors.New("Invalid glTF buffer view of accessor " + itoa(index))
+	}
+	// every element takes at least a byte, which also keeps end from
+	// overflowing
+	if acc.Count > len(bin) {
+		return nil, errors.New("glTF accessor " + itoa(index) + " exceeds its buffer")
+	}
+	start := view.ByteOffset + acc.ByteOffset
+	if acc.Count > 0 {
+		end := start + (acc.Count-1)*stride + comps*size
+		if end > view.ByteOffset+view.ByteLength || end > len(bin) {
+			return nil, errors.New("glTF accessor " + itoa(index) + " exceeds its buffer")
+		}
+	}
+	values := make([][]float64, acc.Count)
+	for i := range values {
+		values[i] = make([]float64, comps)
+		for j := 0; j < comps; j++ {
+			b := bin[start+i*stride+j*size:]
+			var v float64
+			switch acc.ComponentType {
+			case gltfByte:
+				v = float64(int8(b[0]))
+				if acc.Normalized {
+					v = math.Max(v/127, -1)
+				}
+			case gltfUnsignedByte:
+				v = float64(b[0])
+				if acc.Normalized {
+					v /= 255
+				}
+			case gltfShort:
+				v = float64(int16(binary.LittleEndian.Uint16(b)))
+				if acc.Normalized {
+					v = math.Max(v/32767, -1)
+				}
+			case gltfUnsignedShort:
+				v = float64(binary.LittleEndian.Uint16(b))
+				if acc.Normalized {
+					v /= 65535
+				}
+			case gltfUnsignedInt:
+				v = float64(binary.LittleEndian.Uint32(b))
+			case gltfFloat:
+				v = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+			}
+			values[i][j] = v
+		}
+	}
+	return values, nil
+}
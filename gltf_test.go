@@ -0,0 +1,103 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestGLBRoundTrip(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 4
+property float x
+property float y
+property float z
+property float nx
+property float ny
+property float nz
+property uchar red
+property uchar green
+property uchar blue
+element face 1
+property list uchar int vertex_indices
+end_header
+0 0 0 0 0 1 255 0 0
+1 0 0 0 0 1 0 255 0
+1 1 0 0 0 1 0 0 255
+0 1 0 0 0 1 10 20 30
+4 0 1 2 3
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := p.ToGLB(&buf); e != nil {
+		t.Fatal(e)
+	}
+	if buf.Len()%4 != 0 || !bytes.HasPrefix(buf.Bytes(), []byte("glTF")) {
+		t.Fatal("invalid GLB container")
+	}
+	q, e := FromGLB(&buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if q.VerticesCount() != 4 {
+		t.Fatalf("expected 4 vertices, got %d", q.VerticesCount())
+	}
+	faces := q.ReadFaces()
+	if len(faces) != 2 || faces[1][0] != 0 || faces[1][1] != 2 || faces[1][2] != 3 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	v := q.GetVertices()
+	if v.findProperty("x").float64At(2) != 1 || v.findProperty("nz").float64At(3) != 1 ||
+		v.findProperty("green").float64At(1) != 255 || v.findProperty("blue").float64At(3) != 30 {
+		t.Error("unexpected vertex attributes")
+	}
+	if _, e := FromGLB(strings.NewReader("not a glb")); e == nil {
+		t.Error("expected error for invalid input")
+	}
+}
+
+// glb wraps a JSON document and a binary chunk into a GLB container.
+func glb(js string, bin []byte) []byte {
+	for len(js)%4 != 0 {
+		js += " "
+	}
+	var buf bytes.Buffer
+	put := func(v uint32) { binary.Write(&buf, binary.LittleEndian, v) }
+	put(glbMagic)
+	put(2)
+	put(uint32(12 + 8 + len(js) + 8 + len(bin)))
+	put(uint32(len(js)))
+	put(glbChunkJSON)
+	buf.WriteString(js)
+	put(uint32(len(bin)))
+	put(glbChunkBIN)
+	buf.Write(bin)
+	return buf.Bytes()
+}
+
+func TestGLBMalformed(t *testing.T) {
+	const mesh = `"meshes":[{"primitives":[{"attributes":{"POSITION":0}}]}]`
+	cases := map[string]string{
+		"negative stride": `{"accessors":[{"bufferView":0,"componentType":5126,"count":2,"type":"VEC3"}],
+			"bufferViews":[{"buffer":0,"byteLength":24,"byteStride":-12}],` + mesh + `}`,
+		"short stride": `{"accessors":[{"bufferView":0,"componentType":5126,"count":2,"type":"VEC3"}],
+			"bufferViews":[{"buffer":0,"byteLength":24,"byteStride":4}],` + mesh + `}`,
+		"negative offset": `{"accessors":[{"bufferView":0,"componentType":5126,"count":1,"type":"VEC3"}],
+			"bufferViews":[{"buffer":0,"byteOffset":-12,"byteLength":24}],` + mesh + `}`,
+		"negative length": `{"accessors":[{"bufferView":0,"componentType":5126,"count":1,"type":"VEC3"}],
+			"bufferViews":[{"buffer":0,"byteLength":-24}],` + mesh + `}`,
+		"huge count": `{"accessors":[{"bufferView":0,"componentType":5126,"count":4611686018427387904,"type":"VEC3"}],
+			"bufferViews":[{"buffer":0,"byteLength":24}],` + mesh + `}`,
+		"huge zero accessor": `{"accessors":[{"componentType":5126,"count":1000000000,"type":"VEC3"}],` + mesh + `}`,
+	}
+	for name, js := range cases {
+		if _, e := FromGLB(bytes.NewReader(glb(js, make([]byte, 24)))); e == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
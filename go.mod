@@ -1,3 +1,5 @@
 module github.com/flywave/go-ply
 
 go 1.13
+
+require gonum.org/v1/gonum v0.8.2
@@ -0,0 +1,81 @@
+package ply
+
+import "errors"
+
+// rows and bytes allocated up front; larger declared sizes grow as data
+// actually arrives, so a lying header cannot exhaust memory
+const (
+	preallocRows  = 1 << 16
+	preallocBytes = 1 << 16
+)
+
+// InputPolicy bounds what a malformed or hostile file can make the reader
+// do. Whatever the policy, malformed input yields an error: negative
+// counts, a header without end_header and a body shorter than its
+// declared rows are always rejected. Zero fields mean no limit.
+type InputPolicy struct {
+	// MaxHeaderLines rejects headers longer than this many lines.
+	MaxHeaderLines int
+	// MaxElementSize rejects elements declaring more rows.
+	MaxElementSize int
+	// MaxListLength rejects list rows holding more items.
+	MaxListLength int
+}
+
+func (policy *InputPolicy) checkHeaderLines(p *PLY) error {
+	if policy != nil && policy.MaxHeaderLines > 0 && p.currentLine > policy.MaxHeaderLines {
+		return errors.New("Header of " + p.filename + " exceeds " +
+			itoa(policy.MaxHeaderLines) + " lines")
+	}
+	return nil
+}
+
+func (policy *InputPolicy) checkElementSize(p *PLY, elem *Element) error {
+	if elem.Size < 0 {
+		return errors.New("Negative size of element " + elem.Name + " in " +
+			p.filename + " at line " + itoa(p.currentLine))
+	}
+	if policy != nil && policy.MaxElementSize > 0 && elem.Size > policy.MaxElementSize {
+		return errors.New("Element " + elem.Name + " in " + p.filename + " exceeds " +
+			itoa(policy.MaxElementSize) + " rows")
+	}
+	return nil
+}
+
+func (policy *InputPolicy) checkListLength(p *PLY, prop *Property, n int) error {
+	if n < 0 {
+		return errors.New("Negative list size of " + prop.Name + " in " + p.filename)
+	}
+	if policy != nil && policy.MaxListLength > 0 && n > policy.MaxListLength {
+		return errors.New("List " + prop.Name + " in " + p.filename + " exceeds " +
+			itoa(policy.MaxListLength) + " items")
+	}
+	return nil
+}
+
+// newRows returns an empty row slice for an element of the given size,
+// preallocating at most preallocRows rows.
+func newRows(size int) [][]byte {
+	if size > preallocRows {
+		size = preallocRows
+	}
+	return make([][]byte, 0, size)
+}
+
+// appendRow appends a row to rows of an element of the given size,
+// doubling the capacity up to size rather than by append's smaller steps.
+func appendRow(rows [][]byte, b []byte, size int) [][]byte {
+	if len(rows) == cap(rows) {
+		n := 2 * cap(rows)
+		if n > size {
+			n = size
+		}
+		if n <= len(rows) {
+			n = len(rows) + 1
+		}
+		grown := make([][]byte, len(rows), n)
+		copy(grown, rows)
+		rows = grown
+	}
+	return append(rows, b)
+}
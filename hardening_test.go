@@ -0,0 +1,81 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+const testBinaryHeader = `ply
+format binary_little_endian 1.0
+element vertex 2
+property float x
+element face 1
+property list uint int vertex_indices
+end_header
+`
+
+// malformed inputs found by fuzzing; each must fail without panicking
+func malformedCorpus() map[string][]byte {
+	corpus := map[string][]byte{
+		"empty":              nil,
+		"magic only":         []byte("ply\n"),
+		"missing end_header": []byte("ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\n"),
+		"blank header lines": []byte("ply\nformat ascii 1.0\n\nelement vertex 1\n\n"),
+		"negative element":   []byte("ply\nformat ascii 1.0\nelement vertex -3\nproperty float x\nend_header\n"),
+		"negative list": []byte("ply\nformat ascii 1.0\nelement face 1\n" +
+			"property list uchar int vertex_indices\nend_header\n-2 0 1\n"),
+		"bare element":    []byte("ply\nformat ascii 1.0\nelement\nend_header\n"),
+		"orphan property": []byte("ply\nformat ascii 1.0\nproperty float x\nelement vertex 1\nend_header\n1\n"),
+		"short property":  []byte("ply\nformat ascii 1.0\nelement vertex 1\nproperty x\nend_header\n1\n"),
+		"short list": []byte("ply\nformat ascii 1.0\nelement face 1\n" +
+			"property list uchar vertex_indices\nend_header\n1 0\n"),
+		"bare obj_info":    []byte("ply\nformat ascii 1.0\nobj_info\nend_header\n"),
+		"short ascii body": []byte(strings.Replace(testASCIIVertices, "element vertex 3", "element vertex 4", 1)),
+		"absurd element": []byte("ply\nformat binary_little_endian 1.0\nelement vertex 2147483647\n" +
+			"property double x\nend_header\n\x00\x00\x00\x00"),
+	}
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, float32(1))
+	corpus["short binary body"] = append([]byte(testBinaryHeader), body.Bytes()...)
+	binary.Write(body, binary.LittleEndian, float32(2))
+	binary.Write(body, binary.LittleEndian, uint32(0xffffffff))
+	binary.Write(body, binary.LittleEndian, int32(0))
+	corpus["absurd list"] = append([]byte(testBinaryHeader), body.Bytes()...)
+	return corpus
+}
+
+func TestMalformedInput(t *testing.T) {
+	for name, data := range malformedCorpus() {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: panic %v", name, r)
+				}
+			}()
+			if e := new(PLY).Read(bytes.NewReader(data)); e == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}()
+	}
+}
+
+func TestInputPolicy(t *testing.T) {
+	limits := []InputPolicy{{MaxHeaderLines: 4}, {MaxElementSize: 2}, {MaxListLength: 2}}
+	for _, policy := range limits {
+		p := new(PLY)
+		e := p.ReadWithOptions(strings.NewReader(testASCIIMesh), &LoadOptions{Input: &policy})
+		if e == nil {
+			t.Errorf("expected %+v to reject the mesh", policy)
+		}
+	}
+	policy := InputPolicy{MaxHeaderLines: 20, MaxElementSize: 4, MaxListLength: 4}
+	p := new(PLY)
+	if e := p.ReadWithOptions(strings.NewReader(testASCIIMesh), &LoadOptions{Input: &policy}); e != nil {
+		t.Fatal(e)
+	}
+	if len(p.ReadFaces()) != 2 {
+		t.Error("expected the mesh to load within the limits")
+	}
+}
@@ -0,0 +1,108 @@
+package ply
+
+import "errors"
+
+// BoundaryLoops returns the closed chains of boundary edges, used by a
+// single face, as vertex loops ordered like a face closing the hole
+// would be. Chains through a vertex on several boundaries are left out, as
+// their loops are ambiguous.
+func (p *PLY) BoundaryLoops() ([][]int, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return nil, e
+	}
+	_, counts := faceEdges(faces)
+	// a boundary edge from a to b in its face closes from b to a
+	next := make(map[int]int)
+	ambiguous := make(map[int]bool)
+	var starts []int
+	for _, f := range faces {
+		for k := range f {
+			a, b := f[k], f[(k+1)%len(f)]
+			if a == b || a < 0 || b < 0 || a >= len(pos) || b >= len(pos) {
+				continue
+			}
+			key := [2]int{a, b}
+			if a > b {
+				key = [2]int{b, a}
+			}
+			if counts[key] != 1 {
+				continue
+			}
+			if _, ok := next[b]; ok {
+				ambiguous[b] = true
+				continue
+			}
+			next[b] = a
+			starts = append(starts, b)
+		}
+	}
+	var loops [][]int
+	visited := make(map[int]bool)
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		loop := []int{start}
+		visited[start] = true
+		ok := !ambiguous[start]
+		for v := next[start]; v != start; v = next[v] {
+			if _, found := next[v]; !found || visited[v] {
+				ok = false
+				break
+			}
+			visited[v] = true
+			ok = ok && !ambiguous[v]
+			loop = append(loop, v)
+		}
+		if ok {
+			loops = append(loops, loop)
+		}
+	}
+	return loops, nil
+}
+
+// FillHoles closes the boundary loops of at most maxEdges edges with
+// triangles, ear clipped in the plane of the loop, and returns the number
+// of holes filled. The new faces get zero or empty values for the other
+// face properties. The outer boundary of an open surface is a loop too, so
+// maxEdges should stay below its length.
+func (p *PLY) FillHoles(maxEdges int) (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	loops, e := p.BoundaryLoops()
+	if e != nil {
+		return 0, e
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return 0, e
+	}
+	face := p.findElement("face")
+	idx := p.faceIndexProperty(face)
+	if idx == nil {
+		return 0, errors.New("Face element has no vertex index list")
+	}
+	filled := 0
+	for _, loop := range loops {
+		if len(loop) < 3 || len(loop) > maxEdges {
+			continue
+		}
+		tris := earClip(loop, pos)
+		if tris == nil {
+			tris = fanTriangles(loop)
+		}
+		for _, t := range tris {
+			if e := face.AppendRow(map[string]interface{}{idx.Name: t[:]}); e != nil {
+				return filled, e
+			}
+		}
+		filled++
+	}
+	return filled, nil
+}
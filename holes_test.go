@@ -0,0 +1,41 @@
+package ply
+
+import "testing"
+
+func TestFillHoles(t *testing.T) {
+	p := gridMesh(t, 4)
+	// remove both triangles of the square at (1, 1)
+	if e := p.findElement("face").DeleteRows([]int{10, 11}); e != nil {
+		t.Fatal(e)
+	}
+	loops, e := p.BoundaryLoops()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(loops) != 2 || len(loops[0])+len(loops[1]) != 20 {
+		t.Fatalf("expected the outer boundary and a hole, got %v", loops)
+	}
+	n, e := p.FillHoles(8)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if n != 1 {
+		t.Errorf("expected one hole filled, got %d", n)
+	}
+	faces := p.ReadFaces()
+	if len(faces) != 32 {
+		t.Errorf("expected 32 faces, got %d", len(faces))
+	}
+	pos, _ := p.Positions()
+	for _, f := range faces[30:] {
+		if n := cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]])); n[2] <= 0 {
+			t.Errorf("filled face %v faces away from the mesh", f)
+		}
+	}
+	if loops, _ = p.BoundaryLoops(); len(loops) != 1 || len(loops[0]) != 16 {
+		t.Errorf("expected only the outer boundary left, got %v", loops)
+	}
+	if n, _ := p.FillHoles(8); n != 0 {
+		t.Errorf("expected the outer boundary left open, got %d filled", n)
+	}
+}
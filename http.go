@@ -0,0 +1,133 @@
+package ply
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Handler serves a PLY over HTTP. The query parameters
+//
+//	bbox=minx,miny,minz,maxx,maxy,maxz  keep only vertices inside the box
+//	props=x,y,z,red                      keep only these vertex properties
+//	format=ascii|binary|binary_big_endian|glb
+//
+// select what is sent; by default the file is sent in its own format.
+type Handler struct {
+	// Source returns the PLY to serve. It is called for every request, so
+	// it may load lazily or consult a cache. The result is not modified,
+	// but read concurrently, so a PLY loaded with LoadOptions.Lazy must be
+	// decoded first, e.g. by DecodeAll.
+	Source func() (*PLY, error)
+}
+
+// NewHandler returns a Handler serving p, decoding any properties left
+// undecoded by LoadOptions.Lazy first.
+func NewHandler(p *PLY) *Handler {
+	p.DecodeAll()
+	return &Handler{Source: func() (*PLY, error) { return p, nil }}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	p, e := h.Source()
+	if e != nil {
+		http.Error(w, e.Error(), http.StatusInternalServerError)
+		return
+	}
+	q := r.URL.Query()
+	if bbox := q.Get("bbox"); bbox != "" {
+		min, max, e := parseBBox(bbox)
+		if e != nil {
+			http.Error(w, e.Error(), http.StatusBadRequest)
+			return
+		}
+		if p, e = p.CropAABB(min, max); e != nil {
+			http.Error(w, e.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if props := q.Get("props"); props != "" {
+		if p, e = p.selectVertexProperties(strings.Split(props, ",")); e != nil {
+			http.Error(w, e.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	out := *p
+	switch q.Get("format") {
+	case "":
+	case "ascii":
+		out.FileType = Ascii
+	case "binary", "binary_little_endian":
+		out.FileType = BinaryLittleEndian
+	case "binary_big_endian":
+		out.FileType = BinaryBigEndian
+	case "glb", "gltf":
+		w.Header().Set("Content-Type", "model/gltf-binary")
+		if r.Method == http.MethodHead {
+			return
+		}
+		if e := out.ToGLB(w); e != nil {
+			http.Error(w, e.Error(), http.StatusBadRequest)
+		}
+		return
+	default:
+		http.Error(w, "unknown format "+q.Get("format"), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/ply")
+	if r.Method == http.MethodHead {
+		return
+	}
+	if e := out.Write(w); e != nil {
+		http.Error(w, e.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseBBox(s string) ([3]float64, [3]float64, error) {
+	var min, max [3]float64
+	parts := strings.Split(s, ",")
+	if len(parts) != 6 {
+		return min, max, errors.New("bbox needs 6 comma separated values")
+	}
+	for k, part := range parts {
+		v, e := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if e != nil {
+			return min, max, errors.New("invalid bbox value " + part)
+		}
+		if k < 3 {
+			min[k] = v
+		} else {
+			max[k-3] = v
+		}
+	}
+	return min, max, nil
+}
+
+// selectVertexProperties returns a shallow copy of p whose vertex element
+// has only the named properties, in the given order.
+func (p *PLY) selectVertexProperties(names []string) (*PLY, error) {
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	for k, elem := range p.Elements {
+		q.Elements[k] = elem
+		if elem.Name != "vertex" {
+			continue
+		}
+		sub := &Element{Name: elem.Name, Size: elem.Size}
+		for _, name := range names {
+			prop := elem.findProperty(strings.TrimSpace(name))
+			if prop == nil {
+				return nil, errors.New("unknown vertex property " + name)
+			}
+			sub.Properties = append(sub.Properties, prop)
+		}
+		q.Elements[k] = sub
+	}
+	return q, nil
+}
@@ -0,0 +1,93 @@
+package ply
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	srv := httptest.NewServer(NewHandler(p))
+	defer srv.Close()
+
+	get := func(query string) (*PLY, int) {
+		resp, e := http.Get(srv.URL + "/?" + query)
+		if e != nil {
+			t.Fatal(e)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, resp.StatusCode
+		}
+		q := new(PLY)
+		if e := q.Read(resp.Body); e != nil {
+			t.Fatal(e)
+		}
+		return q, resp.StatusCode
+	}
+	q, _ := get("bbox=0,0,0,1,0.5,0&props=x,y,z&format=binary")
+	if q == nil || q.FileType != BinaryLittleEndian || q.VerticesCount() != 2 || len(q.GetVertices().Properties) != 3 {
+		t.Fatalf("unexpected cropped result %+v", q)
+	}
+	if q.findElement("face").Size != 0 {
+		t.Error("expected faces crossing the box to be dropped")
+	}
+	q, _ = get("bbox=0,0,0,1,1,0")
+	if q.VerticesCount() != 4 || len(q.ReadFaces()) != 2 || p.VerticesCount() != 4 {
+		t.Error("unexpected full crop")
+	}
+	if _, status := get("props=nope"); status != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", status)
+	}
+	resp, e := http.Get(srv.URL + "/?format=glb")
+	if e != nil {
+		t.Fatal(e)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "model/gltf-binary" {
+		t.Error("unexpected glb content type")
+	}
+}
+
+func TestHandlerLazy(t *testing.T) {
+	src := new(PLY)
+	if e := src.Read(strings.NewReader(benchMesh(50))); e != nil {
+		t.Fatal(e)
+	}
+	src.FileType = BinaryLittleEndian
+	var bin bytes.Buffer
+	src.Write(&bin)
+	p := new(PLY)
+	if e := p.ReadWithOptions(&bin, &LoadOptions{Lazy: true}); e != nil {
+		t.Fatal(e)
+	}
+	srv := httptest.NewServer(NewHandler(p))
+	defer srv.Close()
+	for _, prop := range p.GetVertices().Properties {
+		if prop.block != nil {
+			t.Errorf("expected %s to be decoded", prop.Name)
+		}
+	}
+	// concurrent requests only read the decoded properties
+	var wg sync.WaitGroup
+	for k := 0; k < 8; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, e := http.Get(srv.URL + "/?bbox=0,0,0,10,10,10")
+			if e != nil {
+				t.Error(e)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+}
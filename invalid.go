@@ -0,0 +1,66 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// InvalidValue locates a NaN or infinite value found by FindInvalidValues.
+type InvalidValue struct {
+	Element  string
+	Property string
+	Row      int
+}
+
+// FindInvalidValues lists the rows holding NaN or infinite values in
+// float properties, lists included, one entry per property and row,
+// ordered by element, row and property.
+func (p *PLY) FindInvalidValues() []InvalidValue {
+	var found []InvalidValue
+	for _, elem := range p.Elements {
+		for i := 0; i < elem.Size; i++ {
+			for _, prop := range elem.Properties {
+				if isFloat(prop.Type) && prop.hasInvalidAt(i) {
+					found = append(found, InvalidValue{elem.Name, prop.Name, i})
+				}
+			}
+		}
+	}
+	return found
+}
+
+// RemoveInvalidVertices drops the vertices with a NaN or infinite value in
+// any float property, and the faces referencing them, re-indexing the
+// remaining faces. It returns the number of vertices removed.
+func (p *PLY) RemoveInvalidVertices() (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return 0, errors.New("No vertex element")
+	}
+	invalid := make(map[int]bool)
+	for i := 0; i < vertex.Size; i++ {
+		for _, prop := range vertex.Properties {
+			if isFloat(prop.Type) && prop.hasInvalidAt(i) {
+				invalid[i] = true
+				break
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		p.Elements = p.filterVertices(func(i int) bool { return !invalid[i] }).Elements
+	}
+	return len(invalid), nil
+}
+
+// hasInvalidAt reports whether row i holds a NaN or infinite value.
+func (p *Property) hasInvalidAt(i int) bool {
+	for _, v := range p.listFloat64At(i) {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package ply
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInvalidValues(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if found := p.FindInvalidValues(); found != nil {
+		t.Errorf("expected no invalid values, got %v", found)
+	}
+	vertex := p.findElement("vertex")
+	vertex.findProperty("quality").setFloat64At(3, math.Inf(1))
+	vertex.findProperty("z").setFloat64At(3, math.NaN())
+	vertex.findProperty("x").setFloat64At(0, math.NaN())
+	want := []InvalidValue{{"vertex", "x", 0}, {"vertex", "z", 3}, {"vertex", "quality", 3}}
+	if found := p.FindInvalidValues(); !reflect.DeepEqual(found, want) {
+		t.Errorf("got %v, want %v", found, want)
+	}
+
+	vertex.findProperty("x").setFloat64At(0, 0)
+	n, e := p.RemoveInvalidVertices()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if n != 1 || p.VerticesCount() != 3 || len(p.ReadFaces()) != 1 {
+		t.Errorf("expected the last vertex and its face removed, got %d", n)
+	}
+	if p.FindInvalidValues() != nil {
+		t.Error("expected no invalid values left")
+	}
+	if n, _ := p.RemoveInvalidVertices(); n != 0 {
+		t.Error("expected nothing more to remove")
+	}
+}
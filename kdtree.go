@@ -0,0 +1,234 @@
+package ply
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// KDTree indexes points for nearest neighbor queries. Points with a NaN or
+// infinite coordinate are left out. The tree keeps the slice it was built
+// from, which must not change while the tree is in use; it is safe for
+// concurrent queries.
+type KDTree struct {
+	points [][3]float64
+	// idx holds the indexed points as a balanced tree: the middle of each
+	// range splits it along axis, the widest extent of the range
+	idx  []int
+	axis []uint8
+}
+
+// BuildKDTree indexes the vertex positions of p. Query results are vertex
+// indices.
+func (p *PLY) BuildKDTree() (*KDTree, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	return NewKDTree(pos), nil
+}
+
+// NewKDTree indexes points, to which query results are indices.
+func NewKDTree(points [][3]float64) *KDTree {
+	t := &KDTree{points: points, idx: make([]int, 0, len(points))}
+	for i, v := range points {
+		if !isInvalidPoint(v) {
+			t.idx = append(t.idx, i)
+		}
+	}
+	t.axis = make([]uint8, len(t.idx))
+	t.build(0, len(t.idx))
+	return t
+}
+
+// Len returns the number of indexed points.
+func (t *KDTree) Len() int {
+	return len(t.idx)
+}
+
+// NearestNeighbor returns the index of the point closest to q and its
+// distance, or -1 for an empty tree. Ties go to the lowest index.
+func (t *KDTree) NearestNeighbor(q [3]float64) (int, float64) {
+	best, limit := -1, math.Inf(1)
+	t.walk(0, len(t.idx), q, &limit, func(i int, d2 float64) {
+		if best < 0 || d2 < limit || i < best {
+			best, limit = i, d2
+		}
+	})
+	if best < 0 {
+		return -1, 0
+	}
+	return best, math.Sqrt(limit)
+}
+
+// KNN returns the indices of the k points closest to q, nearest first.
+func (t *KDTree) KNN(q [3]float64, k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	h := &neighborHeap{}
+	limit := math.Inf(1)
+	t.walk(0, len(t.idx), q, &limit, func(i int, d2 float64) {
+		if h.Len() == k {
+			if !h.closer(neighbor{i, d2}, (*h)[0]) {
+				return
+			}
+			heap.Pop(h)
+		}
+		heap.Push(h, neighbor{i, d2})
+		if h.Len() == k {
+			limit = (*h)[0].d2
+		}
+	})
+	return h.sorted()
+}
+
+// RadiusSearch returns the indices of the points within radius of q,
+// bounds included, nearest first.
+func (t *KDTree) RadiusSearch(q [3]float64, radius float64) []int {
+	if !(radius >= 0) {
+		return nil
+	}
+	h := &neighborHeap{}
+	limit := radius * radius
+	t.walk(0, len(t.idx), q, &limit, func(i int, d2 float64) {
+		*h = append(*h, neighbor{i, d2})
+	})
+	return h.sorted()
+}
+
+// within calls visit for the points within radius of q, in no particular
+// order.
+func (t *KDTree) within(q [3]float64, radius float64, visit func(i int)) {
+	limit := radius * radius
+	t.walk(0, len(t.idx), q, &limit, func(i int, d2 float64) { visit(i) })
+}
+
+// neighborhood returns the k points nearest to q, or those within radius
+// if set, at most k of them if k is set too. Neither set means k = 10.
+func (t *KDTree) neighborhood(q [3]float64, k int, radius float64) []int {
+	if radius == 0 {
+		if k == 0 {
+			k = 10
+		}
+		return t.KNN(q, k)
+	}
+	idx := t.RadiusSearch(q, radius)
+	if k > 0 && len(idx) > k {
+		idx = idx[:k]
+	}
+	return idx
+}
+
+func (t *KDTree) build(lo, hi int) {
+	if hi-lo < 2 {
+		return
+	}
+	min, max := t.points[t.idx[lo]], t.points[t.idx[lo]]
+	for _, i := range t.idx[lo+1 : hi] {
+		for j, c := range t.points[i] {
+			min[j], max[j] = math.Min(min[j], c), math.Max(max[j], c)
+		}
+	}
+	axis := uint8(0)
+	for j := uint8(1); j < 3; j++ {
+		if max[j]-min[j] > max[axis]-min[axis] {
+			axis = j
+		}
+	}
+	mid := (lo + hi) / 2
+	t.selectNth(lo, hi, mid, axis)
+	t.axis[mid] = axis
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+// selectNth reorders idx[lo:hi] so that idx[n] holds the point it would
+// hold if sorted along axis, with no greater points before it and no
+// smaller ones after.
+func (t *KDTree) selectNth(lo, hi, n int, axis uint8) {
+	idx, pts := t.idx, t.points
+	for hi-lo > 1 {
+		pivot := pts[idx[lo+(hi-lo)/2]][axis]
+		i, j := lo, hi-1
+		for i <= j {
+			for pts[idx[i]][axis] < pivot {
+				i++
+			}
+			for pts[idx[j]][axis] > pivot {
+				j--
+			}
+			if i <= j {
+				idx[i], idx[j] = idx[j], idx[i]
+				i++
+				j--
+			}
+		}
+		// idx[lo:j+1] are at most pivot, idx[i:hi] at least, and any
+		// between equal to it
+		if n <= j {
+			hi = j + 1
+		} else if n >= i {
+			lo = i
+		} else {
+			return
+		}
+	}
+}
+
+// walk calls visit for the points of idx[lo:hi] whose squared distance to
+// q is at most *limit, which visit may lower to prune the search.
+func (t *KDTree) walk(lo, hi int, q [3]float64, limit *float64, visit func(i int, d2 float64)) {
+	for lo < hi {
+		mid := (lo + hi) / 2
+		i := t.idx[mid]
+		v := t.points[i]
+		d := sub3(q, v)
+		if d2 := dot3(d, d); d2 <= *limit {
+			visit(i, d2)
+		}
+		split := d[t.axis[mid]]
+		nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+		if split > 0 {
+			nearLo, nearHi, farLo, farHi = farLo, farHi, nearLo, nearHi
+		}
+		t.walk(nearLo, nearHi, q, limit, visit)
+		if split*split > *limit {
+			return
+		}
+		lo, hi = farLo, farHi
+	}
+}
+
+type neighbor struct {
+	i  int
+	d2 float64
+}
+
+// neighborHeap is a max-heap of neighbors, the farthest first.
+type neighborHeap []neighbor
+
+func (h neighborHeap) closer(a, b neighbor) bool {
+	return a.d2 < b.d2 || a.d2 == b.d2 && a.i < b.i
+}
+
+func (h neighborHeap) Len() int            { return len(h) }
+func (h neighborHeap) Less(a, b int) bool  { return h.closer(h[b], h[a]) }
+func (h neighborHeap) Swap(a, b int)       { h[a], h[b] = h[b], h[a] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// sorted returns the indices nearest first.
+func (h neighborHeap) sorted() []int {
+	sort.Slice(h, func(a, b int) bool { return h.closer(h[a], h[b]) })
+	indices := make([]int, len(h))
+	for k, n := range h {
+		indices[k] = n.i
+	}
+	return indices
+}
@@ -0,0 +1,100 @@
+package ply
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestKDTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := make([][3]float64, 2000)
+	for i := range points {
+		// coarse coordinates give plenty of ties and duplicates
+		points[i] = [3]float64{float64(r.Intn(20)), float64(r.Intn(20)), float64(r.Intn(5))}
+	}
+	points[7] = [3]float64{math.NaN(), 0, 0}
+	tree := NewKDTree(points)
+	if tree.Len() != len(points)-1 {
+		t.Errorf("expected the NaN point left out, got %d points", tree.Len())
+	}
+	// brute force reference, nearest first and ties by index
+	byDistance := func(q [3]float64) []int {
+		var order []int
+		for i := range points {
+			if i != 7 {
+				order = append(order, i)
+			}
+		}
+		d2 := func(i int) float64 { d := sub3(points[i], q); return dot3(d, d) }
+		sort.SliceStable(order, func(a, b int) bool { return d2(order[a]) < d2(order[b]) })
+		return order
+	}
+	for n := 0; n < 50; n++ {
+		q := [3]float64{r.Float64()*24 - 2, r.Float64()*24 - 2, r.Float64() * 5}
+		want := byDistance(q)
+		if i, d := tree.NearestNeighbor(q); i != want[0] || d != length3(sub3(points[i], q)) {
+			t.Fatalf("nearest to %v: got %d at %v, want %d", q, i, d, want[0])
+		}
+		if got := tree.KNN(q, 10); !equalInts(got, want[:10]) {
+			t.Fatalf("10 nearest to %v: got %v, want %v", q, got, want[:10])
+		}
+		var inside []int
+		for _, i := range want {
+			if length3(sub3(points[i], q)) <= 2.5 {
+				inside = append(inside, i)
+			}
+		}
+		if got := tree.RadiusSearch(q, 2.5); !equalInts(got, inside) {
+			t.Fatalf("within 2.5 of %v: got %v, want %v", q, got, inside)
+		}
+	}
+	if i, _ := NewKDTree(nil).NearestNeighbor([3]float64{}); i != -1 {
+		t.Errorf("expected -1 for an empty tree, got %d", i)
+	}
+	if got := tree.KNN([3]float64{}, len(points)+5); len(got) != tree.Len() {
+		t.Errorf("expected k beyond the size to return every point, got %d", len(got))
+	}
+}
+
+func TestBuildKDTree(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	tree, e := p.BuildKDTree()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if i, d := tree.NearestNeighbor([3]float64{0.9, 1.2, 0}); i != 2 || math.Abs(d-math.Sqrt(0.05)) > 1e-12 {
+		t.Errorf("unexpected nearest vertex %d at %v", i, d)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkKDTreeKNN(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	points := make([][3]float64, 100000)
+	for i := range points {
+		points[i] = [3]float64{r.Float64(), r.Float64(), r.Float64()}
+	}
+	tree := NewKDTree(points)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.KNN(points[i%len(points)], 16)
+	}
+}
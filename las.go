@@ -0,0 +1,188 @@
+package ply
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// LASDecompressor decodes LAZ point data into uncompressed LAS point
+// records. header holds the raw public header block and variable length
+// records (including the LASzip VLR) that precede the point data.
+type LASDecompressor interface {
+	Decompress(header []byte, points io.Reader) (io.Reader, error)
+}
+
+type LASOptions struct {
+	// Decompressor is required for LAZ files.
+	Decompressor LASDecompressor
+}
+
+type lasLayout struct {
+	classification int
+	gpsTime        int
+	rgb            int
+}
+
+var lasLayouts = map[int]lasLayout{
+	0:  {15, -1, -1},
+	1:  {15, 20, -1},
+	2:  {15, -1, 20},
+	3:  {15, 20, 28},
+	4:  {15, 20, -1},
+	5:  {15, 20, 28},
+	6:  {16, 22, -1},
+	7:  {16, 22, 30},
+	8:  {16, 22, 30},
+	9:  {16, 22, -1},
+	10: {16, 22, 30},
+}
+
+// FromLAS reads an ASPRS LAS point cloud into a vertex-only PLY with
+// double x, y, z (scaled and offset per the header), ushort intensity,
+// uchar classification and, where the point format has them, double
+// gps_time and uchar red, green, blue. 16-bit colors are reduced to 8 bits
+// unless all values already fit.
+func FromLAS(r io.Reader, opts *LASOptions) (*PLY, error) {
+	if opts == nil {
+		opts = &LASOptions{}
+	}
+	br := bufio.NewReader(r)
+	head := make([]byte, 227)
+	if _, e := io.ReadFull(br, head); e != nil {
+		return nil, errors.New("Truncated LAS header")
+	}
+	if string(head[:4]) != "LASF" {
+		return nil, errors.New("Not a LAS file")
+	}
+	le := binary.LittleEndian
+	headerSize := int(le.Uint16(head[94:]))
+	pointOffset := int(le.Uint32(head[96:]))
+	format := int(head[104])
+	recordLen := int(le.Uint16(head[105:]))
+	count := uint64(le.Uint32(head[107:]))
+	var scale, offset [3]float64
+	for j := 0; j < 3; j++ {
+		scale[j] = math.Float64frombits(le.Uint64(head[131+8*j:]))
+		offset[j] = math.Float64frombits(le.Uint64(head[155+8*j:]))
+	}
+	if headerSize < len(head) || pointOffset < headerSize {
+		return nil, errors.New("Invalid LAS header size")
+	}
+	// the header block is at most 64 KiB; the records up to the points,
+	// whose offset may be anything, are read as they arrive and only kept
+	// for a decompressor
+	header := make([]byte, headerSize)
+	copy(header, head)
+	if _, e := io.ReadFull(br, header[len(head):]); e != nil {
+		return nil, errors.New("Truncated LAS header")
+	}
+	if count == 0 && header[24] == 1 && header[25] >= 4 && len(header) >= 255 {
+		count = le.Uint64(header[247:])
+	}
+	gap := int64(pointOffset - headerSize)
+	var points io.Reader = br
+	if format&0xc0 != 0 {
+		if opts.Decompressor == nil {
+			return nil, errors.New("LAZ data needs a LASDecompressor")
+		}
+		format &^= 0xc0
+		records := bytes.NewBuffer(header)
+		if n, _ := io.CopyN(records, br, gap); n != gap {
+			return nil, errors.New("Truncated LAS header")
+		}
+		var e error
+		if points, e = opts.Decompressor.Decompress(records.Bytes(), br); e != nil {
+			return nil, e
+		}
+	} else if n, _ := io.CopyN(ioutil.Discard, br, gap); n != gap {
+		return nil, errors.New("Truncated LAS header")
+	}
+	layout, ok := lasLayouts[format]
+	if !ok {
+		return nil, errors.New("Unsupported LAS point format " + itoa(format))
+	}
+	minLen := 20
+	if layout.gpsTime >= 0 {
+		minLen = layout.gpsTime + 8
+	}
+	if layout.rgb >= 0 {
+		minLen = layout.rgb + 6
+	}
+	if recordLen < minLen {
+		return nil, errors.New("LAS point record too short for format " + itoa(format))
+	}
+	if count > math.MaxInt32 {
+		return nil, errors.New("Too many LAS points")
+	}
+	n := int(count)
+
+	names := []string{"x", "y", "z", "intensity", "classification"}
+	types := []string{"double", "double", "double", "ushort", "uchar"}
+	if layout.gpsTime >= 0 {
+		names = append(names, "gps_time")
+		types = append(types, "double")
+	}
+	if layout.rgb >= 0 {
+		names = append(names, "red", "green", "blue")
+		types = append(types, "uchar", "uchar", "uchar")
+	}
+	vertex := &Element{Name: "vertex", Size: n}
+	for j, name := range names {
+		prop := newProperty(name, types[j], 0)
+		prop.Data = newRows(n)
+		prop.pos = j
+		vertex.Properties = append(vertex.Properties, prop)
+	}
+	var rgb [][3]uint16
+	wide := false
+	rec := make([]byte, recordLen)
+	for i := 0; i < n; i++ {
+		if _, e := io.ReadFull(points, rec); e != nil {
+			return nil, errors.New("Truncated LAS point data at point " + itoa(i))
+		}
+		values := make([]float64, 0, len(names))
+		for j := 0; j < 3; j++ {
+			values = append(values, float64(int32(le.Uint32(rec[4*j:])))*scale[j]+offset[j])
+		}
+		class := rec[layout.classification]
+		if format < 6 {
+			class &= 0x1f
+		}
+		values = append(values, float64(le.Uint16(rec[12:])), float64(class))
+		if layout.gpsTime >= 0 {
+			values = append(values, math.Float64frombits(le.Uint64(rec[layout.gpsTime:])))
+		}
+		for j, v := range values {
+			prop := vertex.Properties[j]
+			prop.Data = appendRow(prop.Data, encodeFloat64(v, prop.Type, le), n)
+		}
+		if layout.rgb >= 0 {
+			var c [3]uint16
+			for j := 0; j < 3; j++ {
+				c[j] = le.Uint16(rec[layout.rgb+2*j:])
+				wide = wide || c[j] > 255
+			}
+			rgb = append(rgb, c)
+		}
+	}
+	if layout.rgb >= 0 {
+		colors := vertex.Properties[len(names)-3:]
+		for _, c := range rgb {
+			for j := 0; j < 3; j++ {
+				v := c[j]
+				if wide {
+					v >>= 8
+				}
+				colors[j].Data = appendRow(colors[j].Data, []byte{byte(v)}, n)
+			}
+		}
+	}
+	// drain trailing data such as extended VLRs so callers can reuse r
+	io.Copy(ioutil.Discard, points)
+	return &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian, Elements: []*Element{vertex}}, nil
+}
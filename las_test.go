@@ -0,0 +1,92 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"runtime"
+	"testing"
+)
+
+func testLAS(format byte, recordLen int, points [][3]int32) []byte {
+	le := binary.LittleEndian
+	head := make([]byte, 227)
+	copy(head, "LASF")
+	head[24], head[25] = 1, 2
+	le.PutUint16(head[94:], 227)
+	le.PutUint32(head[96:], 227)
+	head[104] = format
+	le.PutUint16(head[105:], uint16(recordLen))
+	le.PutUint32(head[107:], uint32(len(points)))
+	for j := 0; j < 3; j++ {
+		le.PutUint64(head[131+8*j:], math.Float64bits(0.01))
+		le.PutUint64(head[155+8*j:], math.Float64bits(float64(1000*j)))
+	}
+	buf := bytes.NewBuffer(head)
+	for i, pt := range points {
+		rec := make([]byte, recordLen)
+		for j := 0; j < 3; j++ {
+			le.PutUint32(rec[4*j:], uint32(pt[j]))
+		}
+		le.PutUint16(rec[12:], uint16(100*i))
+		rec[15] = 0xe0 | 2
+		le.PutUint64(rec[20:], math.Float64bits(float64(i)+0.5))
+		le.PutUint16(rec[28:], 65535)
+		le.PutUint16(rec[30:], 256*uint16(i))
+		buf.Write(rec)
+	}
+	return buf.Bytes()
+}
+
+type copyDecompressor struct{ called bool }
+
+func (d *copyDecompressor) Decompress(header []byte, points io.Reader) (io.Reader, error) {
+	d.called = true
+	return points, nil
+}
+
+func TestFromLAS(t *testing.T) {
+	data := testLAS(3, 34, [][3]int32{{100, 200, -300}, {1, 2, 3}})
+	p, e := FromLAS(bytes.NewReader(data), nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	if v.Size != 2 || math.Abs(v.findProperty("y").float64At(0)-1002) > 1e-9 ||
+		math.Abs(v.findProperty("z").float64At(0)-1997) > 1e-9 {
+		t.Errorf("unexpected coordinates")
+	}
+	if v.findProperty("classification").float64At(1) != 2 || v.findProperty("intensity").float64At(1) != 100 ||
+		v.findProperty("gps_time").float64At(1) != 1.5 || v.findProperty("red").float64At(0) != 255 ||
+		v.findProperty("green").float64At(1) != 1 {
+		t.Errorf("unexpected attributes")
+	}
+	data[104] |= 0x80
+	if _, e := FromLAS(bytes.NewReader(data), nil); e == nil {
+		t.Error("expected LAZ without decompressor to fail")
+	}
+	d := &copyDecompressor{}
+	if _, e := FromLAS(bytes.NewReader(data), &LASOptions{Decompressor: d}); e != nil || !d.called {
+		t.Errorf("decompressor not used: %v", e)
+	}
+}
+
+func TestFromLASLyingHeader(t *testing.T) {
+	le := binary.LittleEndian
+	offset := testLAS(0, 34, nil)
+	le.PutUint32(offset[96:], math.MaxUint32)
+	count := testLAS(0, 34, [][3]int32{{1, 2, 3}})
+	le.PutUint32(count[107:], math.MaxInt32)
+	for name, data := range map[string][]byte{"point offset": offset, "point count": count} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if _, e := FromLAS(bytes.NewReader(data), nil); e == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		runtime.ReadMemStats(&after)
+		if grown := after.TotalAlloc - before.TotalAlloc; grown > 1<<24 {
+			t.Errorf("%s: allocated %d bytes for a %d byte file", name, grown, len(data))
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package ply
+
+import "errors"
+
+// In-memory layouts for SetLayout. Whatever the layout, Property.Data
+// holds one slice per row; layouts only change the memory behind them.
+const (
+	// ScatteredLayout allocates every row separately, as decoding does.
+	ScatteredLayout = iota
+	// RowMajor packs the scalar properties of each row into one record,
+	// for fast row iteration and struct decoding. See Element.Records.
+	RowMajor
+	// ColumnMajor packs each property's rows into one column, for fast
+	// per-property math. See Element.Column.
+	ColumnMajor
+)
+
+// SetLayout rearranges the element's data into layout. Lists are packed
+// into one buffer per property for both RowMajor and ColumnMajor. Missing
+// scalar rows become zero. Operations replacing rows afterwards may break
+// the packing; Column and Records restore it when needed.
+func (e *Element) SetLayout(layout int) error {
+	if e.frozen {
+		return ErrFrozen
+	}
+	switch layout {
+	case ScatteredLayout:
+		for _, prop := range e.Properties {
+			prop.load()
+			for i := 0; i < e.Size && i < len(prop.Data); i++ {
+				prop.Data[i] = append([]byte(nil), prop.Data[i]...)
+			}
+			prop.column = nil
+		}
+		e.records = nil
+	case RowMajor:
+		e.packRecords()
+		for _, prop := range e.Properties {
+			if prop.IsList {
+				prop.packColumn(e.Size)
+			}
+		}
+	case ColumnMajor:
+		for _, prop := range e.Properties {
+			prop.packColumn(e.Size)
+		}
+		e.records = nil
+	default:
+		return errors.New("Unknown layout " + itoa(layout))
+	}
+	return nil
+}
+
+// Column returns the values of a scalar property as one contiguous buffer
+// in the property's byte order, packing the column first if needed. Rows
+// share the buffer, so writes to it change the property.
+func (e *Element) Column(name string) ([]byte, error) {
+	prop := e.findProperty(name)
+	if prop == nil {
+		return nil, errors.New("No property " + name + " in element " + e.Name)
+	}
+	if prop.IsList {
+		return nil, errors.New("Property " + name + " is a list")
+	}
+	if prop.isPacked(prop.column, e.Size, SizeOfType[prop.Type], 0) {
+		return prop.column, nil
+	}
+	if e.frozen {
+		// pack a copy; frozen elements are read concurrently
+		cp := *prop
+		prop = &cp
+	}
+	prop.packColumn(e.Size)
+	return prop.column, nil
+}
+
+// Records returns the scalar properties as consecutive records of stride
+// bytes, fields in declaration order and each in its property's byte
+// order, packing them first if needed. Rows share the buffer, so writes to
+// it change the properties.
+func (e *Element) Records() (records []byte, stride int) {
+	off := 0
+	packed := e.records != nil
+	for _, prop := range e.Properties {
+		if prop.IsList {
+			continue
+		}
+		size := SizeOfType[prop.Type]
+		packed = packed && prop.isPacked(e.records, e.Size, e.stride, off)
+		off += size
+	}
+	if packed && off == e.stride {
+		return e.records, e.stride
+	}
+	if e.frozen {
+		c := &Element{Size: e.Size}
+		for _, prop := range e.Properties {
+			cp := *prop
+			c.Properties = append(c.Properties, &cp)
+		}
+		e = c
+	}
+	e.packRecords()
+	return e.records, e.stride
+}
+
+// isPacked reports whether row i of p is buf[i*stride+off:] for all rows.
+func (p *Property) isPacked(buf []byte, n, stride, off int) bool {
+	size := SizeOfType[p.Type]
+	p.load()
+	if len(p.Data) < n || len(buf) < n*stride {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		row := p.Data[i]
+		if len(row) != size || size > 0 && &row[0] != &buf[i*stride+off] {
+			return false
+		}
+	}
+	return true
+}
+
+// packColumn copies the first n rows of p into one buffer and points the
+// rows into it. Each row's capacity ends at its length so that appending
+// to a row cannot overwrite the next one.
+func (p *Property) packColumn(n int) {
+	size := SizeOfType[p.Type]
+	total := 0
+	for i := 0; i < n; i++ {
+		if p.IsList {
+			total += len(p.row(i))
+		} else {
+			total += size
+		}
+	}
+	buf := make([]byte, total)
+	data := make([][]byte, n)
+	at := 0
+	for i := range data {
+		row := p.row(i)
+		if !p.IsList {
+			row = p.scalarRow(i)
+			if row == nil {
+				row = make([]byte, size)
+			}
+		}
+		end := at + len(row)
+		copy(buf[at:end], row)
+		data[i] = buf[at:end:end]
+		at = end
+	}
+	p.Data = data
+	p.column = nil
+	if !p.IsList {
+		p.column = buf
+	}
+}
+
+// packRecords interleaves the scalar properties into e.records.
+func (e *Element) packRecords() {
+	stride := 0
+	for _, prop := range e.Properties {
+		if !prop.IsList {
+			stride += SizeOfType[prop.Type]
+		}
+	}
+	buf := make([]byte, e.Size*stride)
+	off := 0
+	for _, prop := range e.Properties {
+		if prop.IsList {
+			continue
+		}
+		size := SizeOfType[prop.Type]
+		data := make([][]byte, e.Size)
+		for i := range data {
+			at := i*stride + off
+			if row := prop.scalarRow(i); row != nil {
+				copy(buf[at:at+size], row)
+			}
+			data[i] = buf[at : at+size : at+size]
+		}
+		prop.Data = data
+		prop.column = nil
+		off += size
+	}
+	e.records, e.stride = buf, stride
+}
@@ -0,0 +1,71 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLayouts(t *testing.T) {
+	p := new(PLY)
+	if e := p.ReadWithOptions(strings.NewReader(testASCIIMesh), &LoadOptions{Layout: ColumnMajor}); e != nil {
+		t.Fatal(e)
+	}
+	vertex := p.findElement("vertex")
+	x, e := vertex.Column("x")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(x) != 16 || math.Float32frombits(binary.LittleEndian.Uint32(x[4:])) != 1 {
+		t.Errorf("unexpected x column %v", x)
+	}
+	binary.LittleEndian.PutUint32(x[4:], math.Float32bits(5))
+	if vertex.findProperty("x").float64At(1) != 5 {
+		t.Error("expected writes to the column to change the property")
+	}
+	if _, e := p.findElement("face").Column("vertex_indices"); e == nil {
+		t.Error("expected an error for a list column")
+	}
+
+	records, stride := vertex.Records()
+	if stride != 20 || len(records) != 80 {
+		t.Fatalf("unexpected records of stride %d", stride)
+	}
+	if q := math.Float64frombits(binary.LittleEndian.Uint64(records[2*stride+12:])); q != -1e-05 {
+		t.Errorf("unexpected quality %v", q)
+	}
+	// replacing a row breaks the packing, which Column restores
+	vertex.findProperty("x").setFloat64At(1, 2)
+	vertex.findProperty("x").Data[3] = encodeFloat64(7, "float", binary.LittleEndian)
+	x, _ = vertex.Column("x")
+	if math.Float32frombits(binary.LittleEndian.Uint32(x[12:])) != 7 {
+		t.Error("expected Column to repack replaced rows")
+	}
+
+	face := p.findElement("face")
+	if e := face.SetLayout(RowMajor); e != nil {
+		t.Fatal(e)
+	}
+	idx := face.findProperty("vertex_indices")
+	idx.Data[0] = append(idx.Data[0], 0, 0, 0, 0)
+	if faces := p.ReadFaces(); len(faces[0]) != 4 || len(faces[1]) != 4 {
+		t.Errorf("expected appending to a packed row to leave the next one intact, got %v", faces)
+	}
+	for _, layout := range []int{ScatteredLayout, RowMajor, ColumnMajor} {
+		if e := vertex.SetLayout(layout); e != nil {
+			t.Fatal(e)
+		}
+		var out bytes.Buffer
+		if e := p.Write(&out); e != nil {
+			t.Fatal(e)
+		}
+		if !strings.Contains(out.String(), "\n2 0 0 0.25\n") {
+			t.Errorf("layout %d: unexpected output\n%s", layout, out.String())
+		}
+	}
+	if e := vertex.SetLayout(7); e == nil {
+		t.Error("expected an error for an unknown layout")
+	}
+}
@@ -0,0 +1,96 @@
+package ply
+
+import "io"
+
+// recordBlock holds the rows of an element without lists as they were
+// read: consecutive records of stride bytes, in chunks. The last chunk may
+// end with a partial record when the body was truncated.
+type recordBlock struct {
+	chunks [][]byte
+	stride int
+}
+
+// readRecords reads rows rows of elem, whose rows are stride bytes, in
+// chunks of whole records rather than value by value. Unless lazy, the
+// rows of every property are then pointed into the chunks; values keep
+// their file encoding either way. A truncated body is decoded at once so
+// that its complete rows can be kept.
+func (p *PLY) readRecords(elem *Element, rows, stride int, lazy bool) error {
+	block := &recordBlock{stride: stride}
+	offset := 0
+	for _, prop := range elem.Properties {
+		prop.Data, prop.block, prop.offset = nil, block, offset
+		offset += SizeOfType[prop.Type]
+	}
+	total := 0
+	var e error
+	for i := 0; i < rows && e == nil; {
+		max := slabSize / stride
+		if max == 0 {
+			max = 1
+		}
+		k := rows - i
+		if k > max {
+			k = max
+		}
+		size := k * stride
+		if k == slabSize/stride {
+			// a full slab, reusable by a pooled Decoder
+			size = slabSize
+		}
+		chunk := p.alloc(size)[:k*stride]
+		var n int
+		n, e = io.ReadFull(p.reader, chunk)
+		block.chunks = append(block.chunks, chunk[:n])
+		total += n
+		i += k
+	}
+	if !lazy || e != nil {
+		for _, prop := range elem.Properties {
+			prop.load()
+		}
+	}
+	if e == io.EOF || e == io.ErrUnexpectedEOF {
+		// name the first value missing from the cut record
+		rest := total % stride
+		for _, prop := range elem.Properties {
+			if prop.offset+SizeOfType[prop.Type] > rest {
+				return p.unexpectedEnd(elem, total/stride, prop.Name)
+			}
+		}
+	}
+	return e
+}
+
+// load decodes a lazily loaded property, pointing its rows into its
+// records. It does nothing for other properties.
+func (p *Property) load() {
+	block := p.block
+	if block == nil {
+		return
+	}
+	size := SizeOfType[p.Type]
+	n := 0
+	for _, chunk := range block.chunks {
+		n += (len(chunk) - p.offset - size + block.stride) / block.stride
+	}
+	data := make([][]byte, 0, n)
+	for _, chunk := range block.chunks {
+		for off := p.offset; off+size <= len(chunk); off += block.stride {
+			data = append(data, chunk[off:off+size:off+size])
+		}
+	}
+	p.Data, p.block = data, nil
+}
+
+// DecodeAll decodes the properties left undecoded by LoadOptions.Lazy.
+// Methods of p decode the properties they use by themselves; only code
+// reading Property.Data directly, or reading p from several goroutines,
+// needs to call DecodeAll first.
+func (p *PLY) DecodeAll() {
+	for _, elem := range p.Elements {
+		for _, prop := range elem.Properties {
+			prop.load()
+		}
+	}
+}
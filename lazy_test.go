@@ -0,0 +1,57 @@
+package ply
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	data := benchBinary(t, 1000)
+	eager := new(PLY)
+	if e := eager.Read(bytes.NewReader(data)); e != nil {
+		t.Fatal(e)
+	}
+	p := new(PLY)
+	if e := p.ReadWithOptions(bytes.NewReader(data), &LoadOptions{Lazy: true}); e != nil {
+		t.Fatal(e)
+	}
+	vertex := p.Elements[0]
+	for _, prop := range vertex.Properties {
+		if prop.Data != nil {
+			t.Fatalf("expected %s to be left undecoded", prop.Name)
+		}
+	}
+	if p.Elements[1].Properties[0].Data == nil {
+		t.Error("expected the list property to be decoded")
+	}
+	pos := p.ReadVertices()
+	if len(pos[0]) != 1000 || pos[2][999] != eager.ReadVertices()[2][999] {
+		t.Errorf("unexpected positions %v", pos[2][999])
+	}
+	if red := vertex.Properties[3]; red.Data != nil {
+		t.Error("expected only the positions to be decoded")
+	}
+
+	q, e := p.rowSubset(map[string]RowRange{"vertex": {Offset: 10, Count: 10}})
+	if e != nil || q.VerticesCount() != 10 || q.ReadVertices()[0][0] != pos[0][10] {
+		t.Errorf("unexpected subset %v", e)
+	}
+	if p.ContentHash() != eager.ContentHash() {
+		t.Error("expected the lazy load to match the eager one")
+	}
+	p.DecodeAll()
+	for _, prop := range vertex.Properties {
+		if len(prop.Data) != 1000 {
+			t.Errorf("expected %s to be decoded", prop.Name)
+		}
+	}
+
+	cut := data[:len(data)-len(data)/2]
+	for _, lazy := range []bool{false, true} {
+		q := new(PLY)
+		e := q.ReadWithOptions(bytes.NewReader(cut), &LoadOptions{Lazy: lazy, KeepTruncatedRows: true})
+		if _, ok := e.(*TruncatedError); !ok || q.Elements[0].findProperty("blue").row(q.VerticesCount()-1) == nil {
+			t.Errorf("lazy %v: unexpected truncated load %v", lazy, e)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Lists decodes a list property: the number of items of every row, and
+// the items of all rows one row after the other, so that row i starts
+// after the items of the rows before it.
+func (p *Property) Lists() (counts []int, values []float64, err error) {
+	size, err := p.listItemSize()
+	if err != nil {
+		return nil, nil, err
+	}
+	counts, total := p.listCounts(size)
+	values = make([]float64, 0, total)
+	order := p.byteOrder()
+	for i := range counts {
+		b := p.Data[i]
+		for k := 0; k+size <= len(b); k += size {
+			values = append(values, scalarFloat64(b[k:k+size], p.Type, order))
+		}
+	}
+	return counts, values, nil
+}
+
+// IntLists is like Lists for lists of integral items, e.g. vertex
+// indices, which it decodes exactly.
+func (p *Property) IntLists() (counts []int, values []int64, err error) {
+	size, err := p.listItemSize()
+	if err != nil {
+		return nil, nil, err
+	}
+	if isFloat(p.Type) || codecs[p.Type] != nil {
+		return nil, nil, errors.New("Property " + p.Name + " holds " + p.Type + " items")
+	}
+	counts, total := p.listCounts(size)
+	values = make([]int64, 0, total)
+	order := p.byteOrder()
+	for i := range counts {
+		b := p.Data[i]
+		for k := 0; k+size <= len(b); k += size {
+			values = append(values, intValue(b[k:k+size], p.Type, order))
+		}
+	}
+	return counts, values, nil
+}
+
+func (p *Property) listItemSize() (int, error) {
+	if !p.IsList {
+		return 0, errors.New("Property " + p.Name + " is not a list")
+	}
+	size := SizeOfType[p.Type]
+	if size == 0 {
+		return 0, errors.New("Invalid type " + p.Type)
+	}
+	p.load()
+	return size, nil
+}
+
+// listCounts returns the item count of every row and their sum.
+func (p *Property) listCounts(size int) ([]int, int) {
+	counts := make([]int, len(p.Data))
+	total := 0
+	for i, b := range p.Data {
+		counts[i] = len(b) / size
+		total += counts[i]
+	}
+	return counts, total
+}
+
+// intValue decodes an integral value of typeName.
+func intValue(b []byte, typeName string, order binary.ByteOrder) int64 {
+	switch typeName {
+	case "int8", "char":
+		return int64(int8(b[0]))
+	case "uint8", "uchar":
+		return int64(b[0])
+	case "int16", "short":
+		return int64(int16(order.Uint16(b)))
+	case "uint16", "ushort":
+		return int64(order.Uint16(b))
+	case "int32", "int":
+		return int64(int32(order.Uint32(b)))
+	}
+	return int64(order.Uint32(b))
+}
@@ -0,0 +1,55 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLists(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element face 3
+property list uchar uint vertex_indices
+property list uchar float texcoord
+property uchar flags
+end_header
+3 0 1 4000000000 6 0 0 1 0 0 1 7
+0 0 0
+4 3 2 1 0 2 0.5 0.25 1
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	face := p.findElement("face")
+	counts, values, e := face.findProperty("vertex_indices").IntLists()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !equalInts(counts, []int{3, 0, 4}) {
+		t.Errorf("unexpected counts %v", counts)
+	}
+	want := []int64{0, 1, 4000000000, 3, 2, 1, 0}
+	if len(values) != len(want) {
+		t.Fatalf("expected %v, got %v", want, values)
+	}
+	for k := range want {
+		if values[k] != want[k] {
+			t.Errorf("expected %v, got %v", want, values)
+			break
+		}
+	}
+	counts, uv, e := face.findProperty("texcoord").Lists()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !equalInts(counts, []int{6, 0, 2}) || len(uv) != 8 || uv[1] != 0 || uv[2] != 1 || uv[6] != 0.5 || uv[7] != 0.25 {
+		t.Errorf("unexpected texcoords %v %v", counts, uv)
+	}
+	if _, _, e := face.findProperty("texcoord").IntLists(); e == nil {
+		t.Error("expected an error for float items")
+	}
+	if _, _, e := face.findProperty("flags").Lists(); e == nil {
+		t.Error("expected an error for a scalar property")
+	}
+}
@@ -0,0 +1,110 @@
+package ply
+
+import (
+	"container/list"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type loaderKey struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+type loaderEntry struct {
+	key  loaderKey
+	done chan struct{}
+	ply  *PLY
+	err  error
+}
+
+// Loader loads PLY files through an LRU cache keyed by path, modification
+// time and size, so a file is decoded again only after it changed. It is
+// safe for concurrent use. The returned PLYs are frozen snapshots shared
+// between callers, see Freeze.
+type Loader struct {
+	// Options are passed to every load. Properties left undecoded by
+	// LoadOptions.Lazy are decoded by freezing the PLY.
+	Options *LoadOptions
+
+	capacity int
+	mu       sync.Mutex
+	lru      *list.List
+	entries  map[string]*list.Element
+}
+
+// NewLoader returns a Loader caching up to capacity files.
+func NewLoader(capacity int) *Loader {
+	return &Loader{capacity: capacity, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (l *Loader) Load(path string) (*PLY, error) {
+	if l.capacity <= 0 {
+		return nil, errors.New("Loader capacity must be positive")
+	}
+	abs, e := filepath.Abs(path)
+	if e != nil {
+		return nil, e
+	}
+	info, e := os.Stat(abs)
+	if e != nil {
+		return nil, e
+	}
+	key := loaderKey{abs, info.ModTime(), info.Size()}
+
+	l.mu.Lock()
+	if el, ok := l.entries[abs]; ok {
+		entry := el.Value.(*loaderEntry)
+		if entry.key == key {
+			l.lru.MoveToFront(el)
+			l.mu.Unlock()
+			<-entry.done
+			return entry.ply, entry.err
+		}
+		l.lru.Remove(el)
+		delete(l.entries, abs)
+	}
+	entry := &loaderEntry{key: key, done: make(chan struct{})}
+	l.entries[abs] = l.lru.PushFront(entry)
+	for l.lru.Len() > l.capacity {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.entries, oldest.Value.(*loaderEntry).key.path)
+	}
+	l.mu.Unlock()
+
+	p := new(PLY)
+	entry.err = p.LoadWithOptions(abs, l.Options)
+	if entry.err == nil {
+		entry.ply = p.Freeze()
+	} else {
+		// do not cache failures
+		l.mu.Lock()
+		if el, ok := l.entries[abs]; ok && el.Value == entry {
+			l.lru.Remove(el)
+			delete(l.entries, abs)
+		}
+		l.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.ply, entry.err
+}
+
+// Len returns the number of cached files.
+func (l *Loader) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lru.Len()
+}
+
+// Purge empties the cache.
+func (l *Loader) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lru.Init()
+	l.entries = make(map[string]*list.Element)
+}
@@ -0,0 +1,82 @@
+package ply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoader(t *testing.T) {
+	dir, e := ioutil.TempDir("", "ply")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	names := []string{"a.ply", "b.ply", "c.ply"}
+	for _, name := range names {
+		if e := ioutil.WriteFile(filepath.Join(dir, name), []byte(testASCIIVertices), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	l := NewLoader(2)
+	a1, e := l.Load(filepath.Join(dir, "a.ply"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	a2, _ := l.Load(filepath.Join(dir, "a.ply"))
+	if a1 != a2 {
+		t.Error("expected cached snapshot")
+	}
+	if e := a1.ScaleUnits(2); e != ErrFrozen {
+		t.Errorf("expected a frozen snapshot, got %v", e)
+	}
+	l.Load(filepath.Join(dir, "b.ply"))
+	l.Load(filepath.Join(dir, "c.ply"))
+	if l.Len() != 2 {
+		t.Errorf("expected 2 cached files, got %d", l.Len())
+	}
+	if a3, _ := l.Load(filepath.Join(dir, "a.ply")); a3 == a1 {
+		t.Error("expected evicted file to be reloaded")
+	}
+	c := filepath.Join(dir, "c.ply")
+	c1, _ := l.Load(c)
+	changed := strings.Replace(testASCIIVertices, "vertex 3", "vertex 2", 1)
+	ioutil.WriteFile(c, []byte(changed), 0644)
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(c, later, later)
+	c2, e := l.Load(c)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if c2 == c1 || c2.VerticesCount() != 2 {
+		t.Error("expected modified file to be reloaded")
+	}
+	if _, e := l.Load(filepath.Join(dir, "missing.ply")); e == nil {
+		t.Error("expected error for missing file")
+	}
+	l.Purge()
+	if l.Len() != 0 {
+		t.Error("expected empty cache")
+	}
+
+	src := new(PLY)
+	src.Read(strings.NewReader(testASCIIVertices))
+	src.FileType = BinaryLittleEndian
+	bin := filepath.Join(dir, "bin.ply")
+	if e := src.Save(bin); e != nil {
+		t.Fatal(e)
+	}
+	l.Options = &LoadOptions{Lazy: true}
+	p, e := l.Load(bin)
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, prop := range p.GetVertices().Properties {
+		if prop.block != nil {
+			t.Errorf("expected %s to be decoded before sharing", prop.Name)
+		}
+	}
+}
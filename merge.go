@@ -0,0 +1,215 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// FillMissing gives rows of inputs lacking a property the fill value,
+	// or an empty list.
+	FillMissing = iota
+	// DropMissing keeps only the properties every input has.
+	DropMissing
+	// ErrorMissing fails if the inputs' property sets differ.
+	ErrorMissing
+)
+
+type MergeOptions struct {
+	// Missing is FillMissing, DropMissing or ErrorMissing.
+	Missing int
+	// Fill is the value of missing scalar properties with FillMissing.
+	Fill float64
+}
+
+// Merge concatenates the elements of plys by name, offsetting face
+// indices by the vertices of the preceding inputs and widening their type
+// to int when they outgrow it. Properties are unified
+// by name and must agree in type; missing ones are filled with zero.
+func Merge(plys ...*PLY) (*PLY, error) {
+	return MergeWithOptions(nil, plys...)
+}
+
+// MergeWithOptions is Merge with configurable handling of properties
+// missing from some inputs. The result takes the first input's format;
+// comments are concatenated and obj_info items merged, first one winning.
+func MergeWithOptions(opts *MergeOptions, plys ...*PLY) (*PLY, error) {
+	if opts == nil {
+		opts = &MergeOptions{}
+	}
+	if len(plys) == 0 {
+		return nil, errors.New("Nothing to merge")
+	}
+	out := &PLY{FileType: plys[0].FileType, byteOrder: plys[0].byteOrder}
+	seen := make(map[string]bool)
+	for _, p := range plys {
+		for _, c := range p.Comments {
+			if !seen[c] {
+				seen[c] = true
+				out.Comments = append(out.Comments, c)
+			}
+		}
+		for k, v := range p.ObjInfoItems {
+			if out.ObjInfoItems == nil {
+				out.ObjInfoItems = make(map[string]string)
+			}
+			if _, ok := out.ObjInfoItems[k]; !ok {
+				out.ObjInfoItems[k] = v
+			}
+		}
+	}
+	var names []string
+	seenElem := make(map[string]bool)
+	for _, p := range plys {
+		for _, elem := range p.Elements {
+			if !seenElem[elem.Name] {
+				seenElem[elem.Name] = true
+				names = append(names, elem.Name)
+			}
+		}
+	}
+	for _, name := range names {
+		elem, e := mergeElement(name, plys, opts)
+		if e != nil {
+			return nil, e
+		}
+		out.Elements = append(out.Elements, elem)
+	}
+	return out, nil
+}
+
+func mergeElement(name string, plys []*PLY, opts *MergeOptions) (*Element, error) {
+	var props []*Property
+	byName := make(map[string]*Property)
+	count := make(map[string]int)
+	inputs := 0
+	for _, p := range plys {
+		elem := p.findElement(name)
+		if elem == nil {
+			continue
+		}
+		inputs++
+		for _, prop := range elem.Properties {
+			count[prop.Name]++
+			first := byName[prop.Name]
+			if first == nil {
+				merged := *prop
+				merged.Data, merged.block = nil, nil
+				merged.order = binary.LittleEndian
+				byName[prop.Name] = &merged
+				props = append(props, &merged)
+				continue
+			}
+			if first.IsList != prop.IsList || normalizeType(first.Type) != normalizeType(prop.Type) {
+				return nil, errors.New("Property " + name + "." + prop.Name + " has different types")
+			}
+			// list counts only affect the encoding; keep the widest
+			if SizeOfType[prop.ListSizeType] > SizeOfType[first.ListSizeType] {
+				first.ListSizeType = prop.ListSizeType
+			}
+		}
+	}
+	merged := &Element{Name: name}
+	for _, prop := range props {
+		if count[prop.Name] == inputs {
+			merged.Properties = append(merged.Properties, prop)
+			continue
+		}
+		switch opts.Missing {
+		case ErrorMissing:
+			return nil, errors.New("Property " + name + "." + prop.Name + " is missing from some inputs")
+		case FillMissing:
+			merged.Properties = append(merged.Properties, prop)
+		}
+	}
+	for k, prop := range merged.Properties {
+		prop.pos = k
+	}
+	if name == "face" {
+		if e := widenIndices(byName, plys); e != nil {
+			return nil, e
+		}
+	}
+	offset := 0
+	for _, p := range plys {
+		elem := p.findElement(name)
+		if elem != nil {
+			var idx *Property
+			if name == "face" {
+				idx = p.faceIndexProperty(elem)
+			}
+			for _, prop := range merged.Properties {
+				src := elem.findProperty(prop.Name)
+				for i := 0; i < elem.Size; i++ {
+					switch {
+					case src == nil && prop.IsList:
+						prop.Data = append(prop.Data, []byte{})
+					case src == nil:
+						prop.Data = append(prop.Data, encodeFloat64(opts.Fill, prop.Type, prop.order))
+					case src == idx && (offset != 0 || src.Type != prop.Type):
+						values := src.listFloat64At(i)
+						for j := range values {
+							values[j] += float64(offset)
+						}
+						prop.Data = append(prop.Data, prop.encodeList(values))
+					default:
+						// copied, so that writing the merged PLY leaves p unchanged
+						prop.Data = append(prop.Data, append([]byte(nil), src.rowInOrder(i, prop.order)...))
+					}
+				}
+			}
+			merged.Size += elem.Size
+		}
+		offset += p.VerticesCount()
+	}
+	return merged, nil
+}
+
+// widenIndices widens the integral item type of the merged face index
+// lists to int, or uint, when the vertex indices of the merged PLY do not
+// fit it, e.g. ushort indices beyond 65535 vertices.
+func widenIndices(byName map[string]*Property, plys []*PLY) error {
+	vertices := 0
+	for _, p := range plys {
+		vertices += p.VerticesCount()
+	}
+	for _, p := range plys {
+		face := p.findElement("face")
+		if face == nil {
+			continue
+		}
+		idx := p.faceIndexProperty(face)
+		if idx == nil || byName[idx.Name] == nil {
+			continue
+		}
+		prop := byName[idx.Name]
+		if isFloat(prop.Type) {
+			continue
+		}
+		fits := false
+		for _, t := range []string{prop.Type, "int", "uint"} {
+			if _, fits = fitValue(float64(vertices-1), t); fits {
+				prop.Type = t
+				break
+			}
+		}
+		if !fits {
+			return errors.New("Merged vertex indices do not fit " + idx.Name)
+		}
+	}
+	return nil
+}
+
+// rowInOrder returns row i with its values in the given byte order.
+func (p *Property) rowInOrder(i int, order binary.ByteOrder) []byte {
+	b := p.row(i)
+	size := SizeOfType[p.Type]
+	if p.byteOrder() == order || size < 2 {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for k := 0; k+size <= len(b); k += size {
+		out = append(out, reversed(b[k:k+size])...)
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	a := new(PLY)
+	if e := a.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	// a big endian tile without the quality property
+	var bin bytes.Buffer
+	src := strings.NewReplacer("property double quality\n", "", " 0.5\n", "\n", " 0.25\n", "\n",
+		" -1e-05\n", "\n", "0 1 0 3\n", "0 1 0\n").Replace(testASCIIMesh)
+	if e := Convert(strings.NewReader(src), &bin, BinaryBigEndian); e != nil {
+		t.Fatal(e)
+	}
+	b := new(PLY)
+	if e := b.Read(&bin); e != nil {
+		t.Fatal(e)
+	}
+	m, e := MergeWithOptions(&MergeOptions{Fill: -1}, a, b)
+	if e != nil {
+		t.Fatal(e)
+	}
+	faces := m.ReadFaces()
+	if m.VerticesCount() != 8 || len(faces) != 4 || faces[2][0] != 4 || faces[3][3] != 7 {
+		t.Errorf("unexpected merge: %d vertices, faces %v", m.VerticesCount(), faces)
+	}
+	quality := m.findElement("vertex").findProperty("quality")
+	if quality.float64At(3) != 3 || quality.float64At(4) != -1 {
+		t.Error("expected missing values to be filled")
+	}
+	if flags := m.findElement("face").findProperty("flags"); flags.float64At(3) != 300 {
+		t.Error("expected big endian rows to be converted")
+	}
+	if len(m.Comments) != 1 {
+		t.Errorf("expected duplicate comments to be merged, got %v", m.Comments)
+	}
+	var out bytes.Buffer
+	if e := m.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	if e := m.ScaleUnits(10); e != nil {
+		t.Fatal(e)
+	}
+	if x := a.findElement("vertex").findProperty("x"); x.float64At(1) != 1 {
+		t.Errorf("expected scaling the merge to leave its input, got x %g", x.float64At(1))
+	}
+
+	d, e := MergeWithOptions(&MergeOptions{Missing: DropMissing}, a, b)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if d.findElement("vertex").findProperty("quality") != nil {
+		t.Error("expected quality to be dropped")
+	}
+	if _, e := MergeWithOptions(&MergeOptions{Missing: ErrorMissing}, a, b); e == nil {
+		t.Error("expected an error for differing property sets")
+	}
+	b.findElement("face").findProperty("flags").Type = "float"
+	if _, e := Merge(a, b); e == nil {
+		t.Error("expected an error for conflicting types")
+	}
+}
+
+func TestMergeWidensIndices(t *testing.T) {
+	a, e := NewFixture(&FixtureOptions{Vertices: 65536})
+	if e != nil {
+		t.Fatal(e)
+	}
+	b, e := NewFixture(&FixtureOptions{Vertices: 3, Faces: 1, ListItemType: "ushort"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	want := b.ReadFaces()[0]
+	m, e := Merge(a, b)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if idx := m.findElement("face").findProperty("vertex_indices"); idx.Type != "int" {
+		t.Errorf("expected the indices to be widened to int, got %s", idx.Type)
+	}
+	for k, i := range m.ReadFaces()[0] {
+		if i != want[k]+65536 {
+			t.Errorf("expected corner %d to be %d, got %d", k, want[k]+65536, i)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+	"image/color"
+)
+
+type Vec2 [2]float64
+
+type Vec3 [3]float64
+
+// Mesh is a triangle mesh with optional per-vertex attributes. Normals,
+// Colors and UVs are either nil or as long as Positions.
+type Mesh struct {
+	Positions []Vec3
+	Normals   []Vec3
+	Colors    []color.NRGBA
+	UVs       []Vec2
+	Faces     [][3]int
+}
+
+// NewMeshFromPLY decodes the vertex and face elements of p. Polygons are
+// fan-triangulated. Colors are read from red, green, blue (and alpha);
+// float channels are taken as normalized.
+func NewMeshFromPLY(p *PLY) (*Mesh, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	vertex := p.findElement("vertex")
+	m := &Mesh{Positions: make([]Vec3, len(pos))}
+	for i, v := range pos {
+		m.Positions[i] = v
+	}
+	if normals := vertex.scalarProperties("nx", "ny", "nz"); normals != nil {
+		m.Normals = make([]Vec3, len(pos))
+		for i := range m.Normals {
+			for j := 0; j < 3; j++ {
+				m.Normals[i][j] = normals[j].float64At(i)
+			}
+		}
+	}
+	if rgb := vertex.scalarProperties("red", "green", "blue"); rgb != nil {
+		alpha := vertex.findProperty("alpha")
+		if alpha != nil && !alpha.IsList {
+			rgb = append(rgb, alpha)
+		}
+		m.Colors = make([]color.NRGBA, len(pos))
+		for i := range m.Colors {
+			c := [4]uint8{0, 0, 0, 255}
+			for j, prop := range rgb {
+				v := prop.float64At(i)
+				if isFloat(prop.Type) {
+					v *= 255
+				}
+				f, _ := fitValue(v, "uchar")
+				c[j] = uint8(f)
+			}
+			m.Colors[i] = color.NRGBA{c[0], c[1], c[2], c[3]}
+		}
+	}
+	for _, names := range uvNames {
+		if uv := vertex.scalarProperties(names[0], names[1]); uv != nil {
+			m.UVs = make([]Vec2, len(pos))
+			for i := range m.UVs {
+				m.UVs[i] = Vec2{uv[0].float64At(i), uv[1].float64At(i)}
+			}
+			break
+		}
+	}
+	if p.findElement("face") != nil {
+		faces, e := p.faceIndices()
+		if e != nil {
+			return nil, e
+		}
+		for _, f := range faces {
+			m.Faces = append(m.Faces, fanTriangles(f)...)
+		}
+	}
+	return m, m.Validate()
+}
+
+// Validate checks attribute lengths and face indices.
+func (m *Mesh) Validate() error {
+	n := len(m.Positions)
+	if m.Normals != nil && len(m.Normals) != n || m.Colors != nil && len(m.Colors) != n ||
+		m.UVs != nil && len(m.UVs) != n {
+		return errors.New("Mesh attributes must match the number of positions")
+	}
+	for i, f := range m.Faces {
+		for _, idx := range f {
+			if idx < 0 || idx >= n {
+				return errors.New("Face " + itoa(i) + " references missing vertex " + itoa(idx))
+			}
+		}
+	}
+	return nil
+}
+
+// ToPLY builds a binary little endian PLY with float positions, normals
+// and s, t texture coordinates, uchar colors (alpha only when some vertex
+// is translucent) and a face element when there are faces.
+func (m *Mesh) ToPLY() (*PLY, error) {
+	if e := m.Validate(); e != nil {
+		return nil, e
+	}
+	n := len(m.Positions)
+	vertex := &Element{Name: "vertex", Size: n}
+	add := func(name, typeName string) *Property {
+		prop := newProperty(name, typeName, n)
+		prop.pos = len(vertex.Properties)
+		vertex.Properties = append(vertex.Properties, prop)
+		return prop
+	}
+	setVec := func(values []Vec3, names ...string) {
+		for j, name := range names {
+			prop := add(name, "float")
+			for i, v := range values {
+				prop.setFloat64At(i, v[j])
+			}
+		}
+	}
+	setVec(m.Positions, "x", "y", "z")
+	if m.Normals != nil {
+		setVec(m.Normals, "nx", "ny", "nz")
+	}
+	if m.Colors != nil {
+		names := []string{"red", "green", "blue"}
+		for _, c := range m.Colors {
+			if c.A != 255 {
+				names = append(names, "alpha")
+				break
+			}
+		}
+		for j, name := range names {
+			prop := add(name, "uchar")
+			for i, c := range m.Colors {
+				prop.Data[i] = []byte{[]uint8{c.R, c.G, c.B, c.A}[j]}
+			}
+		}
+	}
+	if m.UVs != nil {
+		s, t := add("s", "float"), add("t", "float")
+		for i, uv := range m.UVs {
+			s.setFloat64At(i, uv[0])
+			t.setFloat64At(i, uv[1])
+		}
+	}
+	p := &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian, Elements: []*Element{vertex}}
+	if len(m.Faces) > 0 {
+		faces := make([][]int, len(m.Faces))
+		for i, f := range m.Faces {
+			faces[i] = []int{f[0], f[1], f[2]}
+		}
+		p.Elements = append(p.Elements, faceElement(faces))
+	}
+	return p, nil
+}
@@ -0,0 +1,40 @@
+package ply
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestMeshRoundTrip(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	m, e := NewMeshFromPLY(p)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(m.Positions) != 4 || len(m.Faces) != 3 || m.Faces[2] != [3]int{0, 2, 3} || m.Normals != nil {
+		t.Fatalf("unexpected mesh %+v", m)
+	}
+	m.Colors = make([]color.NRGBA, 4)
+	m.Colors[1] = color.NRGBA{1, 2, 3, 128}
+	m.UVs = []Vec2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	q, e := m.ToPLY()
+	if e != nil {
+		t.Fatal(e)
+	}
+	back, e := NewMeshFromPLY(q)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if back.Colors[1] != m.Colors[1] || back.UVs[2] != m.UVs[2] || back.Positions[2] != m.Positions[2] ||
+		len(back.Faces) != 3 {
+		t.Errorf("round trip mismatch %+v", back)
+	}
+	m.Faces = append(m.Faces, [3]int{0, 1, 9})
+	if _, e := m.ToPLY(); e == nil {
+		t.Error("expected invalid face error")
+	}
+}
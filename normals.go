@@ -0,0 +1,134 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// Normal weighting schemes for ComputeNormals.
+const (
+	AreaWeighted = iota
+	AngleWeighted
+)
+
+// ComputeNormals derives vertex normals from the faces and stores them in
+// nx, ny and nz, creating float properties if needed. Polygons are
+// fan-triangulated; vertices without faces get a zero normal.
+func (p *PLY) ComputeNormals(weighting int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return e
+	}
+	var tris [][3]int
+	for _, f := range faces {
+		tris = append(tris, fanTriangles(f)...)
+	}
+	return p.SetNormals(vertexNormals(pos, tris, weighting))
+}
+
+type NormalEstimationOptions struct {
+	// K is the number of nearest points, the point itself included, whose
+	// plane gives its normal; 10 by default unless Radius is set.
+	K int
+	// Radius, when set, takes the points within this distance instead, at
+	// most K of them if K is set too.
+	Radius float64
+	// Viewpoint is where normals are oriented towards, e.g. the scanner
+	// position; the origin by default.
+	Viewpoint [3]float64
+}
+
+// EstimateNormals derives vertex normals of a point cloud without faces
+// from the plane best fitting the neighborhood of each point, by principal
+// component analysis, and stores them in nx, ny and nz like SetNormals.
+// Points with fewer than three neighbors get a zero normal.
+func (p *PLY) EstimateNormals(opts *NormalEstimationOptions) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if opts == nil {
+		opts = &NormalEstimationOptions{}
+	}
+	if opts.K < 0 || !(opts.Radius >= 0) {
+		return errors.New("Neighborhood size must not be negative")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	tree := NewKDTree(pos)
+	normals := make([][3]float64, len(pos))
+	for i, v := range pos {
+		if isInvalidPoint(v) {
+			continue
+		}
+		idx := tree.neighborhood(v, opts.K, opts.Radius)
+		if len(idx) < 3 {
+			continue
+		}
+		_, c := covariance3(pos, idx)
+		_, vectors := symmetricEigen3(c)
+		n := normalize3(vectors[0])
+		if dot3(n, sub3(opts.Viewpoint, v)) < 0 {
+			n = scale3(n, -1)
+		}
+		normals[i] = n
+	}
+	return p.SetNormals(normals)
+}
+
+// ComputeNormals replaces m.Normals with normals derived from the faces.
+func (m *Mesh) ComputeNormals(weighting int) {
+	pos := make([][3]float64, len(m.Positions))
+	for i, v := range m.Positions {
+		pos[i] = v
+	}
+	normals := vertexNormals(pos, m.Faces, weighting)
+	m.Normals = make([]Vec3, len(normals))
+	for i, n := range normals {
+		m.Normals[i] = n
+	}
+}
+
+func vertexNormals(pos [][3]float64, tris [][3]int, weighting int) [][3]float64 {
+	normals := make([][3]float64, len(pos))
+	for _, t := range tris {
+		valid := true
+		for _, idx := range t {
+			valid = valid && idx >= 0 && idx < len(pos)
+		}
+		if !valid {
+			continue
+		}
+		// the cross product's length is twice the triangle area
+		n := cross3(sub3(pos[t[1]], pos[t[0]]), sub3(pos[t[2]], pos[t[0]]))
+		if weighting != AngleWeighted {
+			for _, idx := range t {
+				normals[idx] = add3(normals[idx], n)
+			}
+			continue
+		}
+		n = normalize3(n)
+		for k, idx := range t {
+			a := sub3(pos[t[(k+1)%3]], pos[idx])
+			b := sub3(pos[t[(k+2)%3]], pos[idx])
+			la, lb := length3(a), length3(b)
+			if la == 0 || lb == 0 {
+				continue
+			}
+			angle := math.Acos(clampFloat64(dot3(a, b)/(la*lb), -1, 1))
+			normals[idx] = add3(normals[idx], scale3(n, angle))
+		}
+	}
+	for i := range normals {
+		normals[i] = normalize3(normals[i])
+	}
+	return normals
+}
@@ -0,0 +1,119 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestComputeNormals(t *testing.T) {
+	// three box faces meeting at vertex 0 with different areas
+	src := `ply
+format ascii 1.0
+element vertex 7
+property float x
+property float y
+property float z
+element face 3
+property list uchar int vertex_indices
+end_header
+0 0 0
+4 0 0
+0 2 0
+0 0 1
+4 0 1
+0 2 1
+9 9 9
+3 0 2 1
+4 0 1 4 3
+4 0 3 5 2
+`
+	for _, w := range []int{AreaWeighted, AngleWeighted} {
+		p := new(PLY)
+		if e := p.Read(strings.NewReader(src)); e != nil {
+			t.Fatal(e)
+		}
+		if e := p.ComputeNormals(w); e != nil {
+			t.Fatal(e)
+		}
+		n, e := p.Normals()
+		if e != nil {
+			t.Fatal(e)
+		}
+		if length := math.Sqrt(n[0][0]*n[0][0] + n[0][1]*n[0][1] + n[0][2]*n[0][2]); math.Abs(length-1) > 1e-6 {
+			t.Errorf("expected unit normal, got %v", n[0])
+		}
+		if n[6] != [3]float64{} {
+			t.Errorf("expected zero normal for isolated vertex, got %v", n[6])
+		}
+		if w == AngleWeighted && (math.Abs(n[0][0]-n[0][1]) > 1e-6 || math.Abs(n[0][1]-n[0][2]) > 1e-6) {
+			t.Errorf("expected symmetric angle-weighted normal, got %v", n[0])
+		}
+		// the x = 0 face has half the area of the other two
+		if w == AreaWeighted && (math.Abs(n[0][0]+1.0/3) > 1e-6 || math.Abs(n[0][1]+2.0/3) > 1e-6) {
+			t.Errorf("unexpected area-weighted normal %v", n[0])
+		}
+	}
+	m := &Mesh{Positions: []Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, Faces: [][3]int{{0, 1, 2}}}
+	m.ComputeNormals(AreaWeighted)
+	if m.Normals[1] != (Vec3{0, 0, 1}) {
+		t.Errorf("unexpected mesh normal %v", m.Normals[1])
+	}
+}
+
+func TestEstimateNormals(t *testing.T) {
+	var pos [][3]float64
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			x, y := float64(i)*0.1, float64(j)*0.1
+			pos = append(pos, [3]float64{x, y, 0.5*x + 0.2*y + 10})
+		}
+	}
+	pos = append(pos, [3]float64{100, 100, 100})
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	want := normalize3([3]float64{-0.5, -0.2, 1})
+	above := [3]float64{0, 0, 100}
+	for _, opts := range []*NormalEstimationOptions{{Viewpoint: above}, {Radius: 0.25, Viewpoint: above},
+		{K: 5, Radius: 0.3, Viewpoint: above}} {
+		if e := p.EstimateNormals(opts); e != nil {
+			t.Fatal(e)
+		}
+		n, _ := p.Normals()
+		for i := 0; i < 400; i++ {
+			if length3(sub3(n[i], want)) > 1e-5 {
+				t.Fatalf("options %+v: unexpected normal %v at %d, want %v", opts, n[i], i, want)
+			}
+		}
+		if opts.Radius > 0 && n[400] != [3]float64{} {
+			t.Errorf("expected a zero normal for an isolated point, got %v", n[400])
+		}
+	}
+	// the plane lies above the default viewpoint at the origin
+	if e := p.EstimateNormals(nil); e != nil {
+		t.Fatal(e)
+	}
+	if n, _ := p.Normals(); length3(add3(n[0], want)) > 1e-5 {
+		t.Errorf("expected normals flipped towards the origin, got %v", n[0])
+	}
+}
+
+func TestSymmetricEigen3(t *testing.T) {
+	a := [3][3]float64{{4, 1, -2}, {1, 2, 0.5}, {-2, 0.5, 3}}
+	values, vectors := symmetricEigen3(a)
+	if !(values[0] <= values[1] && values[1] <= values[2]) {
+		t.Errorf("expected ascending eigenvalues, got %v", values)
+	}
+	for k, v := range vectors {
+		for r := 0; r < 3; r++ {
+			if got := dot3(a[r], v); math.Abs(got-values[k]*v[r]) > 1e-9 {
+				t.Errorf("eigenpair %d does not hold: %v, %v", k, values[k], v)
+			}
+		}
+		if math.Abs(length3(v)-1) > 1e-12 {
+			t.Errorf("expected a unit eigenvector, got %v", v)
+		}
+	}
+}
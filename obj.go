@@ -0,0 +1,85 @@
+package ply
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+)
+
+var uvNames = [][2]string{{"u", "v"}, {"s", "t"}, {"texture_u", "texture_v"}}
+
+// ToOBJ writes the vertex and face elements as Wavefront OBJ. Normals
+// (nx, ny, nz) and texture coordinates (u/v, s/t or texture_u/texture_v)
+// are written when the vertex element has them.
+func (p *PLY) ToOBJ(w io.Writer) error {
+	elem := p.findElement("vertex")
+	if elem == nil {
+		return errors.New("No vertex element")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	normals := elem.scalarProperties("nx", "ny", "nz")
+	var uvs []*Property
+	for _, names := range uvNames {
+		if uvs = elem.scalarProperties(names[0], names[1]); uvs != nil {
+			break
+		}
+	}
+	bw := bufio.NewWriter(w)
+	for i, v := range pos {
+		bw.WriteString("v " + formatFloat(v[0]) + " " + formatFloat(v[1]) + " " + formatFloat(v[2]) + "\n")
+		if uvs != nil {
+			bw.WriteString("vt " + formatFloat(uvs[0].float64At(i)) + " " + formatFloat(uvs[1].float64At(i)) + "\n")
+		}
+		if normals != nil {
+			bw.WriteString("vn " + formatFloat(normals[0].float64At(i)) + " " +
+				formatFloat(normals[1].float64At(i)) + " " + formatFloat(normals[2].float64At(i)) + "\n")
+		}
+	}
+	if p.findElement("face") != nil {
+		faces, e := p.faceIndices()
+		if e != nil {
+			return e
+		}
+		for i, f := range faces {
+			bw.WriteString("f")
+			for _, idx := range f {
+				if idx < 0 || idx >= len(pos) {
+					return errors.New("Face " + itoa(i) + " references missing vertex " + itoa(idx))
+				}
+				ref := itoa(idx + 1)
+				switch {
+				case uvs != nil && normals != nil:
+					ref += "/" + ref + "/" + ref
+				case uvs != nil:
+					ref += "/" + ref
+				case normals != nil:
+					ref += "//" + ref
+				}
+				bw.WriteString(" " + ref)
+			}
+			bw.WriteString("\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// scalarProperties returns the named scalar properties, or nil unless all
+// of them exist.
+func (e *Element) scalarProperties(names ...string) []*Property {
+	props := make([]*Property, len(names))
+	for j, name := range names {
+		props[j] = e.findProperty(name)
+		if props[j] == nil || props[j].IsList {
+			return nil
+		}
+	}
+	return props
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,239 @@
+package ply
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type objCorner struct {
+	v, vt, vn int
+}
+
+// FromOBJ builds a PLY with vertex and face elements from a Wavefront OBJ
+// file. Normals become nx/ny/nz, texture coordinates s/t and the common
+// "v x y z r g b" color extension red/green/blue. Vertices are split where
+// faces combine the same position with different normals or texture
+// coordinates, since PLY stores attributes per vertex.
+func FromOBJ(r io.Reader) (*PLY, error) {
+	var pos, colors, normals, uvs [][3]float64
+	var faces [][]objCorner
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 || strings.HasPrefix(words[0], "#") {
+			continue
+		}
+		fail := func(msg string) error {
+			return errors.New(msg + " in OBJ at line " + itoa(lineNo))
+		}
+		switch words[0] {
+		case "v", "vn", "vt":
+			values, e := parseOBJFloats(words[1:])
+			if e != nil {
+				return nil, fail(e.Error())
+			}
+			var v [3]float64
+			copy(v[:], values)
+			switch {
+			case words[0] == "vn" && len(values) >= 3:
+				normals = append(normals, v)
+			case words[0] == "vt" && len(values) >= 1:
+				uvs = append(uvs, v)
+			case words[0] == "v" && len(values) >= 3:
+				pos = append(pos, v)
+				if len(values) >= 6 {
+					var c [3]float64
+					copy(c[:], values[3:6])
+					for len(colors) < len(pos)-1 {
+						colors = append(colors, [3]float64{})
+					}
+					colors = append(colors, c)
+				}
+			default:
+				return nil, fail("Too few values")
+			}
+		case "f":
+			if len(words) < 4 {
+				return nil, fail("Face with fewer than 3 vertices")
+			}
+			face := make([]objCorner, len(words)-1)
+			for k, w := range words[1:] {
+				c, e := parseOBJCorner(w, len(pos), len(uvs), len(normals))
+				if e != nil {
+					return nil, fail(e.Error())
+				}
+				face[k] = c
+			}
+			faces = append(faces, face)
+		}
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, e
+	}
+	if colors != nil {
+		for len(colors) < len(pos) {
+			colors = append(colors, [3]float64{})
+		}
+	}
+
+	hasUV, hasNormal := false, false
+	for _, f := range faces {
+		for _, c := range f {
+			hasUV = hasUV || c.vt >= 0
+			hasNormal = hasNormal || c.vn >= 0
+		}
+	}
+	// without per-corner attributes OBJ vertices map one to one; otherwise
+	// every distinct corner combination becomes a PLY vertex
+	var corners []objCorner
+	index := make(map[objCorner]int)
+	if !hasUV && !hasNormal {
+		corners = make([]objCorner, len(pos))
+		for i := range pos {
+			corners[i] = objCorner{i, -1, -1}
+			index[corners[i]] = i
+		}
+	}
+	indices := make([][]int, len(faces))
+	for i, f := range faces {
+		indices[i] = make([]int, len(f))
+		for k, c := range f {
+			if !hasUV {
+				c.vt = -1
+			}
+			if !hasNormal {
+				c.vn = -1
+			}
+			idx, ok := index[c]
+			if !ok {
+				idx = len(corners)
+				index[c] = idx
+				corners = append(corners, c)
+			}
+			indices[i][k] = idx
+		}
+	}
+	if hasUV || hasNormal {
+		used := make([]bool, len(pos))
+		for _, c := range corners {
+			used[c.v] = true
+		}
+		for i := range pos {
+			if !used[i] {
+				corners = append(corners, objCorner{i, -1, -1})
+			}
+		}
+	}
+
+	names := []string{"x", "y", "z"}
+	if hasNormal {
+		names = append(names, "nx", "ny", "nz")
+	}
+	if hasUV {
+		names = append(names, "s", "t")
+	}
+	vertex := &Element{Name: "vertex", Size: len(corners)}
+	for _, name := range names {
+		vertex.Properties = append(vertex.Properties, newProperty(name, "float", len(corners)))
+	}
+	if colors != nil {
+		for _, name := range []string{"red", "green", "blue"} {
+			vertex.Properties = append(vertex.Properties, newProperty(name, "uchar", len(corners)))
+		}
+	}
+	for i, c := range corners {
+		values := []float64{pos[c.v][0], pos[c.v][1], pos[c.v][2]}
+		if hasNormal {
+			var n [3]float64
+			if c.vn >= 0 {
+				n = normals[c.vn]
+			}
+			values = append(values, n[:]...)
+		}
+		if hasUV {
+			var uv [3]float64
+			if c.vt >= 0 {
+				uv = uvs[c.vt]
+			}
+			values = append(values, uv[0], uv[1])
+		}
+		if colors != nil {
+			for _, v := range colors[c.v] {
+				values = append(values, clampFloat64(v*255, 0, 255))
+			}
+		}
+		for j, v := range values {
+			vertex.Properties[j].setFloat64At(i, v)
+		}
+	}
+
+	p := &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian}
+	p.Elements = append(p.Elements, vertex)
+	if len(faces) > 0 {
+		p.Elements = append(p.Elements, faceElement(indices))
+	}
+	return p, nil
+}
+
+func parseOBJFloats(words []string) ([]float64, error) {
+	values := make([]float64, len(words))
+	for i, w := range words {
+		v, e := strconv.ParseFloat(w, 64)
+		if e != nil {
+			return nil, errors.New("Invalid number \"" + w + "\"")
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// parseOBJCorner parses v, v/vt, v//vn or v/vt/vn into zero-based indices,
+// resolving negative (relative) references. Missing parts are -1.
+func parseOBJCorner(w string, nv, nvt, nvn int) (objCorner, error) {
+	c := objCorner{-1, -1, -1}
+	parts := strings.Split(w, "/")
+	if len(parts) > 3 {
+		return c, errors.New("Invalid face vertex \"" + w + "\"")
+	}
+	counts := []int{nv, nvt, nvn}
+	refs := []*int{&c.v, &c.vt, &c.vn}
+	for k, part := range parts {
+		if part == "" {
+			if k == 0 {
+				return c, errors.New("Invalid face vertex \"" + w + "\"")
+			}
+			continue
+		}
+		n, e := strconv.Atoi(part)
+		if e != nil || n == 0 {
+			return c, errors.New("Invalid face vertex \"" + w + "\"")
+		}
+		if n < 0 {
+			n = counts[k] + n
+		} else {
+			n--
+		}
+		if n < 0 || n >= counts[k] {
+			return c, errors.New("Face vertex \"" + w + "\" out of range")
+		}
+		*refs[k] = n
+	}
+	return c, nil
+}
+
+func clampFloat64(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestToOBJ(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 3
+property float x
+property float y
+property float z
+property float nx
+property float ny
+property float nz
+property float s
+property float t
+element face 1
+property list uchar int vertex_indices
+end_header
+0 0 0 0 0 1 0 0
+1 0 0 0 0 1 1 0
+0 1 0 0 0 1 0 1
+3 0 1 2
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := p.ToOBJ(&buf); e != nil {
+		t.Fatal(e)
+	}
+	expected := `v 0 0 0
+vt 0 0
+vn 0 0 1
+v 1 0 0
+vt 1 0
+vn 0 0 1
+v 0 1 0
+vt 0 1
+vn 0 0 1
+f 1/1/1 2/2/2 3/3/3
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected OBJ:\n%s", buf.String())
+	}
+}
+
+func TestFromOBJ(t *testing.T) {
+	src := `# quad with shared normal
+v 0 0 0 1 0 0
+v 1 0 0 0 1 0
+v 1 1 0 0 0 1
+v 0 1 0 1 1 1
+vn 0 0 1
+vt 0 0
+vt 1 1
+f 1/1/1 2/1/1 3/2/1 -1/2/-1
+f 1//1 3//1 4//1
+`
+	p, e := FromOBJ(strings.NewReader(src))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if p.VerticesCount() != 7 {
+		t.Errorf("expected 7 split vertices, got %d", p.VerticesCount())
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(faces) != 2 || len(faces[0]) != 4 || faces[1][0] == faces[0][0] {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	vertex := p.GetVertices()
+	if vertex.findProperty("nz").float64At(0) != 1 || vertex.findProperty("t").float64At(2) != 1 ||
+		vertex.findProperty("red").float64At(0) != 255 {
+		t.Error("unexpected vertex attributes")
+	}
+	var buf bytes.Buffer
+	if e := p.Write(&buf); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := FromOBJ(strings.NewReader("f 1 2 3\n")); e == nil {
+		t.Error("expected out of range error")
+	}
+}
@@ -0,0 +1,204 @@
+package ply
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// OctreeIndexFile is the name of the hierarchy document Octree.Save writes
+// next to the node files.
+const OctreeIndexFile = "octree.json"
+
+type OctreeOptions struct {
+	// MaxPoints is the number of points above which a node splits, 20000
+	// by default.
+	MaxPoints int
+	// GridSize divides the cube of a splitting node into GridSize cells
+	// along each axis, of which the node keeps one point each as its
+	// level of detail, 32 by default. Each level halves the spacing.
+	GridSize int
+	// MaxDepth bounds the depth of the tree, e.g. for many duplicate
+	// points, 16 by default.
+	MaxDepth int
+}
+
+// Octree partitions the vertices of a PLY for level of detail rendering,
+// like Potree: every node holds a subsample of the points in its cube that
+// its ancestors do not hold, so that a node and its ancestors together
+// show the cube at the node's level of detail and the leaves hold the
+// remaining points. Points with a NaN or infinite coordinate are left
+// out.
+type Octree struct {
+	Root *OctreeNode
+	ply  *PLY
+}
+
+// OctreeNode is a cube of an Octree.
+type OctreeNode struct {
+	// Name locates the node: "r" for the root followed by the index of
+	// each child on the way down, e.g. "r052".
+	Name string
+	// Bounds is the cube of the node.
+	Bounds
+	// Points holds the vertex indices of the node.
+	Points []int
+	// Children is indexed by x<<2 | y<<1 | z, where a bit is set for the
+	// upper half along its axis. Nil children hold no points.
+	Children [8]*OctreeNode
+}
+
+// IsLeaf reports whether n has no children.
+func (n *OctreeNode) IsLeaf() bool {
+	for _, c := range n.Children {
+		if c != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildOctree builds an octree over the vertex positions of p, which must
+// not change while the octree is in use.
+func (p *PLY) BuildOctree(opts *OctreeOptions) (*Octree, error) {
+	if opts == nil {
+		opts = &OctreeOptions{}
+	}
+	o := *opts
+	if o.MaxPoints == 0 {
+		o.MaxPoints = 20000
+	}
+	if o.GridSize == 0 {
+		o.GridSize = 32
+	}
+	if o.MaxDepth == 0 {
+		o.MaxDepth = 16
+	}
+	if o.MaxPoints < 0 || o.GridSize < 0 || o.MaxDepth < 0 {
+		return nil, errors.New("Octree options must not be negative")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	root := &OctreeNode{Name: "r"}
+	var idx []int
+	for i, v := range pos {
+		if !isInvalidPoint(v) {
+			idx = append(idx, i)
+		}
+	}
+	if b := positionBounds(pos); b != nil {
+		size := math.Max(b.Max[0]-b.Min[0], math.Max(b.Max[1]-b.Min[1], b.Max[2]-b.Min[2]))
+		if size == 0 {
+			size = 1
+		}
+		root.Min = b.Min
+		root.Max = add3(b.Min, [3]float64{size, size, size})
+	}
+	buildOctree(root, idx, pos, &o, 0)
+	return &Octree{Root: root, ply: p}, nil
+}
+
+func buildOctree(n *OctreeNode, idx []int, pos [][3]float64, opts *OctreeOptions, depth int) {
+	if len(idx) <= opts.MaxPoints || depth == opts.MaxDepth {
+		n.Points = idx
+		return
+	}
+	// keep the first point of every grid cell, pass the rest down
+	size := n.Max[0] - n.Min[0]
+	grid := opts.GridSize
+	cell := func(c float64, j int) int {
+		k := int((c - n.Min[j]) / size * float64(grid))
+		if k >= grid {
+			k = grid - 1
+		}
+		return k
+	}
+	taken := make(map[int]bool)
+	center := scale3(add3(n.Min, n.Max), 0.5)
+	var children [8][]int
+	for _, i := range idx {
+		v := pos[i]
+		key := (cell(v[0], 0)*grid+cell(v[1], 1))*grid + cell(v[2], 2)
+		if !taken[key] {
+			taken[key] = true
+			n.Points = append(n.Points, i)
+			continue
+		}
+		k := 0
+		for j := 0; j < 3; j++ {
+			if v[j] >= center[j] {
+				k |= 4 >> uint(j)
+			}
+		}
+		children[k] = append(children[k], i)
+	}
+	for k, rows := range children {
+		if len(rows) == 0 {
+			continue
+		}
+		c := &OctreeNode{Name: n.Name + itoa(k), Bounds: Bounds{n.Min, center}}
+		for j := 0; j < 3; j++ {
+			if k&(4>>uint(j)) != 0 {
+				c.Min[j], c.Max[j] = center[j], n.Max[j]
+			}
+		}
+		n.Children[k] = c
+		buildOctree(c, rows, pos, opts, depth+1)
+	}
+}
+
+// Nodes returns the nodes breadth first, parents before their children.
+func (o *Octree) Nodes() []*OctreeNode {
+	nodes := []*OctreeNode{o.Root}
+	for k := 0; k < len(nodes); k++ {
+		for _, c := range nodes[k].Children {
+			if c != nil {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+	return nodes
+}
+
+// Save writes every node to dir as a point cloud named after the node,
+// e.g. r052.ply, holding its vertices with all their properties, and the
+// hierarchy with the bounds and point count of each node, breadth first,
+// to OctreeIndexFile. It returns the names of the node files.
+func (o *Octree) Save(dir string, opts *SaveOptions) ([]string, error) {
+	vertex := o.ply.findElement("vertex")
+	if vertex == nil {
+		return nil, errors.New("No vertex element")
+	}
+	type entry struct {
+		Name string `json:"name"`
+		Bounds
+		Points int `json:"points"`
+	}
+	nodes := o.Nodes()
+	index := make([]entry, len(nodes))
+	names := make([]string, len(nodes))
+	for k, n := range nodes {
+		q := o.ply.shallowCopy()
+		q.Elements = []*Element{vertex.selectRows(n.Points)}
+		names[k] = filepath.Join(dir, n.Name+".ply")
+		if e := q.SaveWithOptions(names[k], opts); e != nil {
+			return names[:k], e
+		}
+		index[k] = entry{n.Name, n.Bounds, len(n.Points)}
+	}
+	f, e := os.Create(filepath.Join(dir, OctreeIndexFile))
+	if e != nil {
+		return names, e
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if e = enc.Encode(index); e != nil {
+		f.Close()
+		return names, e
+	}
+	return names, f.Close()
+}
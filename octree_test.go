@@ -0,0 +1,89 @@
+package ply
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOctree(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(benchMesh(3000))); e != nil {
+		t.Fatal(e)
+	}
+	x := p.GetVertices().findProperty("x")
+	x.setFloat64At(5, math.NaN())
+	tree, e := p.BuildOctree(&OctreeOptions{MaxPoints: 100, GridSize: 4})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if tree.Root.IsLeaf() {
+		t.Fatal("expected the root to split")
+	}
+	pos, _ := p.Positions()
+	seen := make(map[int]bool)
+	for _, n := range tree.Nodes() {
+		if !n.IsLeaf() && len(n.Points) > 4*4*4 {
+			t.Errorf("node %s keeps %d points, more than its grid cells", n.Name, len(n.Points))
+		}
+		for _, i := range n.Points {
+			if seen[i] {
+				t.Errorf("vertex %d in several nodes", i)
+			}
+			seen[i] = true
+			for j := 0; j < 3; j++ {
+				if pos[i][j] < n.Min[j] || pos[i][j] > n.Max[j] {
+					t.Fatalf("vertex %d outside node %s", i, n.Name)
+				}
+			}
+		}
+		for k, c := range n.Children {
+			if c != nil && c.Name != n.Name+itoa(k) {
+				t.Errorf("unexpected child name %s of %s", c.Name, n.Name)
+			}
+		}
+	}
+	if len(seen) != 2999 || seen[5] {
+		t.Errorf("expected every finite vertex in one node, got %d", len(seen))
+	}
+
+	dir, e := ioutil.TempDir("", "octree")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	names, e := tree.Save(dir, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(names) != len(tree.Nodes()) || filepath.Base(names[0]) != "r.ply" {
+		t.Errorf("unexpected files %v", names)
+	}
+	root := new(PLY)
+	if e := root.Load(names[0]); e != nil {
+		t.Fatal(e)
+	}
+	if root.VerticesCount() != len(tree.Root.Points) || len(root.Elements) != 1 ||
+		len(root.GetVertices().Properties) != 6 {
+		t.Errorf("unexpected root file with %d vertices", root.VerticesCount())
+	}
+	data, e := ioutil.ReadFile(filepath.Join(dir, OctreeIndexFile))
+	if e != nil {
+		t.Fatal(e)
+	}
+	var index []struct {
+		Name   string
+		Min    [3]float64
+		Points int
+	}
+	if e := json.Unmarshal(data, &index); e != nil {
+		t.Fatal(e)
+	}
+	if len(index) != len(names) || index[0].Name != "r" || index[0].Points != len(tree.Root.Points) {
+		t.Errorf("unexpected index %v", index[:1])
+	}
+}
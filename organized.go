@@ -0,0 +1,78 @@
+package ply
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Organization returns the dimensions of an organized cloud, whose
+// vertices form a grid of rows by cols stored row by row, as given by the
+// num_cols and num_rows obj_info items. ok is false if they are missing or
+// do not match the vertex count.
+func (p *PLY) Organization() (cols, rows int, ok bool) {
+	cols, e := strconv.Atoi(p.ObjInfoItems["num_cols"])
+	if e != nil || cols <= 0 {
+		return 0, 0, false
+	}
+	rows, e = strconv.Atoi(p.ObjInfoItems["num_rows"])
+	if e != nil || rows <= 0 || cols*rows != p.VerticesCount() {
+		return 0, 0, false
+	}
+	return cols, rows, true
+}
+
+// SetOrganization marks p as an organized cloud of rows by cols vertices.
+func (p *PLY) SetOrganization(cols, rows int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if cols <= 0 || rows <= 0 || cols*rows != p.VerticesCount() {
+		return errors.New("Grid of " + itoa(rows) + "x" + itoa(cols) + " does not match " +
+			itoa(p.VerticesCount()) + " vertices")
+	}
+	if p.ObjInfoItems == nil {
+		p.ObjInfoItems = make(map[string]string)
+	}
+	p.ObjInfoItems["num_cols"] = itoa(cols)
+	p.ObjInfoItems["num_rows"] = itoa(rows)
+	return nil
+}
+
+// Grid gives 2D access to the positions of an organized cloud.
+type Grid struct {
+	Cols, Rows int
+	points     [][3]float64
+}
+
+// Grid decodes the positions of an organized cloud.
+func (p *PLY) Grid() (*Grid, error) {
+	cols, rows, ok := p.Organization()
+	if !ok {
+		return nil, errors.New("Not an organized cloud")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	return &Grid{Cols: cols, Rows: rows, points: pos}, nil
+}
+
+// Index returns the vertex row of the grid cell, or -1 outside the grid.
+func (g *Grid) Index(row, col int) int {
+	if row < 0 || row >= g.Rows || col < 0 || col >= g.Cols {
+		return -1
+	}
+	return row*g.Cols + col
+}
+
+// At returns the position in a grid cell. ok is false outside the grid and
+// for invalid points, which organized clouds store as NaN to keep their
+// shape.
+func (g *Grid) At(row, col int) (v [3]float64, ok bool) {
+	i := g.Index(row, col)
+	if i < 0 {
+		return v, false
+	}
+	v = g.points[i]
+	return v, !isInvalidPoint(v)
+}
@@ -0,0 +1,70 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOrganizedCloud(t *testing.T) {
+	src := `ply
+format ascii 1.0
+obj_info num_cols 3
+obj_info num_rows 2
+element vertex 6
+property float x
+property float y
+property float z
+end_header
+0 0 1
+1 0 1
+2 0 1
+0 1 1
+nan nan nan
+2 1 1
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	if cols, rows, ok := p.Organization(); !ok || cols != 3 || rows != 2 {
+		t.Fatalf("unexpected organization %d x %d", rows, cols)
+	}
+	g, e := p.Grid()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if v, ok := g.At(1, 2); !ok || v != [3]float64{2, 1, 1} {
+		t.Errorf("unexpected point %v", v)
+	}
+	if _, ok := g.At(1, 1); ok {
+		t.Error("expected the NaN point to be invalid")
+	}
+	if _, ok := g.At(2, 0); ok || g.Index(0, 3) != -1 {
+		t.Error("expected cells outside the grid to be rejected")
+	}
+
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&out); e != nil {
+		t.Fatal(e)
+	}
+	if cols, rows, ok := q.Organization(); !ok || cols != 3 || rows != 2 {
+		t.Error("expected the organization to survive saving")
+	}
+	if e := q.SetOrganization(2, 2); e == nil {
+		t.Error("expected an error for a grid not matching the vertex count")
+	}
+	if e := q.SetOrganization(2, 3); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := q.Grid(); e != nil {
+		t.Error(e)
+	}
+	if _, _, ok := q.filterVertices(func(i int) bool { return i > 0 }).Organization(); ok {
+		t.Error("expected removing points to break the organization")
+	}
+}
@@ -0,0 +1,350 @@
+package ply
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type pcdField struct {
+	name  string
+	size  int
+	typ   byte
+	count int
+}
+
+// packedColor reports whether f holds red, green, blue and alpha packed
+// into one 4 byte value, as PCL writes rgb and rgba fields.
+func (f pcdField) packedColor() bool {
+	return f.count == 1 && f.size == 4 && (f.name == "rgb" || f.name == "rgba")
+}
+
+var pcdTypes = map[string]string{
+	"I1": "int8", "I2": "int16", "I4": "int32",
+	"U1": "uint8", "U2": "uint16", "U4": "uint32",
+	"F4": "float32", "F8": "float64",
+}
+
+// FromPCD reads a PCL point cloud (DATA ascii or binary) into a vertex-only
+// PLY. Fields become vertex properties of the same type, fields with COUNT
+// > 1 are split into name_0, name_1, ..., and packed rgb/rgba fields are
+// unpacked into red, green, blue (and alpha). Organized clouds record their
+// WIDTH and HEIGHT as num_cols and num_rows obj_info items.
+func FromPCD(r io.Reader) (*PLY, error) {
+	br := bufio.NewReader(r)
+	var fields []pcdField
+	width, height, points := 0, 1, -1
+	data := ""
+	for data == "" {
+		line, e := readLine(br)
+		if e != nil {
+			return nil, errors.New("Incomplete PCD header: " + e.Error())
+		}
+		words := strings.Fields(line)
+		if len(words) == 0 || strings.HasPrefix(words[0], "#") {
+			continue
+		}
+		key, args := strings.ToUpper(words[0]), words[1:]
+		switch key {
+		case "FIELDS", "COLUMNS":
+			fields = make([]pcdField, len(args))
+			for i, name := range args {
+				fields[i] = pcdField{name: name, size: 4, typ: 'F', count: 1}
+			}
+		case "SIZE", "TYPE", "COUNT":
+			if len(args) != len(fields) {
+				return nil, errors.New("PCD " + key + " does not match FIELDS")
+			}
+			for i, a := range args {
+				switch key {
+				case "TYPE":
+					fields[i].typ = strings.ToUpper(a)[0]
+				default:
+					n, e := strconv.Atoi(a)
+					if e != nil || n <= 0 {
+						return nil, errors.New("Invalid PCD " + key + " " + a)
+					}
+					if key == "SIZE" {
+						fields[i].size = n
+					} else {
+						fields[i].count = n
+					}
+				}
+			}
+		case "WIDTH", "HEIGHT", "POINTS":
+			if len(args) != 1 {
+				return nil, errors.New("Invalid PCD " + key)
+			}
+			n, e := strconv.Atoi(args[0])
+			if e != nil || n < 0 {
+				return nil, errors.New("Invalid PCD " + key + " " + args[0])
+			}
+			switch key {
+			case "WIDTH":
+				width = n
+			case "HEIGHT":
+				height = n
+			default:
+				points = n
+			}
+		case "DATA":
+			if len(args) != 1 {
+				return nil, errors.New("Invalid PCD DATA")
+			}
+			data = strings.ToLower(args[0])
+		}
+	}
+	if points < 0 {
+		points = width * height
+	}
+	if data != "ascii" && data != "binary" {
+		return nil, errors.New("Unsupported PCD DATA " + data)
+	}
+
+	vertex := &Element{Name: "vertex", Size: points}
+	var props [][]*Property
+	for _, f := range fields {
+		typeName := pcdTypes[string(f.typ)+strconv.Itoa(f.size)]
+		if typeName == "" {
+			return nil, errors.New("Unsupported PCD field type " + string(f.typ) + strconv.Itoa(f.size) + " of " + f.name)
+		}
+		var fp []*Property
+		if f.name == "_" {
+			// padding
+		} else if f.packedColor() {
+			names := []string{"red", "green", "blue"}
+			if f.name == "rgba" {
+				names = append(names, "alpha")
+			}
+			for _, n := range names {
+				fp = append(fp, newProperty(n, "uchar", 0))
+			}
+		} else if f.count == 1 {
+			fp = append(fp, newProperty(f.name, typeName, 0))
+		} else {
+			for k := 0; k < f.count; k++ {
+				fp = append(fp, newProperty(f.name+"_"+strconv.Itoa(k), typeName, 0))
+			}
+		}
+		props = append(props, fp)
+		vertex.Properties = append(vertex.Properties, fp...)
+	}
+	// POINTS comes from the header, so columns grow as points are read
+	for i, prop := range vertex.Properties {
+		prop.pos = i
+		prop.Data = newRows(points)
+	}
+
+	stride := 0
+	for _, f := range fields {
+		stride += f.size * f.count
+	}
+	row := make([]byte, stride)
+	for i := 0; i < points; i++ {
+		if data == "binary" {
+			if _, e := io.ReadFull(br, row); e != nil {
+				return nil, errors.New("Truncated PCD data at point " + itoa(i))
+			}
+		} else {
+			line, e := readLine(br)
+			for e == nil && line == "" {
+				line, e = readLine(br)
+			}
+			if e != nil {
+				return nil, errors.New("Truncated PCD data at point " + itoa(i))
+			}
+			words := strings.Fields(line)
+			off := 0
+			for _, f := range fields {
+				for k := 0; k < f.count; k++ {
+					if len(words) == 0 {
+						return nil, errors.New("Missing PCD values at point " + itoa(i))
+					}
+					typeName := pcdTypes[string(f.typ)+strconv.Itoa(f.size)]
+					b, e := toType(words[0], typeName)
+					if e != nil && f.packedColor() {
+						// packed colors are sometimes printed as integers
+						b, e = toType(words[0], "uint32")
+					}
+					if e != nil {
+						return nil, errors.New("Invalid PCD value " + words[0] + " at point " + itoa(i))
+					}
+					copy(row[off:], b)
+					off += f.size
+					words = words[1:]
+				}
+			}
+		}
+		off := 0
+		for j, f := range fields {
+			if f.packedColor() {
+				packed := binary.LittleEndian.Uint32(row[off:])
+				// red, green, blue, alpha
+				shifts := []uint{16, 8, 0, 24}
+				for k, prop := range props[j] {
+					prop.Data = appendRow(prop.Data, []byte{byte(packed >> shifts[k])}, points)
+				}
+				off += 4
+				continue
+			}
+			for _, prop := range props[j] {
+				prop.Data = appendRow(prop.Data, append([]byte(nil), row[off:off+f.size]...), points)
+				off += f.size
+			}
+			if len(props[j]) == 0 {
+				off += f.size * f.count
+			}
+		}
+	}
+	p := &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian}
+	p.Elements = []*Element{vertex}
+	if height > 1 {
+		if e := p.SetOrganization(width, height); e != nil {
+			return nil, e
+		}
+	}
+	return p, nil
+}
+
+// ToPCD writes the scalar vertex properties as a PCL point cloud, packing
+// red, green and blue (and alpha) into an rgb (rgba) field. binaryData
+// selects DATA binary over DATA ascii.
+func (p *PLY) ToPCD(w io.Writer, binaryData bool) error {
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return errors.New("No vertex element")
+	}
+	colors := vertex.scalarProperties("red", "green", "blue")
+	var alpha *Property
+	if colors != nil {
+		if a := vertex.scalarProperties("alpha"); a != nil {
+			alpha = a[0]
+		}
+	}
+	isColor := func(prop *Property) bool {
+		if colors == nil {
+			return false
+		}
+		return prop == colors[0] || prop == colors[1] || prop == colors[2] || prop == alpha
+	}
+	var props []*Property
+	var names, sizes, types []string
+	colorAt := -1
+	for _, prop := range vertex.Properties {
+		if prop.IsList {
+			continue
+		}
+		if isColor(prop) {
+			if colorAt < 0 {
+				colorAt = len(props)
+				props = append(props, nil)
+				if alpha != nil {
+					names = append(names, "rgba")
+					types = append(types, "U")
+				} else {
+					names = append(names, "rgb")
+					types = append(types, "F")
+				}
+				sizes = append(sizes, "4")
+			}
+			continue
+		}
+		code := ""
+		for k, v := range pcdTypes {
+			if v == normalizeType(prop.Type) {
+				code = k
+			}
+		}
+		if code == "" {
+			return errors.New("Unsupported type " + prop.Type + " of property " + prop.Name)
+		}
+		props = append(props, prop)
+		names = append(names, prop.Name)
+		types = append(types, code[:1])
+		sizes = append(sizes, code[1:])
+	}
+	width, height := vertex.Size, 1
+	if cols, rows, ok := p.Organization(); ok {
+		width, height = cols, rows
+	}
+	counts := strings.TrimSpace(strings.Repeat("1 ", len(names)))
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# .PCD v0.7 - Point Cloud Data file format\nVERSION 0.7\n")
+	bw.WriteString("FIELDS " + strings.Join(names, " ") + "\nSIZE " + strings.Join(sizes, " ") +
+		"\nTYPE " + strings.Join(types, " ") + "\nCOUNT " + counts + "\n")
+	bw.WriteString("WIDTH " + itoa(width) + "\nHEIGHT " + itoa(height) + "\nVIEWPOINT 0 0 0 1 0 0 0\n")
+	bw.WriteString("POINTS " + itoa(vertex.Size) + "\n")
+	if binaryData {
+		bw.WriteString("DATA binary\n")
+	} else {
+		bw.WriteString("DATA ascii\n")
+	}
+	for i := 0; i < vertex.Size; i++ {
+		for k, prop := range props {
+			var b []byte
+			var typeName string
+			if prop == nil {
+				var packed uint32
+				for j, c := range colors {
+					packed |= uint32(clampFloat64(c.float64At(i)/colorScale(c.Type)*255, 0, 255)) << uint(16-8*j)
+				}
+				typeName = "float32"
+				if alpha != nil {
+					packed |= uint32(clampFloat64(alpha.float64At(i)/colorScale(alpha.Type)*255, 0, 255)) << 24
+					typeName = "uint32"
+				}
+				b = make([]byte, 4)
+				binary.LittleEndian.PutUint32(b, packed)
+			} else {
+				if b = prop.scalarRow(i); b == nil {
+					return errors.New("Missing data for property " + prop.Name)
+				}
+				typeName = prop.Type
+				if prop.byteOrder() != binary.LittleEndian && len(b) > 1 {
+					b = reversed(b)
+				}
+			}
+			if binaryData {
+				bw.Write(b)
+				continue
+			}
+			if k > 0 {
+				bw.WriteByte(' ')
+			}
+			if prop == nil && typeName == "float32" {
+				// PCL prints packed rgb floats as decimal
+				f := math.Float32frombits(binary.LittleEndian.Uint32(b))
+				bw.WriteString(strconv.FormatFloat(float64(f), 'g', -1, 32))
+			} else {
+				bw.WriteString(formatValue(b, typeName, binary.LittleEndian))
+			}
+		}
+		if !binaryData {
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+func reversed(b []byte) []byte {
+	r := make([]byte, len(b))
+	for k := range b {
+		r[k] = b[len(b)-1-k]
+	}
+	return r
+}
+
+// normalizeType maps the legacy type names (char, uchar, ...) to their
+// sized equivalents.
+func normalizeType(typeName string) string {
+	for i, t := range OldTypes {
+		if t == typeName {
+			return Types[i]
+		}
+	}
+	return typeName
+}
@@ -0,0 +1,107 @@
+package ply
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPCDRoundTrip(t *testing.T) {
+	src := `# .PCD v0.7 - Point Cloud Data file format
+VERSION 0.7
+FIELDS x y z intensity rgb
+SIZE 4 4 4 2 4
+TYPE F F F U F
+COUNT 1 1 1 1 1
+WIDTH 2
+HEIGHT 1
+VIEWPOINT 0 0 0 1 0 0 0
+POINTS 2
+DATA ascii
+1.5 2 -3 100 4.808e+06
+0 0 1 7 0
+`
+	p, e := FromPCD(strings.NewReader(src))
+	if e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	if v.Size != 2 || len(v.Properties) != 7 {
+		t.Fatalf("unexpected vertex element %+v", v)
+	}
+	// 4.808e+06 has float bits 0x4a92ba80
+	if v.findProperty("red").float64At(0) != 0x92 || v.findProperty("green").float64At(0) != 0xba ||
+		v.findProperty("intensity").float64At(1) != 7 || v.findProperty("x").float64At(0) != 1.5 {
+		t.Error("unexpected values")
+	}
+	for _, binaryData := range []bool{false, true} {
+		var buf bytes.Buffer
+		if e := p.ToPCD(&buf, binaryData); e != nil {
+			t.Fatal(e)
+		}
+		q, e := FromPCD(&buf)
+		if e != nil {
+			t.Fatal(e)
+		}
+		var a, b bytes.Buffer
+		p.Write(&a)
+		q.Write(&b)
+		if a.String() != b.String() {
+			t.Errorf("binary=%v: round trip mismatch\n%s\n%s", binaryData, a.String(), b.String())
+		}
+	}
+	if e := p.ConvertColors("float"); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := p.ToPCD(&buf, true); e != nil {
+		t.Fatal(e)
+	}
+	q, e := FromPCD(&buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if q.GetVertices().findProperty("red").float64At(0) != 0x92 {
+		t.Error("float colors not scaled to 0-255")
+	}
+}
+
+func TestPCDLowercaseHeader(t *testing.T) {
+	src := `fields x rgb
+size 4 4
+type F U
+count 1 2
+points 1
+data ascii
+1.5 7 8
+`
+	p, e := FromPCD(strings.NewReader(src))
+	if e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	if v.Size != 1 || v.findProperty("rgb_1") == nil || v.findProperty("red") != nil {
+		t.Fatalf("expected rgb with COUNT 2 to be split, got %+v", v.Properties)
+	}
+	if v.findProperty("rgb_0").float64At(0) != 7 || v.findProperty("rgb_1").float64At(0) != 8 {
+		t.Error("unexpected values")
+	}
+}
+
+func TestPCDLyingHeader(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, e := FromPCD(strings.NewReader("FIELDS x y z\nSIZE 8 8 8\nTYPE F F F\nCOUNT 1 1 1\n" +
+		"POINTS 2000000000\nDATA ascii\n1 2 3\n")); e == nil {
+		t.Error("expected missing points to fail")
+	}
+	runtime.ReadMemStats(&after)
+	if grown := after.TotalAlloc - before.TotalAlloc; grown > 1<<24 {
+		t.Errorf("allocated %d bytes for one point", grown)
+	}
+	if _, e := FromPCD(strings.NewReader("FIELDS x\nSIZE 4\nTYPE F\nCOUNT 1\n" +
+		"WIDTH 2\nHEIGHT 2\nPOINTS 3\nDATA ascii\n1\n2\n3\n")); e == nil {
+		t.Error("expected a grid not matching POINTS to fail")
+	}
+}
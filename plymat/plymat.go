@@ -0,0 +1,70 @@
+// Package plymat adapts PLY vertex attributes to gonum matrices.
+package plymat
+
+import (
+	"errors"
+
+	ply "github.com/flywave/go-ply"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Positions returns the vertex positions as an n×3 matrix.
+func Positions(p *ply.PLY) (*mat.Dense, error) {
+	pos, e := p.Positions()
+	if e != nil {
+		return nil, e
+	}
+	return toDense(pos), nil
+}
+
+// Normals returns the vertex normals as an n×3 matrix.
+func Normals(p *ply.PLY) (*mat.Dense, error) {
+	n, e := p.Normals()
+	if e != nil {
+		return nil, e
+	}
+	return toDense(n), nil
+}
+
+// SetPositions stores an n×3 matrix into the vertex x, y and z properties.
+func SetPositions(p *ply.PLY, m mat.Matrix) error {
+	values, e := fromMatrix(m)
+	if e != nil {
+		return e
+	}
+	return p.SetPositions(values)
+}
+
+// SetNormals stores an n×3 matrix into the vertex nx, ny and nz properties.
+func SetNormals(p *ply.PLY, m mat.Matrix) error {
+	values, e := fromMatrix(m)
+	if e != nil {
+		return e
+	}
+	return p.SetNormals(values)
+}
+
+func toDense(values [][3]float64) *mat.Dense {
+	if len(values) == 0 {
+		return &mat.Dense{}
+	}
+	data := make([]float64, 0, 3*len(values))
+	for _, v := range values {
+		data = append(data, v[0], v[1], v[2])
+	}
+	return mat.NewDense(len(values), 3, data)
+}
+
+func fromMatrix(m mat.Matrix) ([][3]float64, error) {
+	r, c := m.Dims()
+	if c != 3 {
+		return nil, errors.New("Matrix must have 3 columns")
+	}
+	values := make([][3]float64, r)
+	for i := range values {
+		for j := 0; j < 3; j++ {
+			values[i][j] = m.At(i, j)
+		}
+	}
+	return values, nil
+}
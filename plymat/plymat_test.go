@@ -0,0 +1,53 @@
+package plymat
+
+import (
+	"strings"
+	"testing"
+
+	ply "github.com/flywave/go-ply"
+	"gonum.org/v1/gonum/mat"
+)
+
+const src = `ply
+format ascii 1.0
+element vertex 2
+property float x
+property float y
+property short z
+end_header
+1 2 3
+4 5 6
+`
+
+func TestPositions(t *testing.T) {
+	p := new(ply.PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	m, e := Positions(p)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if r, c := m.Dims(); r != 2 || c != 3 || m.At(1, 2) != 6 {
+		t.Errorf("unexpected matrix %v", mat.Formatted(m))
+	}
+	var scaled mat.Dense
+	scaled.Scale(2, m)
+	if e := SetPositions(p, &scaled); e != nil {
+		t.Fatal(e)
+	}
+	if e := SetNormals(p, mat.NewDense(2, 3, []float64{0, 0, 1, 0, 1, 0})); e != nil {
+		t.Fatal(e)
+	}
+	pos, _ := p.Positions()
+	n, e := Normals(p)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if pos[1] != [3]float64{8, 10, 12} || n.At(1, 1) != 1 {
+		t.Errorf("unexpected data %v %v", pos, mat.Formatted(n))
+	}
+	if e := SetPositions(p, mat.NewDense(2, 2, nil)); e == nil {
+		t.Error("expected error for wrong column count")
+	}
+}
@@ -0,0 +1,86 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// RoundPrecision snaps values to the nearest multiple of the step.
+	RoundPrecision = iota
+	// TruncatePrecision snaps values towards zero.
+	TruncatePrecision
+)
+
+// PrecisionPolicy quantizes vertex properties on write, e.g. to
+// millimetres with a Step of 0.001 for coordinates in metres. The snapped
+// values are what both binary and ASCII output hold, the latter printing
+// no more digits than they need, so exported tiles compress predictably.
+type PrecisionPolicy struct {
+	Step float64
+	// Mode is RoundPrecision or TruncatePrecision.
+	Mode int
+	// Properties names the vertex properties to quantize, x, y and z by
+	// default.
+	Properties []string
+}
+
+func (policy *PrecisionPolicy) snap(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	if policy.Mode == TruncatePrecision {
+		return math.Trunc(v/policy.Step) * policy.Step
+	}
+	return math.Round(v/policy.Step) * policy.Step
+}
+
+// quantize returns a shallow copy of p with the policy's vertex properties
+// snapped, copying only those properties.
+func (p *PLY) quantize(policy *PrecisionPolicy) (*PLY, error) {
+	if !(policy.Step > 0) {
+		return nil, errors.New("Precision step must be positive")
+	}
+	names := policy.Properties
+	if names == nil {
+		names = []string{"x", "y", "z"}
+	}
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	copy(q.Elements, p.Elements)
+	for k, elem := range p.Elements {
+		if elem.Name != "vertex" {
+			continue
+		}
+		se := *elem
+		se.Properties = append([]*Property(nil), elem.Properties...)
+		for j, prop := range elem.Properties {
+			if !containsString(names, prop.Name) {
+				continue
+			}
+			prop.load()
+			sp := *prop
+			data := newListColumn(&sp)
+			for i := range prop.Data {
+				values := prop.listFloat64At(i)
+				for n, v := range values {
+					values[n] = policy.snap(v)
+				}
+				data.add(values)
+			}
+			sp.Data = data.rows()
+			se.Properties[j] = &sp
+		}
+		q.Elements[k] = &se
+	}
+	return q, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrecisionPolicy(t *testing.T) {
+	src := strings.Replace(testASCIIMesh, "1 1 0 -1e-05", "1.23456 -0.98765 0.0004 -1e-05", 1)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	policy := &PrecisionPolicy{Step: 0.001}
+	var ascii bytes.Buffer
+	if e := p.WriteWithOptions(&ascii, &SaveOptions{Precision: policy}); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.Contains(ascii.String(), "\n1.235 -0.988 0 -1e-05\n") {
+		t.Errorf("unexpected ASCII output:\n%s", ascii.String())
+	}
+	if p.Elements[0].findProperty("x").float64At(2) == 1.235 {
+		t.Error("expected the source to be unchanged")
+	}
+
+	p.ConvertTo(BinaryLittleEndian)
+	var bin bytes.Buffer
+	policy.Mode = TruncatePrecision
+	if e := p.WriteWithOptions(&bin, &SaveOptions{Precision: policy}); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&bin); e != nil {
+		t.Fatal(e)
+	}
+	pos, _ := q.Positions()
+	if float32(pos[2][0]) != 1.234 || float32(pos[2][1]) != -0.987 {
+		t.Errorf("unexpected truncated position %v", pos[2])
+	}
+	if e := p.WriteWithOptions(&bin, &SaveOptions{Precision: &PrecisionPolicy{}}); e == nil {
+		t.Error("expected an error for a zero step")
+	}
+}
@@ -0,0 +1,123 @@
+package ply
+
+import "io"
+
+// loadedRange returns the rows of elem to decode under opts.Rows and
+// opts.MaxRows.
+func loadedRange(elem *Element, opts *LoadOptions) (start, end int) {
+	start, end = 0, elem.Size
+	if r, ok := opts.Rows[elem.Name]; ok {
+		// checked by checkSelection
+		start, end, _ = r.clamp(elem.Size)
+	}
+	if opts.MaxRows > 0 && opts.MaxRows < end-start {
+		end = start + opts.MaxRows
+	}
+	return start, end
+}
+
+// offsetRows shifts the row of a body error by the rows skipped before
+// the decoded ones.
+func offsetRows(e error, start int) error {
+	switch e := e.(type) {
+	case *TruncatedError:
+		e.Row += start
+	case *DataError:
+		e.Row += start
+	}
+	return e
+}
+
+// skipBinaryRows discards the rows of elem from row from up to row to,
+// reading only list counts.
+func skipBinaryRows(p *PLY, elem *Element, from, to int) error {
+	r := p.reader
+	rowSize, fixed := 0, true
+	for _, prop := range elem.Properties {
+		rowSize += SizeOfType[prop.Type]
+		fixed = fixed && !prop.IsList
+	}
+	if fixed {
+		want := (to - from) * rowSize
+		if n, _ := r.Discard(want); n < want {
+			return p.unexpectedEnd(elem, from+n/rowSize, "")
+		}
+		return nil
+	}
+	for i := from; i < to; i++ {
+		for _, prop := range elem.Properties {
+			want := SizeOfType[prop.Type]
+			if prop.IsList {
+				n, e := readListCount(r, prop.ListSizeType, prop.order)
+				if e == io.EOF || e == io.ErrUnexpectedEOF {
+					return p.unexpectedEnd(elem, i, prop.Name)
+				}
+				if e != nil {
+					return e
+				}
+				want *= n
+			}
+			if n, _ := r.Discard(want); n < want {
+				return p.unexpectedEnd(elem, i, prop.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// skipASCIIRows discards the lines of the rows of elem from row from up to
+// row to.
+func skipASCIIRows(p *PLY, elem *Element, from, to int) error {
+	for i := from; i < to; {
+		line, e := readLine(p.reader)
+		if e == io.EOF {
+			return p.unexpectedEnd(elem, i, "")
+		}
+		if e != nil {
+			return e
+		}
+		p.currentLine++
+		if line != "" {
+			i++
+		}
+	}
+	return nil
+}
+
+// dropUnloadedFaces removes faces referencing vertices outside the rows
+// loaded from offset on, as left by a partial load, and shifts the indices
+// of the others to the loaded rows.
+func dropUnloadedFaces(p *PLY, offset int) {
+	face := p.findElement("face")
+	if face == nil {
+		return
+	}
+	idx := p.faceIndexProperty(face)
+	if idx == nil {
+		return
+	}
+	n := p.VerticesCount()
+	var rows []int
+	for i := 0; i < face.Size; i++ {
+		ok := true
+		for _, v := range idx.listIntsAt(i) {
+			ok = ok && v >= offset && v < offset+n
+		}
+		if ok {
+			rows = append(rows, i)
+		}
+	}
+	if len(rows) < face.Size {
+		sub := face.selectRows(rows)
+		face.Properties = sub.Properties
+		face.Size = sub.Size
+		idx = p.faceIndexProperty(face)
+	}
+	for i := 0; offset > 0 && i < face.Size; i++ {
+		values := idx.listFloat64At(i)
+		for j := range values {
+			values[j] -= float64(offset)
+		}
+		idx.setListFloat64At(i, values)
+	}
+}
@@ -0,0 +1,74 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoadMaxRows(t *testing.T) {
+	// a trailing element checks that skipped rows are fully consumed
+	src := strings.Replace(testASCIIMesh, "end_header", "element edge 2\nproperty int vertex1\nend_header", 1) + "3\n\n1\n"
+	for _, format := range []int{Ascii, BinaryLittleEndian, BinaryBigEndian} {
+		var data bytes.Buffer
+		if e := Convert(strings.NewReader(src), &data, format); e != nil {
+			t.Fatal(e)
+		}
+		for _, c := range []struct{ rows, vertices, faces, edges int }{{3, 3, 1, 2}, {1, 1, 0, 1}} {
+			p := new(PLY)
+			if e := p.ReadWithOptions(bytes.NewReader(data.Bytes()), &LoadOptions{MaxRows: c.rows}); e != nil {
+				t.Fatal(e)
+			}
+			if p.VerticesCount() != c.vertices || len(p.ReadFaces()) != c.faces {
+				t.Errorf("format %d, %d rows: got %d vertices and faces %v",
+					format, c.rows, p.VerticesCount(), p.ReadFaces())
+			}
+			if edge := p.findElement("edge"); edge.Size != c.edges || edge.Properties[0].float64At(0) != 3 {
+				t.Errorf("format %d: expected the edge element after skipped rows", format)
+			}
+		}
+	}
+	truncated := strings.Replace(testASCIIMesh, "element vertex 4", "element vertex 9", 1)
+	if e := new(PLY).ReadWithOptions(strings.NewReader(truncated), &LoadOptions{MaxRows: 2}); e == nil {
+		t.Error("expected an error when skipped rows are missing")
+	}
+}
+
+func TestLoadRowRange(t *testing.T) {
+	src := benchMesh(10)
+	for _, format := range []int{Ascii, BinaryLittleEndian, BinaryBigEndian} {
+		var data bytes.Buffer
+		if e := Convert(strings.NewReader(src), &data, format); e != nil {
+			t.Fatal(e)
+		}
+		p := new(PLY)
+		opts := &LoadOptions{Rows: map[string]RowRange{"vertex": {Offset: 3, Count: 4}}}
+		if e := p.ReadWithOptions(bytes.NewReader(data.Bytes()), opts); e != nil {
+			t.Fatal(e)
+		}
+		faces := p.ReadFaces()
+		if p.VerticesCount() != 4 || p.findElement("vertex").findProperty("red").float64At(0) != 3 ||
+			len(faces) != 2 || faces[1][0] != 1 || faces[1][2] != 3 {
+			t.Errorf("format %d: got %d vertices and faces %v", format, p.VerticesCount(), faces)
+		}
+
+		opts = &LoadOptions{Rows: map[string]RowRange{"face": {Offset: 5, Count: 100}}, MaxRows: 2}
+		p = new(PLY)
+		if e := p.ReadWithOptions(bytes.NewReader(data.Bytes()), opts); e != nil {
+			t.Fatal(e)
+		}
+		if faces := p.ReadFaces(); len(faces) != 0 || p.VerticesCount() != 2 {
+			t.Errorf("format %d: expected faces beyond the loaded vertices to be dropped, got %v", format, faces)
+		}
+	}
+	bad := strings.Replace(src, "\n3 5 6 7\n", "\n3 5 x 7\n", 1)
+	opts := &LoadOptions{Rows: map[string]RowRange{"face": {Offset: 2, Count: 5}}}
+	if de, ok := new(PLY).ReadWithOptions(strings.NewReader(bad), opts).(*DataError); !ok || de.Row != 5 {
+		t.Errorf("expected the error at face 5, got %v", de)
+	}
+	for _, rows := range []map[string]RowRange{{"edge": {}}, {"vertex": {Offset: -1}}} {
+		if e := new(PLY).ReadWithOptions(strings.NewReader(src), &LoadOptions{Rows: rows}); e == nil {
+			t.Errorf("expected an error for %v", rows)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// AddProperty appends a scalar property holding values converted to
+// typeName, one per row.
+func (e *Element) AddProperty(name, typeName string, values []float64) error {
+	if e.frozen {
+		return ErrFrozen
+	}
+	if len(values) != e.Size {
+		return errors.New("Got " + itoa(len(values)) + " values for " + itoa(e.Size) + " rows")
+	}
+	if SizeOfType[typeName] == 0 {
+		return errors.New("Unknown type " + typeName)
+	}
+	if name == "" || e.findProperty(name) != nil {
+		return errors.New("Invalid or duplicate property name \"" + name + "\"")
+	}
+	prop := newProperty(name, typeName, e.Size)
+	for i, v := range values {
+		prop.setFloat64At(i, v)
+	}
+	prop.pos = len(e.Properties)
+	e.Properties = append(e.Properties, prop)
+	return nil
+}
+
+// RemoveProperty deletes the named property.
+func (e *Element) RemoveProperty(name string) error {
+	if e.frozen {
+		return ErrFrozen
+	}
+	for k, prop := range e.Properties {
+		if prop.Name == name {
+			e.Properties = append(e.Properties[:k:k], e.Properties[k+1:]...)
+			for j := k; j < len(e.Properties); j++ {
+				e.Properties[j].pos = j
+			}
+			return nil
+		}
+	}
+	return errors.New("No property " + name + " in element " + e.Name)
+}
+
+// RenameProperty renames a property, keeping its position and data.
+func (e *Element) RenameProperty(old, name string) error {
+	if e.frozen {
+		return ErrFrozen
+	}
+	prop := e.findProperty(old)
+	if prop == nil {
+		return errors.New("No property " + old + " in element " + e.Name)
+	}
+	if name == "" || (name != old && e.findProperty(name) != nil) {
+		return errors.New("Invalid or duplicate property name \"" + name + "\"")
+	}
+	prop.Name = name
+	return nil
+}
+
+// ConversionStats counts the values changed by ConvertProperty.
+type ConversionStats struct {
+	// Clamped counts values outside the new type's range, stored as the
+	// nearest bound, and NaN stored as 0 by integral types.
+	Clamped int
+	// Rounded counts values within range that the new type holds
+	// inexactly, e.g. fractions dropped by integral types or digits by
+	// float.
+	Rounded int
+}
+
+// ConvertProperty re-encodes a property, or the items of a list property,
+// as newType, e.g. double to float to halve its size. Values keep their
+// magnitude: uchar colors become floats from 0 to 255, not from 0 to 1.
+// Values that do not fit are clamped and rounded as when writing, and
+// counted in the returned stats.
+func (e *Element) ConvertProperty(name, newType string) (ConversionStats, error) {
+	var stats ConversionStats
+	if e.frozen {
+		return stats, ErrFrozen
+	}
+	prop := e.findProperty(name)
+	if prop == nil {
+		return stats, errors.New("No property " + name + " in element " + e.Name)
+	}
+	if _, ok := typeRanges[normalizeType(prop.Type)]; !ok {
+		return stats, errors.New("Cannot convert property " + name + " of type " + prop.Type)
+	}
+	if _, ok := typeRanges[normalizeType(newType)]; !ok {
+		return stats, errors.New("Unknown type " + newType)
+	}
+	converted := *prop
+	converted.Type = newType
+	size := SizeOfType[newType]
+	order := prop.byteOrder()
+	scratch := make([]byte, size)
+	convert := func(v float64) float64 {
+		f, ok := fitValue(v, newType)
+		if !ok {
+			stats.Clamped++
+			return f
+		}
+		putFloat64(scratch, f, newType, order)
+		if back := scalarFloat64(scratch, newType, order); back != v && !math.IsNaN(v) {
+			stats.Rounded++
+		}
+		return f
+	}
+	var data [][]byte
+	var column []byte
+	if prop.IsList {
+		lists := newListColumn(&converted)
+		for i := range prop.Data {
+			values := prop.listFloat64At(i)
+			for j, v := range values {
+				values[j] = convert(v)
+			}
+			lists.add(values)
+		}
+		data = lists.rows()
+	} else {
+		data = make([][]byte, len(prop.Data))
+		column = make([]byte, len(prop.Data)*size)
+		for i := range prop.Data {
+			b := column[i*size : (i+1)*size : (i+1)*size]
+			putFloat64(b, convert(prop.float64At(i)), newType, order)
+			data[i] = b
+		}
+	}
+	prop.Type, prop.Data, prop.column, prop.block = newType, data, column, nil
+	return stats, nil
+}
+
+// AppendRow adds a row with values keyed by property name. Scalars take
+// any Go integer or float type and lists a slice of one; properties
+// without a value get zero or an empty list.
+func (e *Element) AppendRow(values map[string]interface{}) error {
+	if e.frozen {
+		return ErrFrozen
+	}
+	rows := make([][]byte, len(e.Properties))
+	for name := range values {
+		if e.findProperty(name) == nil {
+			return errors.New("No property " + name + " in element " + e.Name)
+		}
+	}
+	for k, prop := range e.Properties {
+		v, ok := values[prop.Name]
+		if !ok {
+			if prop.IsList {
+				rows[k] = []byte{}
+			} else {
+				rows[k] = make([]byte, SizeOfType[prop.Type])
+			}
+			continue
+		}
+		f, ok := toFloat64s(v)
+		if !ok || !prop.IsList && len(f) != 1 {
+			return errors.New("Invalid value for property " + prop.Name)
+		}
+		rows[k] = prop.encodeList(f)
+	}
+	for k, prop := range e.Properties {
+		prop.load()
+		prop.Data = append(prop.Data, rows[k])
+	}
+	e.Size++
+	return nil
+}
+
+// DeleteRows removes the rows at the given indices, which may be in any
+// order and repeat.
+func (e *Element) DeleteRows(indices []int) error {
+	if e.frozen {
+		return ErrFrozen
+	}
+	drop := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= e.Size {
+			return errors.New("Row " + itoa(i) + " outside element " + e.Name)
+		}
+		drop[i] = true
+	}
+	for _, prop := range e.Properties {
+		data := make([][]byte, 0, e.Size-len(drop))
+		for i := 0; i < e.Size; i++ {
+			if !drop[i] {
+				data = append(data, prop.row(i))
+			}
+		}
+		prop.Data = data
+	}
+	e.Size -= len(drop)
+	return nil
+}
+
+// toFloat64s converts a Go number or slice of numbers to float64 values.
+func toFloat64s(v interface{}) ([]float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return []float64{float64(v)}, true
+	case int8:
+		return []float64{float64(v)}, true
+	case int16:
+		return []float64{float64(v)}, true
+	case int32:
+		return []float64{float64(v)}, true
+	case int64:
+		return []float64{float64(v)}, true
+	case uint:
+		return []float64{float64(v)}, true
+	case uint8:
+		return []float64{float64(v)}, true
+	case uint16:
+		return []float64{float64(v)}, true
+	case uint32:
+		return []float64{float64(v)}, true
+	case uint64:
+		return []float64{float64(v)}, true
+	case float32:
+		return []float64{float64(v)}, true
+	case float64:
+		return []float64{v}, true
+	case []float64:
+		return v, true
+	case []float32:
+		f := make([]float64, len(v))
+		for i, x := range v {
+			f[i] = float64(x)
+		}
+		return f, true
+	case []int:
+		f := make([]float64, len(v))
+		for i, x := range v {
+			f[i] = float64(x)
+		}
+		return f, true
+	case []int32:
+		f := make([]float64, len(v))
+		for i, x := range v {
+			f[i] = float64(x)
+		}
+		return f, true
+	case []uint32:
+		f := make([]float64, len(v))
+		for i, x := range v {
+			f[i] = float64(x)
+		}
+		return f, true
+	case []uint8:
+		f := make([]float64, len(v))
+		for i, x := range v {
+			f[i] = float64(x)
+		}
+		return f, true
+	}
+	return nil, false
+}
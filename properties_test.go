@@ -0,0 +1,143 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPropertyEditing(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	vertex := p.findElement("vertex")
+	if e := vertex.AddProperty("confidence", "float", []float64{0.5, 1, 0, 0.25}); e != nil {
+		t.Fatal(e)
+	}
+	if e := vertex.AddProperty("confidence", "float", []float64{0, 0, 0, 0}); e == nil {
+		t.Error("expected an error for a duplicate name")
+	}
+	if e := vertex.AddProperty("short", "float", []float64{0}); e == nil {
+		t.Error("expected an error for a wrong value count")
+	}
+	if e := vertex.RemoveProperty("quality"); e != nil {
+		t.Fatal(e)
+	}
+	if e := vertex.RenameProperty("confidence", "weight"); e != nil {
+		t.Fatal(e)
+	}
+	if e := vertex.RenameProperty("weight", "x"); e == nil {
+		t.Error("expected an error for renaming onto an existing property")
+	}
+	if e := vertex.RemoveProperty("quality"); e == nil {
+		t.Error("expected an error for a missing property")
+	}
+	for k, prop := range vertex.Properties {
+		if prop.pos != k {
+			t.Errorf("property %s at %d has pos %d", prop.Name, k, prop.pos)
+		}
+	}
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.Contains(out.String(), "property float weight\nelement face") ||
+		!strings.Contains(out.String(), "\n1 1 0 0\n") {
+		t.Errorf("unexpected output\n%s", out.String())
+	}
+}
+
+func TestAppendDeleteRows(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	vertex, face := p.findElement("vertex"), p.findElement("face")
+	if e := vertex.AppendRow(map[string]interface{}{"x": 2, "y": float32(3), "quality": 0.75}); e != nil {
+		t.Fatal(e)
+	}
+	if e := face.AppendRow(map[string]interface{}{"vertex_indices": []int{4, 1, 2}, "flags": int16(-1)}); e != nil {
+		t.Fatal(e)
+	}
+	if e := vertex.AppendRow(map[string]interface{}{"w": 1}); e == nil {
+		t.Error("expected an error for an unknown property")
+	}
+	if e := vertex.AppendRow(map[string]interface{}{"x": "1"}); e == nil {
+		t.Error("expected an error for a non-numeric value")
+	}
+	if vertex.Size != 5 || len(vertex.findProperty("z").Data) != 5 {
+		t.Fatal("expected failed appends to leave the element unchanged")
+	}
+	pos, _ := p.Positions()
+	if pos[4] != [3]float64{2, 3, 0} || face.findProperty("flags").float64At(2) != -1 {
+		t.Errorf("unexpected appended rows %v", pos[4])
+	}
+	if e := face.DeleteRows([]int{1, 0, 1}); e != nil {
+		t.Fatal(e)
+	}
+	if faces := p.ReadFaces(); len(faces) != 1 || faces[0][0] != 4 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if e := face.DeleteRows([]int{1}); e == nil {
+		t.Error("expected an error for a row out of range")
+	}
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.HasSuffix(out.String(), "2 3 0 0.75\n3 4 1 2 -1\n") {
+		t.Errorf("unexpected output\n%s", out.String())
+	}
+}
+
+func TestConvertProperty(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	vertex := p.findElement("vertex")
+	stats, e := vertex.ConvertProperty("quality", "float")
+	if e != nil {
+		t.Fatal(e)
+	}
+	// 0.5, 0.25 and 3 are exact as floats, -1e-05 is not
+	if stats.Clamped != 0 || stats.Rounded != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	quality := vertex.findProperty("quality")
+	if quality.Type != "float" || len(quality.row(0)) != 4 || quality.float64At(3) != 3 {
+		t.Errorf("unexpected converted property %s %v", quality.Type, quality.row(0))
+	}
+	face := p.findElement("face")
+	if stats, e = face.ConvertProperty("flags", "char"); e != nil {
+		t.Fatal(e)
+	}
+	if stats.Clamped != 1 || face.findProperty("flags").float64At(1) != 127 {
+		t.Errorf("expected 300 clamped to 127, got %+v", stats)
+	}
+	if stats, e = face.ConvertProperty("vertex_indices", "ushort"); e != nil {
+		t.Fatal(e)
+	}
+	if stats != (ConversionStats{}) {
+		t.Errorf("unexpected stats %+v for indices", stats)
+	}
+	faces := p.ReadFaces()
+	if len(faces) != 2 || len(faces[1]) != 4 || faces[1][3] != 3 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if _, e = vertex.ConvertProperty("x", "vec3"); e == nil {
+		t.Error("expected an error for an unknown type")
+	}
+	var buf bytes.Buffer
+	if e = p.Write(&buf); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e = q.Read(&buf); e != nil {
+		t.Fatal(e)
+	}
+	if got := q.findElement("face").findProperty("vertex_indices").Type; got != "ushort" {
+		t.Errorf("expected the header to list ushort indices, got %s", got)
+	}
+}
@@ -0,0 +1,35 @@
+package ply
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+)
+
+// pyramidSeed makes pyramids reproducible across runs.
+const pyramidSeed = 1
+
+// Pyramid returns one subsampled copy of p per fraction, e.g. 1, 0.25 and
+// 0.05, keeping about that share of the vertices with all their
+// properties. Vertices are ranked once by a fixed pseudo-random order, so
+// every level is a subset of the levels with larger fractions. Faces
+// referencing a dropped vertex are removed.
+func (p *PLY) Pyramid(fractions []float64) ([]*PLY, error) {
+	n := p.VerticesCount()
+	for _, f := range fractions {
+		if !(f > 0 && f <= 1) {
+			return nil, errors.New("Pyramid fraction " +
+				strconv.FormatFloat(f, 'g', -1, 64) + " outside (0, 1]")
+		}
+	}
+	rank := rand.New(rand.NewSource(pyramidSeed)).Perm(n)
+	levels := make([]*PLY, len(fractions))
+	for k, f := range fractions {
+		count := int(f*float64(n) + 0.5)
+		if count == 0 && n > 0 {
+			count = 1
+		}
+		levels[k] = p.filterVertices(func(i int) bool { return rank[i] < count })
+	}
+	return levels, nil
+}
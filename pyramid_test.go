@@ -0,0 +1,47 @@
+package ply
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPyramid(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("ply\nformat ascii 1.0\nelement vertex 100\nproperty float x\nproperty float y\nproperty float z\nproperty uchar red\nend_header\n")
+	for i := 0; i < 100; i++ {
+		src.WriteString(strconv.Itoa(i) + " 0 0 " + strconv.Itoa(i) + "\n")
+	}
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src.String())); e != nil {
+		t.Fatal(e)
+	}
+	levels, e := p.Pyramid([]float64{1, 0.25, 0.05})
+	if e != nil {
+		t.Fatal(e)
+	}
+	for k, want := range []int{100, 25, 5} {
+		if n := levels[k].VerticesCount(); n != want {
+			t.Errorf("level %d: expected %d vertices, got %d", k, want, n)
+		}
+	}
+	fine := make(map[float64]bool)
+	for _, x := range levels[1].ReadVertices()[0] {
+		fine[float64(x)] = true
+	}
+	red := levels[2].Elements[0].findProperty("red")
+	for i, x := range levels[2].ReadVertices()[0] {
+		if !fine[float64(x)] {
+			t.Errorf("vertex %v of the coarsest level missing from the finer one", x)
+		}
+		if red.float64At(i) != float64(x) {
+			t.Error("expected attributes to follow their vertices")
+		}
+	}
+	if p.VerticesCount() != 100 {
+		t.Error("expected the source to be unchanged")
+	}
+	if _, e := p.Pyramid([]float64{0}); e == nil {
+		t.Error("expected an error for a zero fraction")
+	}
+}
@@ -0,0 +1,239 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Plane holds the points v with dot(Normal, v) + D = 0, Normal being a
+// unit vector, as taken by ClipPlane.
+type Plane struct {
+	Normal [3]float64
+	D      float64
+}
+
+// Distance returns the distance of v to the plane.
+func (pl Plane) Distance(v [3]float64) float64 {
+	return math.Abs(dot3(pl.Normal, v) + pl.D)
+}
+
+type Sphere struct {
+	Center [3]float64
+	Radius float64
+}
+
+// Distance returns the distance of v to the surface of the sphere.
+func (s Sphere) Distance(v [3]float64) float64 {
+	return math.Abs(length3(sub3(v, s.Center)) - s.Radius)
+}
+
+type RANSACOptions struct {
+	// Threshold is the largest distance of an inlier to the model.
+	Threshold float64
+	// Iterations is the number of random samples tried, 1000 by default.
+	Iterations int
+	// Seed seeds the sampling, for reproducible fits.
+	Seed int64
+}
+
+// FitPlane finds the plane with the most vertices within opts.Threshold by
+// RANSAC, refined by least squares over its inliers, and returns it with
+// the inlier vertex indices in ascending order.
+func (p *PLY) FitPlane(opts *RANSACOptions) (Plane, []int, error) {
+	m, inliers, e := p.ransac(opts, 3, func(points [][3]float64) (primitive, bool) {
+		n := normalize3(cross3(sub3(points[1], points[0]), sub3(points[2], points[0])))
+		if n == ([3]float64{}) {
+			return nil, false
+		}
+		return Plane{n, -dot3(n, points[0])}, true
+	}, func(points [][3]float64) (primitive, bool) {
+		idx := make([]int, len(points))
+		for k := range idx {
+			idx[k] = k
+		}
+		mean, c := covariance3(points, idx)
+		_, vectors := symmetricEigen3(c)
+		n := normalize3(vectors[0])
+		return Plane{n, -dot3(n, mean)}, true
+	})
+	if e != nil {
+		return Plane{}, nil, e
+	}
+	return m.(Plane), inliers, nil
+}
+
+// FitSphere finds the sphere with the most vertices within opts.Threshold
+// of its surface by RANSAC, refined by least squares over its inliers,
+// and returns it with the inlier vertex indices in ascending order.
+func (p *PLY) FitSphere(opts *RANSACOptions) (Sphere, []int, error) {
+	fit := func(points [][3]float64) (primitive, bool) {
+		s, ok := fitSphere(points)
+		return s, ok
+	}
+	m, inliers, e := p.ransac(opts, 4, fit, fit)
+	if e != nil {
+		return Sphere{}, nil, e
+	}
+	return m.(Sphere), inliers, nil
+}
+
+// ExtractVertices returns a copy of p holding only the given vertices,
+// e.g. the inliers of a fit, with the faces among them re-indexed.
+func (p *PLY) ExtractVertices(indices []int) *PLY {
+	keep := indexSet(indices)
+	return p.filterVertices(func(i int) bool { return keep[i] })
+}
+
+// ExcludeVertices returns a copy of p without the given vertices, e.g. to
+// remove a fitted floor before fitting the next primitive. Faces
+// referencing them are removed and the others re-indexed.
+func (p *PLY) ExcludeVertices(indices []int) *PLY {
+	drop := indexSet(indices)
+	return p.filterVertices(func(i int) bool { return !drop[i] })
+}
+
+func indexSet(indices []int) map[int]bool {
+	set := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		set[i] = true
+	}
+	return set
+}
+
+// primitive is a model fitted by ransac.
+type primitive interface {
+	Distance(v [3]float64) float64
+}
+
+// ransac fits primitives to random samples of size vertices and keeps the
+// one with the most inliers, ties going to the smallest summed distance.
+// It then refits the primitive to its inliers with refine, keeping the
+// result unless it loses inliers.
+func (p *PLY) ransac(opts *RANSACOptions, size int,
+	fit, refine func(points [][3]float64) (primitive, bool)) (primitive, []int, error) {
+	if opts == nil || !(opts.Threshold > 0) {
+		return nil, nil, errors.New("RANSAC needs a positive threshold")
+	}
+	iterations := opts.Iterations
+	if iterations == 0 {
+		iterations = 1000
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, nil, e
+	}
+	var valid []int
+	for i, v := range pos {
+		if !isInvalidPoint(v) {
+			valid = append(valid, i)
+		}
+	}
+	if len(valid) < size {
+		return nil, nil, errors.New("Too few vertices to fit")
+	}
+	score := func(m primitive) ([]int, float64) {
+		var inliers []int
+		sum := 0.0
+		for _, i := range valid {
+			if d := m.Distance(pos[i]); d <= opts.Threshold {
+				inliers = append(inliers, i)
+				sum += d
+			}
+		}
+		return inliers, sum
+	}
+	r := rand.New(rand.NewSource(opts.Seed))
+	var best primitive
+	var bestInliers []int
+	bestSum := math.Inf(1)
+	points := make([][3]float64, size)
+	for n := 0; n < iterations; n++ {
+		for k := range points {
+			points[k] = pos[valid[r.Intn(len(valid))]]
+		}
+		m, ok := fit(points)
+		if !ok {
+			continue
+		}
+		inliers, sum := score(m)
+		if len(inliers) > len(bestInliers) || len(inliers) == len(bestInliers) && sum < bestSum {
+			best, bestInliers, bestSum = m, inliers, sum
+		}
+	}
+	if best == nil {
+		return nil, nil, errors.New("No model fits the vertices")
+	}
+	inlierPoints := make([][3]float64, len(bestInliers))
+	for k, i := range bestInliers {
+		inlierPoints[k] = pos[i]
+	}
+	if m, ok := refine(inlierPoints); ok {
+		if inliers, _ := score(m); len(inliers) >= len(bestInliers) {
+			return m, inliers, nil
+		}
+	}
+	return best, bestInliers, nil
+}
+
+// fitSphere fits a sphere to at least four points by linear least
+// squares on x² + y² + z² + a x + b y + c z + d = 0.
+func fitSphere(points [][3]float64) (Sphere, bool) {
+	var m [4][5]float64
+	for _, v := range points {
+		row := [5]float64{v[0], v[1], v[2], 1, -dot3(v, v)}
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 5; c++ {
+				m[r][c] += row[r] * row[c]
+			}
+		}
+	}
+	x, ok := solve4(m)
+	if !ok {
+		return Sphere{}, false
+	}
+	center := [3]float64{-x[0] / 2, -x[1] / 2, -x[2] / 2}
+	r2 := dot3(center, center) - x[3]
+	if !(r2 > 0) || math.IsInf(r2, 0) {
+		return Sphere{}, false
+	}
+	return Sphere{center, math.Sqrt(r2)}, true
+}
+
+// solve4 solves the 4x4 linear system held with its right-hand side in m
+// by Gaussian elimination with partial pivoting.
+func solve4(m [4][5]float64) ([4]float64, bool) {
+	scale := 0.0
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			scale = math.Max(scale, math.Abs(m[r][c]))
+		}
+	}
+	for c := 0; c < 4; c++ {
+		pivot := c
+		for r := c + 1; r < 4; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[pivot][c]) {
+				pivot = r
+			}
+		}
+		if !(math.Abs(m[pivot][c]) > 1e-12*scale) {
+			return [4]float64{}, false
+		}
+		m[c], m[pivot] = m[pivot], m[c]
+		for r := c + 1; r < 4; r++ {
+			f := m[r][c] / m[c][c]
+			for k := c; k < 5; k++ {
+				m[r][k] -= f * m[c][k]
+			}
+		}
+	}
+	var x [4]float64
+	for r := 3; r >= 0; r-- {
+		s := m[r][4]
+		for k := r + 1; k < 4; k++ {
+			s -= m[r][k] * x[k]
+		}
+		x[r] = s / m[r][r]
+	}
+	return x, true
+}
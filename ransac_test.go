@@ -0,0 +1,54 @@
+package ply
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFitPlaneAndSphere(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var pos [][3]float64
+	// a noisy floor, a sphere resting above it and some clutter
+	for i := 0; i < 400; i++ {
+		pos = append(pos, [3]float64{r.Float64() * 10, r.Float64() * 10, (r.Float64() - 0.5) * 0.004})
+	}
+	for i := 0; i < 200; i++ {
+		d := normalize3([3]float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()})
+		pos = append(pos, add3([3]float64{5, 5, 5}, scale3(d, 2+(r.Float64()-0.5)*0.004)))
+	}
+	for i := 0; i < 50; i++ {
+		pos = append(pos, [3]float64{r.Float64() * 10, r.Float64() * 10, 1 + r.Float64()*10})
+	}
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	plane, inliers, e := p.FitPlane(&RANSACOptions{Threshold: 0.01})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if math.Abs(math.Abs(plane.Normal[2])-1) > 1e-3 || math.Abs(plane.D) > 1e-3 {
+		t.Errorf("unexpected plane %+v", plane)
+	}
+	if len(inliers) != 400 || inliers[399] != 399 {
+		t.Errorf("expected the floor as inliers, got %d", len(inliers))
+	}
+	if n := p.ExtractVertices(inliers).VerticesCount(); n != 400 {
+		t.Errorf("expected 400 extracted vertices, got %d", n)
+	}
+	rest := p.ExcludeVertices(inliers)
+	sphere, inliers, e := rest.FitSphere(&RANSACOptions{Threshold: 0.01, Seed: 3})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if length3(sub3(sphere.Center, [3]float64{5, 5, 5})) > 0.01 || math.Abs(sphere.Radius-2) > 0.01 {
+		t.Errorf("unexpected sphere %+v", sphere)
+	}
+	if len(inliers) != 200 {
+		t.Errorf("expected the sphere points as inliers, got %d", len(inliers))
+	}
+	if _, _, e := p.FitPlane(nil); e == nil {
+		t.Error("expected an error without a threshold")
+	}
+}
@@ -2,11 +2,10 @@ package ply
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/binary"
 	"errors"
-	"fmt"
 	"io"
+	"math"
 	"os"
 	"regexp"
 	"strconv"
@@ -21,6 +20,10 @@ var Types []string = []string{"invalid", "int8", "int16", "int32", "uint8", "uin
 
 var OldTypes []string = []string{"invalid", "char", "short", "int", "uchar", "ushort", "uint", "float", "double"}
 
+// FaceIndexNames are the names searched, in order, for the vertex index
+// list of the face element. PLY.FaceIndexNames overrides them per file.
+var FaceIndexNames []string = []string{"vertex_indices", "vertex_index", "vertex_list"}
+
 var SizeOfType = map[string]int{
 	"invalid": 0,
 	"int8":    1,
@@ -47,24 +50,30 @@ type Property struct {
 	Type         string
 	ListSizeType string
 	pos          int
+	order        binary.ByteOrder
+	// column backs Data when packed by Element.Column
+	column []byte
+	// block holds the undecoded rows of a lazily loaded property, which
+	// start at offset in each of its records
+	block  *recordBlock
+	offset int
 }
 
 type Element struct {
 	Name       string
 	Properties []*Property
 	Size       int
+	// records backs the scalar rows when packed by Element.Records
+	records []byte
+	stride  int
+	frozen  bool
 }
 
-func (p *Property) print() {
-	if p.IsList {
-		fmt.Printf("\tproperty list %s %s %s\n", p.ListSizeType, p.Type, p.Name)
-	} else {
-		fmt.Printf("\tproperty %s %s\n", p.Type, p.Name)
+func (p *Property) byteOrder() binary.ByteOrder {
+	if p.order == nil {
+		return binary.LittleEndian
 	}
-}
-
-func (e *Element) print() {
-	fmt.Printf("element %s\n", e.Name)
+	return p.order
 }
 
 const (
@@ -77,37 +86,198 @@ type PLY struct {
 	Elements     []*Element
 	FileType     int8
 	ObjInfoItems map[string]string
-	currentLine  int
-	filename     string
-	reader       *bufio.Reader
-	byteOrder    binary.ByteOrder
+	Comments     []string
+	// FaceIndexNames overrides the package FaceIndexNames when non-nil.
+	FaceIndexNames []string
+	// LoadErrors holds the body errors recovered from by the last load
+	// with LoadOptions.RecoverPartial.
+	LoadErrors  []error
+	currentLine int
+	filename    string
+	reader      *bufio.Reader
+	byteOrder   binary.ByteOrder
+	closed      bool
+	frozen      bool
+	// decoder, slabs and slab back the rows decoded by a Decoder
+	decoder *Decoder
+	slabs   *slabSet
+	slab    []byte
+	// header holds the raw header lines when loaded with KeepHeader
+	header []string
 }
 
-func (p *PLY) Save(filename string) error {
-	return nil
+type LoadOptions struct {
+	// Tokenizer splits ASCII data lines into value tokens. DefaultTokenizer
+	// is used when nil.
+	Tokenizer Tokenizer
+	// CheckFaceIndices verifies that every face index is within
+	// [0, vertex count) and fails with a *FaceIndexError otherwise.
+	CheckFaceIndices bool
+	// Input limits what malformed input can make the reader allocate.
+	Input *InputPolicy
+	// ByteOrders overrides the file's byte order for binary properties,
+	// keyed by "element.property", to salvage files from exporters that
+	// mix endianness. List counts are read in the overriding order too.
+	ByteOrders map[string]binary.ByteOrder
+	// MaxRows, when positive, keeps only the first MaxRows rows of each
+	// element, e.g. for previews of huge files. The remaining rows are
+	// skipped without being decoded and faces referencing vertices that
+	// were not loaded are dropped.
+	MaxRows int
+	// Layout arranges the decoded data, see Element.SetLayout.
+	Layout int
+	// Assertions are checked once the header is read, failing with an
+	// *AssertionError before the body is decoded.
+	Assertions *Assertions
+	// KeepHeader records the header lines verbatim so that
+	// SaveOptions.VerbatimHeader can write them back unchanged.
+	KeepHeader bool
+	// BufferSize is the size of the read buffer, 4096 bytes by default.
+	BufferSize int
+	// KeepTruncatedRows keeps the complete rows of a truncated body: the
+	// element where the data ran out is cut to them, later elements are
+	// left empty and faces referencing missing vertices are dropped. Read
+	// still returns the *TruncatedError.
+	KeepTruncatedRows bool
+	// RecoverPartial handles any error decoding the body like
+	// KeepTruncatedRows, but records it in PLY.LoadErrors and returns the
+	// usable data without an error. The checksum is then not verified.
+	RecoverPartial bool
+	// VerifyChecksum checks the body against the checksum comment added by
+	// SaveOptions.Checksum, if the header has one.
+	VerifyChecksum bool
+	// Lazy keeps the records of binary elements without list properties
+	// undecoded until a property is first used, so that reading positions
+	// does not pay for colors or normals. Property.Data of such properties
+	// is nil until then; call PLY.DecodeAll before reading it directly or
+	// from several goroutines at once. ASCII files are always decoded at
+	// once, as malformed values must fail the load.
+	Lazy bool
+	// Properties, when not nil, loads only the listed properties, named
+	// "element.property", skipping the others in the body without decoding
+	// them. Elements without a listed property are left out.
+	Properties []string
+	// Rows loads only the given range of rows of an element, keyed by
+	// element name, skipping the others. MaxRows then limits the rows
+	// loaded from the range. Restricting the vertex element drops the faces
+	// referencing vertices outside it and shifts the indices of the rest.
+	Rows map[string]RowRange
 }
 
 func (p *PLY) Load(filename string) error {
-	p.filename = filename
+	return p.LoadWithOptions(filename, nil)
+}
+
+func (p *PLY) LoadWithOptions(filename string, opts *LoadOptions) error {
 	file, err := os.Open(filename)
-	p.reader = bufio.NewReader(file)
 	if err != nil {
 		return err
 	}
-	e := parseHeader(p)
+	defer file.Close()
+	p.filename = filename
+	return p.read(file, opts)
+}
+
+// Read decodes a PLY file from r. Malformed input of any kind, here and in
+// the other load functions, yields an error and never a panic; see
+// InputPolicy for bounding the memory it may use.
+func (p *PLY) Read(r io.Reader) error {
+	return p.ReadWithOptions(r, nil)
+}
+
+func (p *PLY) ReadWithOptions(r io.Reader, opts *LoadOptions) error {
+	if p.filename == "" {
+		p.filename = "<reader>"
+	}
+	return p.read(r, opts)
+}
+
+func (p *PLY) read(r io.Reader, opts *LoadOptions) error {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+	return p.decode(&Decoder{r: r, opts: opts})
+}
+
+func (p *PLY) decode(d *Decoder) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	opts := d.opts
+	br, e := d.reader()
+	if e != nil {
+		return e
+	}
+	// rows of an earlier load may still be in use, so its slabs are
+	// dropped rather than reused
+	p.decoder, p.slabs, p.slab = d, nil, nil
+	p.reader = br
+	defer func() { p.reader = nil }()
+	p.closed = false
+	p.LoadErrors = nil
+	p.header = nil
+	if opts.KeepHeader {
+		p.header = []string{}
+	}
+	e = parseHeader(p, opts.Input)
 	if e != nil {
 		return e
 	}
+	if e = checkSelection(p, opts); e != nil {
+		return e
+	}
+	vertexStart := 0
+	if vertex := p.findElement("vertex"); vertex != nil {
+		vertexStart, _ = loadedRange(vertex, opts)
+	}
+	if opts.Assertions != nil {
+		if e = opts.Assertions.Check(p); e != nil {
+			return e
+		}
+	}
+	var verify func() error
+	if opts.VerifyChecksum {
+		if verify, e = p.checksumReader(); e != nil {
+			return e
+		}
+	}
 	switch p.FileType {
 	case BinaryBigEndian:
-		e = parseBinaryBigEndian(p)
+		e = parseBinaryBigEndian(p, opts)
 	case BinaryLittleEndian:
-		e = parseBinaryLittleEndian(p)
+		e = parseBinaryLittleEndian(p, opts)
 	case Ascii:
-		e = parseASCII(p)
+		tokenizer := opts.Tokenizer
+		if tokenizer == nil {
+			tokenizer = DefaultTokenizer
+		}
+		e = parseASCII(p, tokenizer, opts)
 	default:
 		e = errors.New("File type error")
 	}
+	dropUnselected(p, opts)
+	if _, truncated := e.(*TruncatedError); e != nil && (truncated && opts.KeepTruncatedRows || opts.RecoverPartial) {
+		p.keepCompleteRows()
+		dropUnloadedFaces(p, vertexStart)
+		if opts.RecoverPartial {
+			p.LoadErrors = append(p.LoadErrors, e)
+			e, verify = nil, nil
+		}
+	}
+	if e == nil && verify != nil {
+		e = verify()
+	}
+	if e == nil && (opts.MaxRows > 0 || opts.Rows != nil) {
+		dropUnloadedFaces(p, vertexStart)
+	}
+	for _, elem := range p.Elements {
+		if e == nil && opts.Layout != ScatteredLayout {
+			e = elem.SetLayout(opts.Layout)
+		}
+	}
+	if e == nil && opts.CheckFaceIndices {
+		e = checkFaceIndices(p)
+	}
 	return e
 }
 
@@ -125,47 +295,44 @@ func (p *PLY) GetByteOrder() binary.ByteOrder {
 }
 
 func (p *PLY) GetVertices() *Element {
-	flag := false
-	count := 0
-	for _, elem := range p.Elements {
-		if elem.Name == "vertex" {
-			flag = true
-			break
-		}
-		count++
-	}
-	if flag {
-		return p.Elements[count]
-	}
-	return nil
+	return p.findElement("vertex")
 }
 
+// ReadVertices returns the vertex coordinates as x, y and z columns
+// converted to float32 from whatever numeric type they are stored in.
+// Files without x, y and z properties fall back to the first three scalar
+// properties, as earlier versions did.
 func (p *PLY) ReadVertices() [][]float32 {
-	flag := false
-	count := 0
-	for _, elem := range p.Elements {
-		if elem.Name == "vertex" {
-			flag = true
-			break
-		}
-		count++
-	}
-	if flag {
-		data := make([][]float32, 3)
-		for j := 0; j < 3; j++ {
-			elem := p.Elements[count]
-			b := elem.Properties[j].Data
-			i := 0
-			data[j] = make([]float32, elem.Size)
-			for _, v := range b {
-				buf := bytes.NewBuffer(v)
-				binary.Read(buf, p.byteOrder, &data[j][i])
-				i++
+	elem := p.findElement("vertex")
+	if elem == nil {
+		return nil
+	}
+	props := elem.scalarProperties("x", "y", "z")
+	if props == nil {
+		for _, prop := range elem.Properties {
+			if !prop.IsList && len(props) < 3 {
+				props = append(props, prop)
 			}
 		}
-		return data
+		if len(props) < 3 {
+			return nil
+		}
 	}
-	return nil
+	data := make([][]float32, 3)
+	for j, prop := range props {
+		data[j] = prop.float32s(elem.Size)
+	}
+	return data
+}
+
+// ReadFaces returns the vertex indices of every face, or nil if there is
+// no face element with a vertex index list.
+func (p *PLY) ReadFaces() [][]int {
+	faces, e := p.faceIndices()
+	if e != nil {
+		return nil
+	}
+	return faces
 }
 
 func strip(s string) string {
@@ -174,108 +341,68 @@ func strip(s string) string {
 
 func readLine(r *bufio.Reader) (line string, e error) {
 	line, e = r.ReadString('\n')
+	if e == io.EOF && len(line) > 0 {
+		return strip(line), nil
+	}
 	if e != nil {
 		return line, e
 	}
 	return strip(line), nil
 }
 
-func toType(data, typeName string) (b []byte, e error) {
-	var n int64
-	var u uint64
-	var f float64
-	switch {
-	case typeName == Types[1] || typeName == OldTypes[1]:
-		n, e = strconv.ParseInt(data, 0, 8)
-		t := int8(n)
-		if e != nil {
-			return nil, e
-		}
-		b = make([]byte, 1)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[2] || typeName == OldTypes[2]:
-		n, e = strconv.ParseInt(data, 0, 16)
-		t := int16(n)
-		if e != nil {
-			return nil, e
-		}
-		b := make([]byte, 2)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[3] || typeName == OldTypes[3]:
-		n, e = strconv.ParseInt(data, 0, 32)
-		t := int32(n)
-		if e != nil {
-			return nil, e
-		}
-		b := make([]byte, 4)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[4] || typeName == OldTypes[4]:
-		u, e = strconv.ParseUint(data, 0, 8)
-		t := uint8(u)
-		if e != nil {
-			return nil, e
-		}
-		b = make([]byte, 1)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[5] || typeName == OldTypes[5]:
-		u, e = strconv.ParseUint(data, 0, 16)
-		t := uint16(u)
-		if e != nil {
-			return nil, e
-		}
-		b := make([]byte, 2)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[6] || typeName == OldTypes[6]:
-		u, e = strconv.ParseUint(data, 0, 32)
-		t := uint32(u)
-		if e != nil {
-			return nil, e
-		}
-		b := make([]byte, 4)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[7] || typeName == OldTypes[7]:
-		f, e = strconv.ParseFloat(data, 32)
-		t := float32(f)
-		if e != nil {
-			return nil, e
-		}
-		b := make([]byte, 4)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
-	case typeName == Types[8] || typeName == OldTypes[8]:
-		f, e = strconv.ParseFloat(data, 64)
-		t := float64(f)
-		if e != nil {
-			return nil, e
-		}
-		b := make([]byte, 8)
-		buf := bytes.NewBuffer(b)
-		binary.Write(buf, binary.LittleEndian, &t)
-		return b, nil
+// readHeaderLine is readLine on p.reader, recording the raw line when the
+// header is kept.
+func (p *PLY) readHeaderLine() (string, error) {
+	line, e := p.reader.ReadString('\n')
+	if e == io.EOF && len(line) > 0 {
+		e = nil
+	}
+	if e != nil {
+		return line, e
 	}
-	return nil, nil
+	if p.header != nil {
+		p.header = append(p.header, line)
+	}
+	return strip(line), nil
+}
+
+func parseIntToken(data string, bitSize int) (int64, error) {
+	n, e := strconv.ParseInt(data, 10, bitSize)
+	if e == nil {
+		return n, nil
+	}
+	// some exporters write integral values as "3.0" or "1e2"
+	f, fe := strconv.ParseFloat(data, 64)
+	if fe != nil || f != math.Trunc(f) {
+		return 0, e
+	}
+	return strconv.ParseInt(strconv.FormatFloat(f, 'f', -1, 64), 10, bitSize)
+}
+
+func parseUintToken(data string, bitSize int) (uint64, error) {
+	u, e := strconv.ParseUint(data, 10, bitSize)
+	if e == nil {
+		return u, nil
+	}
+	f, fe := strconv.ParseFloat(data, 64)
+	if fe != nil || f != math.Trunc(f) || f < 0 {
+		return 0, e
+	}
+	return strconv.ParseUint(strconv.FormatFloat(f, 'f', -1, 64), 10, bitSize)
+}
+
+// out-of-range floats parse to ±Inf or 0, which is what exporters meant
+func isRangeError(e error) bool {
+	ne, ok := e.(*strconv.NumError)
+	return ok && ne.Err == strconv.ErrRange
 }
 
 func itoa(n int) string {
 	return strconv.Itoa(n)
 }
 
-func parseHeader(p *PLY) error {
-	r := p.reader
-	line, e := readLine(r)
+func parseHeader(p *PLY, policy *InputPolicy) error {
+	line, e := p.readHeaderLine()
 	if e != nil {
 		return e
 	}
@@ -284,7 +411,7 @@ func parseHeader(p *PLY) error {
 	}
 	p.currentLine++
 
-	line, e = readLine(r)
+	line, e = p.readHeaderLine()
 	if e != nil {
 		return e
 	}
@@ -312,29 +439,53 @@ func parseHeader(p *PLY) error {
 	}
 	currentElem := -1
 	propPos := 0
+	malformed := func() error {
+		return errors.New("Incorrect format in " +
+			p.filename + " at line " + itoa(p.currentLine))
+	}
 	for {
-		line, e = readLine(r)
+		line, e = p.readHeaderLine()
+		if e == io.EOF {
+			return errors.New("Missing end_header in " + p.filename)
+		}
 		if e != nil {
 			return e
 		}
 		p.currentLine++
+		if e = policy.checkHeaderLines(p); e != nil {
+			return e
+		}
 		words = wordMatcher.FindAllStringSubmatch(line, -1)
+		if len(words) == 0 {
+			continue
+		}
 		if words[0][0] == "comment" {
-			// skip
+			p.Comments = append(p.Comments, strip(strings.TrimPrefix(line, "comment")))
 		} else if words[0][0] == "element" {
+			if len(words) < 3 {
+				return malformed()
+			}
 			elemName := words[1][0]
 			elem := new(Element)
-			num, e := strconv.ParseInt(words[2][0], 0, 32)
+			// the word matcher drops signs, so take the count verbatim
+			fields := strings.Fields(line)
+			num, e := strconv.ParseInt(fields[len(fields)-1], 0, 32)
 			if e != nil {
 				return errors.New(e.Error() + p.filename +
 					" at line " + itoa(p.currentLine))
 			}
 			elem.Size = int(num)
 			elem.Name = elemName
+			if e = policy.checkElementSize(p, elem); e != nil {
+				return e
+			}
 			p.Elements = append(p.Elements, elem)
 			currentElem++
 			propPos = 0
 		} else if words[0][0] == "property" {
+			if currentElem < 0 || len(words) < 3 || words[1][0] == "list" && len(words) < 5 {
+				return malformed()
+			}
 			cnt := 1
 			currWord := words[cnt][0]
 			prop := new(Property)
@@ -361,7 +512,12 @@ func parseHeader(p *PLY) error {
 			if p.ObjInfoItems == nil {
 				p.ObjInfoItems = make(map[string]string)
 			}
-			p.ObjInfoItems[words[1][0]] = words[2][0]
+			if len(words) < 2 {
+				return malformed()
+			}
+			key := words[1][0]
+			value := strip(line[strings.Index(line, key)+len(key):])
+			p.ObjInfoItems[key] = value
 		} else if words[0][0] == "end_header" {
 			break
 		}
@@ -369,159 +525,202 @@ func parseHeader(p *PLY) error {
 	return nil
 }
 
-func appendBytes(slice, data []byte) []byte {
-	l := len(slice)
-	if l+len(data) > cap(slice) {
-		newSlice := make([]byte, (l+len(data))*2)
-		for i, c := range slice {
-			newSlice[i] = c
+func parseBinary(p *PLY, opts *LoadOptions) error {
+	for _, elem := range p.Elements {
+		for _, prop := range elem.Properties {
+			prop.Data = newRows(elem.Size)
+			prop.order = p.byteOrder
+			if order, ok := opts.ByteOrders[elem.Name+"."+prop.Name]; ok {
+				prop.order = order
+			}
 		}
-		slice = newSlice
-	}
-	slice = slice[0 : l+len(data)]
-	for i, c := range data {
-		slice[l+i] = c
+		start, end := loadedRange(elem, opts)
+		if e := skipBinaryRows(p, elem, 0, start); e != nil {
+			return e
+		}
+		rows := end - start
+		keep := opts.selected(elem)
+		var e error
+		if stride := elem.recordStride(); stride > 0 && keep == nil {
+			e = p.readRecords(elem, rows, stride, opts.Lazy)
+		} else {
+			e = p.readRows(elem, rows, opts.Input, keep)
+		}
+		if e != nil {
+			return offsetRows(e, start)
+		}
+		if e := skipBinaryRows(p, elem, end, elem.Size); e != nil {
+			return e
+		}
+		elem.Size = rows
 	}
-	return slice
-}
-
-func toBType(rd io.Reader, typeName string) (b []byte, e error) {
-	switch {
-	case typeName == Types[1] || typeName == OldTypes[1]:
-		b = make([]byte, 1)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[2] || typeName == OldTypes[2]:
-		b = make([]byte, 2)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[3] || typeName == OldTypes[3]:
-		b = make([]byte, 4)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[4] || typeName == OldTypes[4]:
-		b = make([]byte, 1)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[5] || typeName == OldTypes[5]:
-		b = make([]byte, 2)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[6] || typeName == OldTypes[6]:
-		b = make([]byte, 4)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[7] || typeName == OldTypes[7]:
-		b = make([]byte, 4)
-		rd.Read(b)
-		return b, nil
-	case typeName == Types[8] || typeName == OldTypes[8]:
-		b = make([]byte, 8)
-		rd.Read(b)
-		return b, nil
-	}
-	return nil, nil
+	return nil
 }
 
-func parseBinary(p *PLY) error {
+// readRows reads rows rows of elem one value at a time.
+func (p *PLY) readRows(elem *Element, rows int, policy *InputPolicy, keep []bool) error {
 	r := p.reader
-	for _, elem := range p.Elements {
-		for _, prop := range elem.Properties {
-			prop.print()
-			prop.Data = make([][]byte, elem.Size)
-		}
-		for i := 0; i < elem.Size; i++ {
-			for _, prop := range elem.Properties {
-				if prop.IsList {
-					var num uint32
-					e := binary.Read(r, p.byteOrder, &num)
-					if e != nil {
-						return e
-					}
-					numSize := int(num)
-					l := make([]byte, numSize*SizeOfType[prop.Type])
-					for j := 0; j < numSize; j++ {
-						b, e := toBType(r, prop.Type)
-						if e != nil {
-							return e
-						}
-						l = appendBytes(l, b)
-					}
-					prop.Data[i] = l
-				} else {
-					b, e := toBType(r, prop.Type)
-					if e != nil {
-						return e
-					}
-					prop.Data[i] = b
+	cols := newColumns(p, elem, rows, keep)
+	for i := 0; i < rows; i++ {
+		for k, prop := range elem.Properties {
+			var b []byte
+			var e error
+			if size := cols[k].size; size == 0 {
+				e = errors.New("Invalid type " + prop.Type)
+			} else if prop.IsList {
+				var numSize int
+				numSize, e = readListCount(r, prop.ListSizeType, prop.order)
+				if e == nil {
+					e = policy.checkListLength(p, prop, numSize)
 				}
+				if e == nil {
+					b, e = cols[k].readList(r, numSize, size)
+				}
+			} else {
+				b, e = cols[k].readScalar(r, size)
+			}
+			if e == io.EOF || e == io.ErrUnexpectedEOF {
+				return p.unexpectedEnd(elem, i, prop.Name)
+			}
+			if e != nil {
+				return e
+			}
+			if !cols[k].skip {
+				prop.Data = appendRow(prop.Data, b, rows)
 			}
 		}
 	}
 	return nil
 }
 
-func parseBinaryBigEndian(p *PLY) error {
+func parseBinaryBigEndian(p *PLY, opts *LoadOptions) error {
 	p.byteOrder = binary.BigEndian
-	return parseBinary(p)
+	return parseBinary(p, opts)
 }
 
-func parseBinaryLittleEndian(p *PLY) error {
+func parseBinaryLittleEndian(p *PLY, opts *LoadOptions) error {
 	p.byteOrder = binary.LittleEndian
-	return parseBinary(p)
+	return parseBinary(p, opts)
 }
 
-func parseASCII(p *PLY) error {
+func parseASCII(p *PLY, tokenize Tokenizer, opts *LoadOptions) error {
 	p.byteOrder = binary.LittleEndian
-	r := p.reader
-	numMatcher, e := regexp.Compile("[\\+\\-]*([0-9]*)+\\.*[0-9]+")
-	if e != nil {
-		return e
-	}
 	for _, elem := range p.Elements {
 		for _, prop := range elem.Properties {
-			prop.Data = make([][]byte, elem.Size)
+			prop.Data = newRows(elem.Size)
+			prop.order = p.byteOrder
 		}
-		for i := 0; i < elem.Size; i++ {
-			line, e := readLine(r)
-			if e != nil {
-				return e
+		start, end := loadedRange(elem, opts)
+		if e := skipASCIIRows(p, elem, 0, start); e != nil {
+			return e
+		}
+		if e := p.readASCIIRows(elem, end-start, tokenize, opts); e != nil {
+			return offsetRows(e, start)
+		}
+		if e := skipASCIIRows(p, elem, end, elem.Size); e != nil {
+			return e
+		}
+		elem.Size = end - start
+	}
+	return nil
+}
+
+// readASCIIRows reads rows rows of elem, a line each.
+func (p *PLY) readASCIIRows(elem *Element, rows int, tokenize Tokenizer, opts *LoadOptions) error {
+	r := p.reader
+	policy := opts.Input
+	cols := newColumns(p, elem, rows, opts.selected(elem))
+	for i := 0; i < rows; {
+		line, e := r.ReadString('\n')
+		// a last line without a newline may be a row cut off by the end
+		last := e == io.EOF
+		if last && len(line) > 0 {
+			e = nil
+		}
+		if e == io.EOF {
+			return p.unexpectedEnd(elem, i, "")
+		}
+		if e != nil {
+			return e
+		}
+		p.currentLine++
+		line = strings.TrimRight(line, "\r\n")
+		words, e := tokenize(strip(line))
+		if e != nil {
+			de := &DataError{Filename: p.filename, Line: p.currentLine,
+				Element: elem.Name, Row: i, Err: e}
+			if te, ok := e.(*TokenError); ok {
+				fields := strings.Fields(line)
+				if te.Index < len(fields) && fields[te.Index] == te.Token {
+					de.Column = tokenColumn(line, fields, te.Index)
+				}
+				de.Property = elem.propertyAt(fields, te.Index)
+				de.Token = te.Token
 			}
-			words := numMatcher.FindAllStringSubmatch(line, -1)
-			currWord := 0
-			if words == nil {
-				// skip empty lines
-			} else {
-				for _, prop := range elem.Properties {
-					if prop.IsList {
-						num, e := strconv.ParseInt(words[currWord][0], 10, 32)
-						if e != nil {
-							return e
-						}
-						numSize := int(num)
-						currWord++
-						l := make([]byte, numSize*SizeOfType[prop.Type])
-						for j := 0; j < numSize; j++ {
-							b, e := toType(words[currWord][0], prop.Type)
-							if e != nil {
-								return e
-							}
-							l = appendBytes(l, b)
-							currWord++
-						}
-						prop.Data[i] = l
-					} else {
-						b, e := toType(words[currWord][0], prop.Type)
-						if e != nil {
-							fmt.Println("")
-							prop.print()
-							return e
-						}
-						prop.Data[i] = b
+			return de
+		}
+		if len(words) == 0 {
+			// skip empty lines
+			continue
+		}
+		fail := func(prop *Property, k int, e error) error {
+			de := &DataError{Filename: p.filename, Line: p.currentLine,
+				Column: tokenColumn(line, words, k), Element: elem.Name,
+				Row: i, Property: prop.Name, Err: e}
+			if k < len(words) {
+				de.Token = words[k]
+			}
+			return de
+		}
+		missing := func(prop *Property, k int) error {
+			if last {
+				return p.unexpectedEnd(elem, i, prop.Name)
+			}
+			return fail(prop, k, errors.New("Missing values"))
+		}
+		currWord := 0
+		for k, prop := range elem.Properties {
+			if currWord >= len(words) {
+				return missing(prop, currWord)
+			}
+			if prop.IsList {
+				num, e := strconv.ParseInt(words[currWord], 10, 32)
+				if e != nil {
+					return fail(prop, currWord, e)
+				}
+				numSize := int(num)
+				if e = policy.checkListLength(p, prop, numSize); e != nil {
+					return e
+				}
+				currWord++
+				if currWord+numSize > len(words) {
+					return missing(prop, len(words))
+				}
+				if cols[k].skip {
+					currWord += numSize
+					continue
+				}
+				size := cols[k].size
+				l := cols[k].take(numSize * size)
+				for j := 0; j < numSize; j++ {
+					if e := putType(l[j*size:], words[currWord], prop.Type); e != nil {
+						return fail(prop, currWord, e)
 					}
+					currWord++
+				}
+				prop.Data = appendRow(prop.Data, l, rows)
+			} else if cols[k].skip {
+				currWord++
+			} else {
+				b := cols[k].take(cols[k].size)
+				if e := putType(b, words[currWord], prop.Type); e != nil {
+					return fail(prop, currWord, e)
 				}
+				prop.Data = appendRow(prop.Data, b, rows)
+				currWord++
 			}
 		}
+		i++
 	}
 	return nil
 }
@@ -1,6 +1,8 @@
 package ply
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"testing"
 )
@@ -31,3 +33,37 @@ func Test(t *testing.T) {
 		t.Error("error")
 	}
 }
+
+func TestByteOrderOverride(t *testing.T) {
+	// a little endian file whose face lists were written big endian
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, []float32{1.5, -2})
+	binary.Write(body, binary.BigEndian, uint32(3))
+	binary.Write(body, binary.BigEndian, []int32{0, 1, 258})
+	data := append([]byte(testBinaryHeader), body.Bytes()...)
+	if e := new(PLY).Read(bytes.NewReader(data)); e == nil {
+		t.Error("expected the mixed file to fail without an override")
+	}
+	p := new(PLY)
+	opts := &LoadOptions{ByteOrders: map[string]binary.ByteOrder{"face.vertex_indices": binary.BigEndian}}
+	if e := p.ReadWithOptions(bytes.NewReader(data), opts); e != nil {
+		t.Fatal(e)
+	}
+	if faces := p.ReadFaces(); len(faces) != 1 || faces[0][2] != 258 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&out); e != nil {
+		t.Fatal(e)
+	}
+	if faces := q.ReadFaces(); len(faces) != 1 || faces[0][2] != 258 {
+		t.Errorf("expected consistent byte order after writing, got %v", faces)
+	}
+	if q.findElement("vertex").findProperty("x").float64At(1) != -2 {
+		t.Error("unexpected vertex values")
+	}
+}
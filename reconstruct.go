@@ -0,0 +1,239 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Reconstructor triangulates an oriented point cloud, as done by
+// ReconstructSurface. The triangles index the points and wind
+// counterclockwise seen from the side the normals point to. Points with a
+// NaN or infinite coordinate or a zero normal must be left out.
+type Reconstructor interface {
+	Triangulate(points, normals [][3]float64) ([][3]int, error)
+}
+
+// GreedyTriangulation is a Reconstructor that projects the neighborhood of
+// every point onto its tangent plane and takes the Delaunay triangles
+// around the point there. The triangles proposed by the most of their
+// corners are then accepted greedily as long as they keep the surface
+// consistently oriented without folds. The points need to sample the
+// surface densely compared to Radius and its curvature.
+type GreedyTriangulation struct {
+	// Radius is the longest edge of a triangle; larger gaps are left open.
+	Radius float64
+	// MaxNeighbors bounds the neighbors considered around a point, 30 by
+	// default.
+	MaxNeighbors int
+	// MaxAngle is the largest angle in radians between the normals of two
+	// points of a triangle, π/4 by default, which keeps thin parts from
+	// being joined across.
+	MaxAngle float64
+}
+
+// ReconstructSurface triangulates the vertices of p with r, using their
+// positions and the nx, ny and nz properties, e.g. from EstimateNormals,
+// and replaces the face element with the triangles.
+func (p *PLY) ReconstructSurface(r Reconstructor) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	normals, e := p.Normals()
+	if e != nil {
+		return e
+	}
+	tris, e := r.Triangulate(pos, normals)
+	if e != nil {
+		return e
+	}
+	faces := make([][]int, len(tris))
+	for k, t := range tris {
+		if !validTriangle(t, pos) {
+			return errors.New("Triangle " + itoa(k) + " references a missing vertex")
+		}
+		faces[k] = []int{t[0], t[1], t[2]}
+	}
+	face := faceElement(faces)
+	for k, elem := range p.Elements {
+		if elem.Name == "face" {
+			p.Elements[k] = face
+			return nil
+		}
+	}
+	p.Elements = append(p.Elements, face)
+	return nil
+}
+
+// Triangulate implements Reconstructor.
+func (g GreedyTriangulation) Triangulate(points, normals [][3]float64) ([][3]int, error) {
+	if !(g.Radius > 0) {
+		return nil, errors.New("Greedy triangulation needs a positive radius")
+	}
+	if len(normals) != len(points) {
+		return nil, errors.New("Got " + itoa(len(normals)) + " normals for " + itoa(len(points)) + " points")
+	}
+	if g.MaxNeighbors == 0 {
+		g.MaxNeighbors = 30
+	}
+	if g.MaxAngle == 0 {
+		g.MaxAngle = math.Pi / 4
+	}
+	if g.MaxNeighbors < 0 || g.MaxAngle < 0 {
+		return nil, errors.New("Greedy triangulation options must not be negative")
+	}
+	unit := make([][3]float64, len(normals))
+	valid := make([]bool, len(points))
+	for i, n := range normals {
+		unit[i] = normalize3(n)
+		valid[i] = !isInvalidPoint(points[i]) && unit[i] != ([3]float64{})
+	}
+	tree := NewKDTree(points)
+	minCos := math.Cos(g.MaxAngle)
+	votes := make(map[[3]int]int)
+	for i := range points {
+		if !valid[i] {
+			continue
+		}
+		var nbrs []int
+		for _, j := range tree.neighborhood(points[i], g.MaxNeighbors+1, g.Radius) {
+			if j != i && valid[j] && points[j] != points[i] && dot3(unit[i], unit[j]) >= minCos {
+				nbrs = append(nbrs, j)
+			}
+		}
+		for _, t := range umbrella(i, nbrs, points, unit[i]) {
+			if length3(sub3(points[t[1]], points[t[2]])) <= g.Radius &&
+				dot3(unit[t[1]], unit[t[2]]) >= minCos {
+				votes[sortedTriangle(t)]++
+			}
+		}
+	}
+	candidates := make([][3]int, 0, len(votes))
+	longest := make(map[[3]int]float64, len(votes))
+	for t := range votes {
+		candidates = append(candidates, t)
+		longest[t] = math.Max(length3(sub3(points[t[0]], points[t[1]])),
+			math.Max(length3(sub3(points[t[1]], points[t[2]])), length3(sub3(points[t[2]], points[t[0]]))))
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		ta, tb := candidates[a], candidates[b]
+		if votes[ta] != votes[tb] {
+			return votes[ta] > votes[tb]
+		}
+		if longest[ta] != longest[tb] {
+			return longest[ta] < longest[tb]
+		}
+		for c := 0; c < 3; c++ {
+			if ta[c] != tb[c] {
+				return ta[c] < tb[c]
+			}
+		}
+		return false
+	})
+	// a consistently oriented surface without folds uses every directed
+	// edge at most once
+	used := make(map[[2]int]bool)
+	var tris [][3]int
+	for _, t := range candidates {
+		n := add3(unit[t[0]], add3(unit[t[1]], unit[t[2]]))
+		if dot3(cross3(sub3(points[t[1]], points[t[0]]), sub3(points[t[2]], points[t[0]])), n) < 0 {
+			t[1], t[2] = t[2], t[1]
+		}
+		edges := [3][2]int{{t[0], t[1]}, {t[1], t[2]}, {t[2], t[0]}}
+		if used[edges[0]] || used[edges[1]] || used[edges[2]] {
+			continue
+		}
+		for _, edge := range edges {
+			used[edge] = true
+		}
+		tris = append(tris, t)
+	}
+	return tris, nil
+}
+
+// umbrella returns the triangles around point i of the Delaunay
+// triangulation of i and its neighbors projected onto the plane through
+// point i with normal n, counterclockwise around n.
+func umbrella(i int, nbrs []int, points [][3]float64, n [3]float64) [][3]int {
+	if len(nbrs) < 2 {
+		return nil
+	}
+	// a basis of the tangent plane, u × v = n
+	u := cross3(n, [3]float64{1, 0, 0})
+	if length3(u) < 0.5 {
+		u = cross3(n, [3]float64{0, 1, 0})
+	}
+	u = normalize3(u)
+	v := cross3(n, u)
+	q := make([][2]float64, len(nbrs))
+	for k, j := range nbrs {
+		d := sub3(points[j], points[i])
+		q[k] = [2]float64{dot3(d, u), dot3(d, v)}
+	}
+	// next returns the neighbor forming the Delaunay triangle with i and
+	// neighbor a on the left of i→a, or on the right if ccw is false: the
+	// one seeing i and a under the widest angle, ties going to the first
+	// one counterclockwise around i
+	next := func(a int, ccw bool) int {
+		best, bestCos := -1, 0.0
+		for b := range q {
+			side := cross2(q[a], q[b])
+			if b == a || ccw && !(side > 0) || !ccw && !(side < 0) {
+				continue
+			}
+			ba := [2]float64{q[a][0] - q[b][0], q[a][1] - q[b][1]}
+			cos := -(q[b][0]*ba[0] + q[b][1]*ba[1]) / (math.Hypot(q[b][0], q[b][1]) * math.Hypot(ba[0], ba[1]))
+			const eps = 1e-9
+			if best < 0 || cos < bestCos-eps ||
+				cos <= bestCos+eps && (cross2(q[b], q[best]) > 0) == ccw {
+				best, bestCos = b, cos
+			}
+		}
+		return best
+	}
+	var tris [][3]int
+	// the nearest neighbor always shares a Delaunay edge with i
+	start := 0
+	for cur, steps := start, 0; steps < len(q); steps++ {
+		b := next(cur, true)
+		if b < 0 {
+			// open on the left, so close the umbrella from the right
+			for cur, steps := start, 0; steps < len(q); steps++ {
+				b := next(cur, false)
+				if b < 0 {
+					break
+				}
+				tris = append(tris, [3]int{i, nbrs[b], nbrs[cur]})
+				cur = b
+			}
+			break
+		}
+		tris = append(tris, [3]int{i, nbrs[cur], nbrs[b]})
+		if cur = b; cur == start {
+			break
+		}
+	}
+	return tris
+}
+
+func cross2(a, b [2]float64) float64 {
+	return a[0]*b[1] - a[1]*b[0]
+}
+
+// sortedTriangle returns the corners of t in ascending order.
+func sortedTriangle(t [3]int) [3]int {
+	if t[0] > t[1] {
+		t[0], t[1] = t[1], t[0]
+	}
+	if t[1] > t[2] {
+		t[1], t[2] = t[2], t[1]
+	}
+	if t[0] > t[1] {
+		t[0], t[1] = t[1], t[0]
+	}
+	return t
+}
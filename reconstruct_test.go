@@ -0,0 +1,104 @@
+package ply
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReconstructSurfacePlane(t *testing.T) {
+	const n = 10
+	var pos, normals [][3]float64
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			pos = append(pos, [3]float64{float64(x), float64(y), 0})
+			normals = append(normals, [3]float64{0, 0, 1})
+		}
+	}
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ReconstructSurface(GreedyTriangulation{Radius: 1.5}); e == nil {
+		t.Error("expected an error without normals")
+	}
+	if e := p.SetNormals(normals); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ReconstructSurface(GreedyTriangulation{}); e == nil {
+		t.Error("expected an error without a radius")
+	}
+	if e := p.ReconstructSurface(GreedyTriangulation{Radius: 1.5}); e != nil {
+		t.Fatal(e)
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(faces) != 2*(n-1)*(n-1) {
+		t.Errorf("expected %d triangles, got %d", 2*(n-1)*(n-1), len(faces))
+	}
+	area := 0.0
+	for _, f := range faces {
+		a, b, c := pos[f[0]], pos[f[1]], pos[f[2]]
+		area += cross3(sub3(b, a), sub3(c, a))[2] / 2
+		if z := cross3(sub3(b, a), sub3(c, a))[2]; z <= 0 {
+			t.Errorf("expected counterclockwise triangle, got %v", f)
+		}
+	}
+	if area != (n-1)*(n-1) {
+		t.Errorf("expected the triangles to cover the grid, got area %v", area)
+	}
+	// reconstructing again replaces the faces
+	if e := p.ReconstructSurface(GreedyTriangulation{Radius: 1.5}); e != nil {
+		t.Fatal(e)
+	}
+	if len(p.Elements) != 2 || p.findElement("face").Size != len(faces) {
+		t.Errorf("expected the face element to be replaced, got %d elements", len(p.Elements))
+	}
+}
+
+func TestReconstructSurfaceSphere(t *testing.T) {
+	// a Fibonacci sphere with outward normals
+	const n = 600
+	pos := make([][3]float64, n)
+	for i := range pos {
+		z := 1 - (2*float64(i)+1)/n
+		r := math.Sqrt(1 - z*z)
+		phi := float64(i) * math.Pi * (3 - math.Sqrt(5))
+		pos[i] = [3]float64{r * math.Cos(phi), r * math.Sin(phi), z}
+	}
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.SetNormals(pos); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ReconstructSurface(GreedyTriangulation{Radius: 0.3}); e != nil {
+		t.Fatal(e)
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		t.Fatal(e)
+	}
+	edges := make(map[[2]int]bool)
+	for _, f := range faces {
+		a, b, c := pos[f[0]], pos[f[1]], pos[f[2]]
+		if dot3(cross3(sub3(b, a), sub3(c, a)), add3(a, add3(b, c))) <= 0 {
+			t.Errorf("expected outward triangle, got %v", f)
+		}
+		for k := range f {
+			edges[[2]int{f[k], f[(k+1)%3]}] = true
+		}
+	}
+	open := 0
+	for edge := range edges {
+		if !edges[[2]int{edge[1], edge[0]}] {
+			open++
+		}
+	}
+	// a closed surface of genus 0 has V - E + F = 2
+	if open != 0 || n-len(edges)/2+len(faces) != 2 {
+		t.Errorf("expected a closed sphere, got %d faces, %d open edges", len(faces), open)
+	}
+}
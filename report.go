@@ -0,0 +1,185 @@
+package ply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+type Report struct {
+	Format           string           `json:"format"`
+	Elements         []ElementSummary `json:"elements"`
+	Vertices         int              `json:"vertices"`
+	Faces            int              `json:"faces"`
+	Bounds           *Bounds          `json:"bounds,omitempty"`
+	Properties       []PropertyReport `json:"properties"`
+	DuplicatePoints  int              `json:"duplicate_points"`
+	DegenerateFaces  int              `json:"degenerate_faces"`
+	BoundaryEdges    int              `json:"boundary_edges"`
+	NonManifoldEdges int              `json:"non_manifold_edges"`
+	Manifold         bool             `json:"manifold"`
+}
+
+type ElementSummary struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+type Bounds struct {
+	Min [3]float64 `json:"min"`
+	Max [3]float64 `json:"max"`
+}
+
+// PropertyReport holds statistics of a scalar property. NaN and Inf values
+// are counted but excluded from Min, Max and Mean.
+type PropertyReport struct {
+	Element string  `json:"element"`
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Mean    float64 `json:"mean"`
+	NaN     int     `json:"nan"`
+	Inf     int     `json:"inf"`
+}
+
+// Report summarizes the contents and mesh quality of p.
+func (p *PLY) Report() *Report {
+	r := &Report{}
+	r.Format, _ = formatName(p.FileType)
+	for _, elem := range p.Elements {
+		r.Elements = append(r.Elements, ElementSummary{elem.Name, elem.Size})
+		for _, prop := range elem.Properties {
+			if !prop.IsList {
+				r.Properties = append(r.Properties, propertyReport(elem, prop))
+			}
+		}
+	}
+	r.Vertices = p.VerticesCount()
+	pos, e := p.vertexPositions()
+	if e == nil {
+		r.Bounds = positionBounds(pos)
+		seen := make(map[[3]float64]bool, len(pos))
+		for _, v := range pos {
+			if seen[v] {
+				r.DuplicatePoints++
+			}
+			seen[v] = true
+		}
+	}
+	faces, e := p.faceIndices()
+	if e == nil {
+		r.Faces = len(faces)
+		for _, f := range faces {
+			if isDegenerateFace(f, pos) {
+				r.DegenerateFaces++
+			}
+		}
+		_, edges := faceEdges(faces)
+		for _, n := range edges {
+			if n == 1 {
+				r.BoundaryEdges++
+			} else if n > 2 {
+				r.NonManifoldEdges++
+			}
+		}
+	}
+	r.Manifold = r.NonManifoldEdges == 0
+	return r
+}
+
+func propertyReport(elem *Element, prop *Property) PropertyReport {
+	pr := PropertyReport{Element: elem.Name, Name: prop.Name, Type: prop.Type}
+	n := 0
+	for i := 0; i < elem.Size; i++ {
+		v := prop.float64At(i)
+		switch {
+		case math.IsNaN(v):
+			pr.NaN++
+		case math.IsInf(v, 0):
+			pr.Inf++
+		default:
+			if n == 0 || v < pr.Min {
+				pr.Min = v
+			}
+			if n == 0 || v > pr.Max {
+				pr.Max = v
+			}
+			pr.Mean += v
+			n++
+		}
+	}
+	if n > 0 {
+		pr.Mean /= float64(n)
+	}
+	return pr
+}
+
+func positionBounds(pos [][3]float64) *Bounds {
+	var b *Bounds
+	for _, v := range pos {
+		if isInvalidPoint(v) {
+			continue
+		}
+		if b == nil {
+			b = &Bounds{v, v}
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			b.Min[j] = math.Min(b.Min[j], v[j])
+			b.Max[j] = math.Max(b.Max[j], v[j])
+		}
+	}
+	return b
+}
+
+func isInvalidPoint(v [3]float64) bool {
+	for _, c := range v {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// a face is degenerate when it has fewer than three distinct, valid
+// vertices or zero area
+func isDegenerateFace(f []int, pos [][3]float64) bool {
+	if len(f) < 3 {
+		return true
+	}
+	seen := make(map[int]bool, len(f))
+	for _, idx := range f {
+		if idx < 0 || idx >= len(pos) || seen[idx] {
+			return true
+		}
+		seen[idx] = true
+	}
+	n := newellNormal(f, pos)
+	return n[0]*n[0]+n[1]*n[1]+n[2]*n[2] == 0
+}
+
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "format: %s\n", r.Format)
+	for _, e := range r.Elements {
+		fmt.Fprintf(w, "element %s: %d rows\n", e.Name, e.Rows)
+	}
+	if r.Bounds != nil {
+		fmt.Fprintf(w, "bounds: min %v max %v\n", r.Bounds.Min, r.Bounds.Max)
+	}
+	for _, pr := range r.Properties {
+		fmt.Fprintf(w, "%s.%s (%s): min %g max %g mean %g nan %d inf %d\n",
+			pr.Element, pr.Name, pr.Type, pr.Min, pr.Max, pr.Mean, pr.NaN, pr.Inf)
+	}
+	fmt.Fprintf(w, "duplicate points: %d\n", r.DuplicatePoints)
+	fmt.Fprintf(w, "degenerate faces: %d\n", r.DegenerateFaces)
+	fmt.Fprintf(w, "boundary edges: %d\n", r.BoundaryEdges)
+	fmt.Fprintf(w, "non-manifold edges: %d\n", r.NonManifoldEdges)
+	_, e := fmt.Fprintf(w, "manifold: %v\n", r.Manifold)
+	return e
+}
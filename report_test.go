@@ -0,0 +1,56 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 5
+property float x
+property float y
+property float z
+element face 4
+property list uchar int vertex_indices
+end_header
+0 0 0
+1 0 0
+1 1 0
+0 1 0
+1 1 0
+3 0 1 2
+3 0 2 3
+3 0 1 4
+3 1 0 3
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	r := p.Report()
+	if r.Vertices != 5 || r.Faces != 4 {
+		t.Errorf("unexpected counts %d %d", r.Vertices, r.Faces)
+	}
+	if r.Bounds == nil || r.Bounds.Max != [3]float64{1, 1, 0} {
+		t.Errorf("unexpected bounds %v", r.Bounds)
+	}
+	if r.DuplicatePoints != 1 || r.DegenerateFaces != 0 || r.NonManifoldEdges != 1 || r.Manifold {
+		t.Errorf("unexpected quality %+v", r)
+	}
+	b, e := r.JSON()
+	if e != nil {
+		t.Fatal(e)
+	}
+	var back Report
+	if e := json.Unmarshal(b, &back); e != nil || back.Faces != 4 {
+		t.Errorf("json round trip failed: %v", e)
+	}
+	var buf bytes.Buffer
+	if e := r.WriteText(&buf); e != nil || !strings.Contains(buf.String(), "manifold: false") {
+		t.Errorf("unexpected text report:\n%s", buf.String())
+	}
+}
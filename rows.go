@@ -0,0 +1,112 @@
+package ply
+
+import (
+	"errors"
+)
+
+// RowRange selects Count rows of an element starting at Offset.
+type RowRange struct {
+	Offset int
+	Count  int
+}
+
+func (r RowRange) clamp(size int) (int, int, error) {
+	if r.Offset < 0 || r.Count < 0 {
+		return 0, 0, errors.New("Invalid row range")
+	}
+	start, end := r.Offset, r.Offset+r.Count
+	if start > size {
+		start = size
+	}
+	if end > size {
+		end = size
+	}
+	return start, end, nil
+}
+
+// SaveRows saves only the selected rows of the given elements. When the
+// vertex element is restricted, only faces whose vertices all lie within
+// the range are written and their indices are shifted accordingly.
+func (p *PLY) SaveRows(filename string, rows map[string]RowRange) error {
+	return p.SaveWithOptions(filename, &SaveOptions{Rows: rows})
+}
+
+// rowSubset returns a shallow copy of p restricted to rows. Property data
+// is shared except for re-indexed faces.
+func (p *PLY) rowSubset(rows map[string]RowRange) (*PLY, error) {
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	vStart, vEnd := 0, p.VerticesCount()
+	for k, elem := range p.Elements {
+		r, ok := rows[elem.Name]
+		if !ok {
+			q.Elements[k] = elem
+			continue
+		}
+		start, end, e := r.clamp(elem.Size)
+		if e != nil {
+			return nil, errors.New(e.Error() + " for element " + elem.Name)
+		}
+		if elem.Name == "vertex" {
+			vStart, vEnd = start, end
+		}
+		sub := &Element{Name: elem.Name, Size: end - start}
+		for _, prop := range elem.Properties {
+			prop.load()
+			if len(prop.Data) < end {
+				return nil, errors.New("Missing data for property " + prop.Name)
+			}
+			sp := *prop
+			sp.Data = prop.Data[start:end]
+			sub.Properties = append(sub.Properties, &sp)
+		}
+		q.Elements[k] = sub
+	}
+	if _, ok := rows["vertex"]; !ok {
+		return q, nil
+	}
+	for k, elem := range q.Elements {
+		if elem.Name != "face" {
+			continue
+		}
+		idx := p.faceIndexProperty(elem)
+		if idx == nil {
+			continue
+		}
+		var keep []int
+		remapped := make(map[int][]byte)
+		for i := 0; i < elem.Size; i++ {
+			values := idx.listFloat64At(i)
+			inside := true
+			for j, v := range values {
+				if v < float64(vStart) || v >= float64(vEnd) {
+					inside = false
+					break
+				}
+				values[j] = v - float64(vStart)
+			}
+			if inside {
+				keep = append(keep, i)
+				if vStart != 0 {
+					remapped[i] = idx.encodeList(values)
+				}
+			}
+		}
+		sub := &Element{Name: elem.Name, Size: len(keep)}
+		for _, prop := range elem.Properties {
+			prop.load()
+			sp := *prop
+			sp.Data = make([][]byte, len(keep))
+			for n, i := range keep {
+				if prop == idx && remapped[i] != nil {
+					sp.Data[n] = remapped[i]
+				} else {
+					sp.Data[n] = prop.Data[i]
+				}
+			}
+			sub.Properties = append(sub.Properties, &sp)
+		}
+		q.Elements[k] = sub
+	}
+	return q, nil
+}
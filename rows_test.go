@@ -0,0 +1,33 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteRows(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	p.Elements[1].Properties[0].setListIntsAt(1, []int{1, 2, 3})
+	var buf bytes.Buffer
+	opts := &SaveOptions{Rows: map[string]RowRange{"vertex": {Offset: 1, Count: 3}}}
+	if e := p.WriteWithOptions(&buf, opts); e != nil {
+		t.Fatal(e)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "element vertex 3\n") || !strings.Contains(out, "element face 1\n") ||
+		!strings.HasSuffix(out, "end_header\n1 0 0 0.25\n1 1 0 -1e-05\n0 1 0 3\n3 0 1 2 300\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	// the source must be left untouched
+	if p.VerticesCount() != 4 || p.Elements[1].Properties[0].listIntsAt(1)[0] != 1 {
+		t.Error("source modified")
+	}
+	opts.Rows["vertex"] = RowRange{Offset: -1, Count: 1}
+	if e := p.WriteWithOptions(&buf, opts); e == nil {
+		t.Error("expected error for negative offset")
+	}
+}
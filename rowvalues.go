@@ -0,0 +1,92 @@
+package ply
+
+import "errors"
+
+// Row decodes every property of row i into Go values: int64, uint64 or
+// float64 by type for scalars and []int64, []uint64 or []float64 for
+// lists.
+func (e *Element) Row(i int) (map[string]interface{}, error) {
+	if i < 0 || i >= e.Size {
+		return nil, errors.New("Row " + itoa(i) + " outside element " + e.Name)
+	}
+	row := make(map[string]interface{}, len(e.Properties))
+	for _, prop := range e.Properties {
+		size := SizeOfType[prop.Type]
+		b := prop.row(i)
+		if size == 0 || len(b)%size != 0 || !prop.IsList && len(b) != size {
+			return nil, errors.New("Malformed row " + itoa(i) + " of property " + e.Name + "." + prop.Name)
+		}
+		values := prop.listFloat64At(i)
+		switch {
+		case isFloat(prop.Type) && prop.IsList:
+			row[prop.Name] = values
+		case isFloat(prop.Type):
+			row[prop.Name] = values[0]
+		case isSigned(prop.Type):
+			ints := make([]int64, len(values))
+			for k, v := range values {
+				ints[k] = int64(v)
+			}
+			if prop.IsList {
+				row[prop.Name] = ints
+			} else {
+				row[prop.Name] = ints[0]
+			}
+		default:
+			uints := make([]uint64, len(values))
+			for k, v := range values {
+				uints[k] = uint64(v)
+			}
+			if prop.IsList {
+				row[prop.Name] = uints
+			} else {
+				row[prop.Name] = uints[0]
+			}
+		}
+	}
+	return row, nil
+}
+
+// RowIterator walks the rows of an element, see Element.Rows.
+type RowIterator struct {
+	elem *Element
+	i    int
+	row  map[string]interface{}
+	err  error
+}
+
+// Rows returns an iterator decoding each row like Row:
+//
+//	it := elem.Rows()
+//	for it.Next() {
+//		use(it.Row())
+//	}
+//	if e := it.Err(); e != nil { ... }
+func (e *Element) Rows() *RowIterator {
+	return &RowIterator{elem: e, i: -1}
+}
+
+// Next decodes the next row, returning false at the end or on an error.
+func (it *RowIterator) Next() bool {
+	if it.err != nil || it.i+1 >= it.elem.Size {
+		return false
+	}
+	it.i++
+	it.row, it.err = it.elem.Row(it.i)
+	return it.err == nil
+}
+
+// Row returns the current row's values.
+func (it *RowIterator) Row() map[string]interface{} {
+	return it.row
+}
+
+// Index returns the current row's index.
+func (it *RowIterator) Index() int {
+	return it.i
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RowIterator) Err() error {
+	return it.err
+}
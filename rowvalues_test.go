@@ -0,0 +1,49 @@
+package ply
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRowValues(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	face := p.findElement("face")
+	row, e := face.Row(1)
+	if e != nil {
+		t.Fatal(e)
+	}
+	want := map[string]interface{}{"vertex_indices": []int64{0, 1, 2, 3}, "flags": int64(300)}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("unexpected row %v", row)
+	}
+	if _, e := face.Row(2); e == nil {
+		t.Error("expected an error for a row out of range")
+	}
+	it := p.findElement("vertex").Rows()
+	var quality []float64
+	for it.Next() {
+		quality = append(quality, it.Row()["quality"].(float64))
+		if x := it.Row()["x"].(float64); it.Index() == 1 && x != 1 {
+			t.Errorf("unexpected x %v", x)
+		}
+	}
+	if it.Err() != nil || len(quality) != 4 || quality[3] != 3 {
+		t.Errorf("unexpected iteration %v, %v", quality, it.Err())
+	}
+	face.findProperty("flags").Data[1] = []byte{1}
+	it = face.Rows()
+	for it.Next() {
+	}
+	if it.Err() == nil || it.Index() != 1 {
+		t.Error("expected the malformed row to stop the iteration")
+	}
+	u := &Element{Name: "u", Size: 1, Properties: []*Property{newListProperty("l", "uchar", "uint16", 1)}}
+	u.Properties[0].setListIntsAt(0, []int{65535})
+	if row, _ := u.Row(0); !reflect.DeepEqual(row["l"], []uint64{65535}) {
+		t.Errorf("unexpected unsigned list %v", row)
+	}
+}
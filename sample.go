@@ -0,0 +1,104 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// sampleSeed makes surface samples reproducible across runs.
+const sampleSeed = 1
+
+// SampleSurface returns a copy of p holding only a vertex element of n
+// points drawn uniformly over the area of the faces, e.g. to compare a mesh
+// with a scan. Polygons are fan-triangulated. Every scalar vertex property
+// is interpolated from the corners of the sampled triangle, so normals,
+// colors and texture coordinates carry over; normals are renormalized.
+func (p *PLY) SampleSurface(n int) (*PLY, error) {
+	if n < 0 {
+		return nil, errors.New("Sample count must not be negative")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return nil, e
+	}
+	var tris [][3]int
+	var cumulative []float64
+	total := 0.0
+	for _, f := range faces {
+		for _, t := range fanTriangles(f) {
+			if !validTriangle(t, pos) {
+				continue
+			}
+			area := length3(cross3(sub3(pos[t[1]], pos[t[0]]), sub3(pos[t[2]], pos[t[0]]))) / 2
+			if !(area > 0) || math.IsInf(area, 0) {
+				continue
+			}
+			total += area
+			tris = append(tris, t)
+			cumulative = append(cumulative, total)
+		}
+	}
+	if n > 0 && len(tris) == 0 {
+		return nil, errors.New("No faces with an area to sample")
+	}
+	vertex := p.findElement("vertex")
+	sampled := &Element{Name: "vertex", Size: n}
+	var from []*Property
+	for _, prop := range vertex.Properties {
+		if prop.IsList {
+			continue
+		}
+		sp := newProperty(prop.Name, prop.Type, n)
+		sp.pos = len(sampled.Properties)
+		sampled.Properties = append(sampled.Properties, sp)
+		from = append(from, prop)
+	}
+	r := rand.New(rand.NewSource(sampleSeed))
+	for i := 0; i < n; i++ {
+		k := sort.SearchFloat64s(cumulative, r.Float64()*total)
+		if k == len(tris) {
+			k--
+		}
+		t := tris[k]
+		// uniform barycentric coordinates
+		s, r2 := math.Sqrt(r.Float64()), r.Float64()
+		w := [3]float64{1 - s, s * (1 - r2), s * r2}
+		for j, prop := range from {
+			v := 0.0
+			for c := 0; c < 3; c++ {
+				v += w[c] * prop.float64At(t[c])
+			}
+			sampled.Properties[j].setFloat64At(i, v)
+		}
+	}
+	if normals := sampled.scalarProperties("nx", "ny", "nz"); normals != nil {
+		for i := 0; i < n; i++ {
+			var v [3]float64
+			for j := range v {
+				v[j] = normals[j].float64At(i)
+			}
+			v = normalize3(v)
+			for j := range v {
+				normals[j].setFloat64At(i, v[j])
+			}
+		}
+	}
+	q := p.shallowCopy()
+	q.Elements = []*Element{sampled}
+	return q, nil
+}
+
+func validTriangle(t [3]int, pos [][3]float64) bool {
+	for _, i := range t {
+		if i < 0 || i >= len(pos) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSampleSurface(t *testing.T) {
+	// a 2 by 1 rectangle and a triangle of area 0.5, with u = x/2 and
+	// red = 100x
+	src := `ply
+format ascii 1.0
+element vertex 7
+property float x
+property float y
+property float z
+property float u
+property uchar red
+element face 3
+property list uchar int vertex_indices
+end_header
+0 0 0 0 0
+2 0 0 1 200
+2 1 0 1 200
+0 1 0 0 0
+10 0 0 5 0
+11 0 0 5.5 0
+10 1 0 5 0
+4 0 1 2 3
+3 4 5 6
+3 4 4 6
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	q, e := p.SampleSurface(5000)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(q.Elements) != 1 || q.VerticesCount() != 5000 {
+		t.Fatalf("expected a vertex-only PLY of 5000 points, got %d elements", len(q.Elements))
+	}
+	pos, _ := q.Positions()
+	v := q.GetVertices()
+	u, red := v.findProperty("u"), v.findProperty("red")
+	inRectangle := 0
+	for i, x := range pos {
+		if x[0] <= 2 {
+			inRectangle++
+			if x[1] < 0 || x[1] > 1 || math.Abs(red.float64At(i)-100*x[0]) > 0.5 {
+				t.Fatalf("unexpected sample %v with red %v", x, red.float64At(i))
+			}
+		} else if x[0] < 10 || x[0]+x[1] > 11+1e-6 {
+			t.Fatalf("sample %v outside the faces", x)
+		}
+		if math.Abs(u.float64At(i)-x[0]/2) > 1e-5 {
+			t.Fatalf("unexpected u %v at %v", u.float64At(i), x)
+		}
+	}
+	if f := float64(inRectangle) / 5000; math.Abs(f-0.8) > 0.03 {
+		t.Errorf("expected 80%% of the samples on the rectangle, got %v", f)
+	}
+	if _, e := q.SampleSurface(10); e == nil {
+		t.Error("expected an error without faces")
+	}
+}
+
+func TestSampleSurfaceNormals(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ComputeNormals(AreaWeighted); e != nil {
+		t.Fatal(e)
+	}
+	q, e := p.SampleSurface(100)
+	if e != nil {
+		t.Fatal(e)
+	}
+	n, e := q.Normals()
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, v := range n {
+		if math.Abs(length3(v)-1) > 1e-6 {
+			t.Fatalf("expected unit normals, got %v", v)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+const (
+	// ClampOutOfRange replaces values outside a type's range with the
+	// nearest representable value.
+	ClampOutOfRange = iota
+	// ErrorOutOfRange fails on values outside a type's range.
+	ErrorOutOfRange
+)
+
+// ValuePolicy controls how values are made to fit their property types
+// when writing.
+type ValuePolicy struct {
+	// OutOfRange is ClampOutOfRange or ErrorOutOfRange, for the values
+	// converted to Types and for the sentinel.
+	OutOfRange int
+	// Types writes the properties of the given names, in any element, as
+	// another type, e.g. "red" as uchar, rounding values as ConvertProperty.
+	Types map[string]string
+	// Sentinel, when set, replaces NaN and ±Inf in float properties.
+	Sentinel *float64
+}
+
+var typeRanges = map[string][2]float64{
+	"int8":    {math.MinInt8, math.MaxInt8},
+	"int16":   {math.MinInt16, math.MaxInt16},
+	"int32":   {math.MinInt32, math.MaxInt32},
+	"uint8":   {0, math.MaxUint8},
+	"uint16":  {0, math.MaxUint16},
+	"uint32":  {0, math.MaxUint32},
+	"float32": {-math.MaxFloat32, math.MaxFloat32},
+	"float64": {-math.MaxFloat64, math.MaxFloat64},
+}
+
+// fitValue rounds v for integral types and clamps it to the range of
+// typeName, reporting whether v was representable. NaN becomes 0 for
+// integral types; NaN and ±Inf are representable as floats.
+func fitValue(v float64, typeName string) (float64, bool) {
+	t := normalizeType(typeName)
+	r, ok := typeRanges[t]
+	if !ok {
+		return v, false
+	}
+	if t == "float32" || t == "float64" {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return v, true
+		}
+	} else {
+		if math.IsNaN(v) {
+			return 0, false
+		}
+		v = math.Round(v)
+	}
+	if v < r[0] {
+		return r[0], false
+	}
+	if v > r[1] {
+		return r[1], false
+	}
+	return v, true
+}
+
+// sanitize returns a shallow copy of p whose properties are converted to
+// the policy types and whose float properties have NaN and ±Inf replaced
+// by the policy sentinel. Only modified properties are copied.
+func (p *PLY) sanitize(policy *ValuePolicy) (*PLY, error) {
+	if len(policy.Types) > 0 {
+		q, e := p.convertTypes(policy)
+		if e != nil {
+			return nil, e
+		}
+		p = q
+	}
+	if policy.Sentinel == nil {
+		return p, nil
+	}
+	sentinel := *policy.Sentinel
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	for k, elem := range p.Elements {
+		q.Elements[k] = elem
+		var props []*Property
+		for j, prop := range elem.Properties {
+			if !isFloat(prop.Type) {
+				continue
+			}
+			v, ok := fitValue(sentinel, prop.Type)
+			if !ok && policy.OutOfRange == ErrorOutOfRange {
+				return nil, errors.New("Sentinel " + strconv.FormatFloat(sentinel, 'g', -1, 64) +
+					" does not fit " + prop.Type + " property " + prop.Name)
+			}
+			replacement := encodeFloat64(v, prop.Type, prop.byteOrder())
+			var data [][]byte
+			prop.load()
+			for i, row := range prop.Data {
+				fixed := sanitizeRow(row, prop, replacement)
+				if fixed == nil {
+					continue
+				}
+				if data == nil {
+					data = append([][]byte(nil), prop.Data...)
+				}
+				data[i] = fixed
+			}
+			if data == nil {
+				continue
+			}
+			if props == nil {
+				props = append([]*Property(nil), elem.Properties...)
+			}
+			sp := *prop
+			sp.Data = data
+			props[j] = &sp
+		}
+		if props != nil {
+			se := *elem
+			se.Properties = props
+			q.Elements[k] = &se
+		}
+	}
+	return q, nil
+}
+
+// convertTypes returns a shallow copy of p with the properties named in
+// policy.Types converted, failing on values that do not fit with
+// ErrorOutOfRange.
+func (p *PLY) convertTypes(policy *ValuePolicy) (*PLY, error) {
+	q := p.shallowCopy()
+	q.Elements = make([]*Element, len(p.Elements))
+	for k, elem := range p.Elements {
+		q.Elements[k] = elem
+		var se *Element
+		for j, prop := range elem.Properties {
+			t, ok := policy.Types[prop.Name]
+			if !ok || t == prop.Type {
+				continue
+			}
+			if se == nil {
+				c := *elem
+				c.Properties = append([]*Property(nil), elem.Properties...)
+				// the copy is private, so a frozen p can be written too
+				c.frozen = false
+				se = &c
+				q.Elements[k] = se
+			}
+			sp := *prop
+			sp.load()
+			se.Properties[j] = &sp
+			stats, e := se.ConvertProperty(prop.Name, t)
+			if e != nil {
+				return nil, e
+			}
+			if stats.Clamped > 0 && policy.OutOfRange == ErrorOutOfRange {
+				return nil, errors.New(itoa(stats.Clamped) + " values of property " + elem.Name + "." +
+					prop.Name + " do not fit " + t)
+			}
+		}
+	}
+	return q, nil
+}
+
+// fitRow returns values, keyed by property name as AppendRow takes them,
+// with NaN and ±Inf of float properties replaced by the sentinel, failing
+// on values outside their property's range with ErrorOutOfRange. Values
+// AppendRow rejects are passed on for it to report.
+func (policy *ValuePolicy) fitRow(elem *Element, values map[string]interface{}) (map[string]interface{}, error) {
+	fitted := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		fitted[name] = v
+		prop := elem.findProperty(name)
+		f, ok := toFloat64s(v)
+		if prop == nil || !ok {
+			continue
+		}
+		f = append([]float64(nil), f...)
+		for j, x := range f {
+			if policy.Sentinel != nil && isFloat(prop.Type) && (math.IsNaN(x) || math.IsInf(x, 0)) {
+				f[j] = *policy.Sentinel
+			}
+			if _, ok := fitValue(f[j], prop.Type); !ok && policy.OutOfRange == ErrorOutOfRange {
+				return nil, errors.New("Value " + strconv.FormatFloat(f[j], 'g', -1, 64) + " does not fit " +
+					prop.Type + " property " + name)
+			}
+		}
+		fitted[name] = f
+	}
+	return fitted, nil
+}
+
+// sanitizeRow returns a copy of row with invalid values replaced, or nil
+// when row is clean.
+func sanitizeRow(row []byte, prop *Property, replacement []byte) []byte {
+	size := SizeOfType[prop.Type]
+	var fixed []byte
+	for off := 0; off+size <= len(row); off += size {
+		v := scalarFloat64(row[off:off+size], prop.Type, prop.byteOrder())
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			continue
+		}
+		if fixed == nil {
+			fixed = append([]byte(nil), row...)
+		}
+		copy(fixed[off:], replacement)
+	}
+	return fixed
+}
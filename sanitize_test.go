@@ -0,0 +1,111 @@
+package ply
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFitValue(t *testing.T) {
+	cases := []struct {
+		v        float64
+		typeName string
+		want     float64
+		ok       bool
+	}{
+		{300, "uchar", 255, false},
+		{-1, "uint8", 0, false},
+		{254.6, "uchar", 255, true},
+		{-200, "char", -128, false},
+		{1e40, "float", math.MaxFloat32, false},
+		{math.NaN(), "int", 0, false},
+		{math.Inf(1), "double", math.Inf(1), true},
+	}
+	for _, c := range cases {
+		got, ok := fitValue(c.v, c.typeName)
+		if got != c.want || ok != c.ok {
+			t.Errorf("fitValue(%v, %s) = %v, %v", c.v, c.typeName, got, ok)
+		}
+	}
+	p := newProperty("red", "uchar", 1)
+	p.setFloat64At(0, 300)
+	if p.Data[0][0] != 255 {
+		t.Errorf("expected clamped color, got %d", p.Data[0][0])
+	}
+}
+
+func TestSanitizeOnWrite(t *testing.T) {
+	src := strings.Replace(testASCIIVertices, "1 0.5 -2 20", "nan 0.5 -inf 20", 1)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	sentinel := -9999.0
+	var buf bytes.Buffer
+	if e := p.WriteWithOptions(&buf, &SaveOptions{Values: &ValuePolicy{Sentinel: &sentinel}}); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.Contains(buf.String(), "\n-9999 0.5 -9999 20\n") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if !math.IsNaN(p.GetVertices().Properties[0].float64At(1)) {
+		t.Error("source modified")
+	}
+	huge := 1e300
+	if e := p.WriteWithOptions(&buf, &SaveOptions{Values: &ValuePolicy{Sentinel: &huge, OutOfRange: ErrorOutOfRange}}); e == nil {
+		t.Error("expected sentinel range error")
+	}
+}
+
+func TestConvertTypesOnWrite(t *testing.T) {
+	src := strings.NewReplacer("uchar red", "int red", " 30\n", " 300\n").Replace(testASCIIVertices)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	types := map[string]string{"red": "uchar"}
+	var buf bytes.Buffer
+	if e := p.WriteWithOptions(&buf, &SaveOptions{Values: &ValuePolicy{Types: types}}); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.Contains(buf.String(), "property uchar red\n") || !strings.Contains(buf.String(), " 255\n") {
+		t.Errorf("expected red to be clamped to uchar:\n%s", buf.String())
+	}
+	if red := p.GetVertices().findProperty("red"); red.Type != "int" || red.float64At(2) != 300 {
+		t.Error("source modified")
+	}
+	policy := &ValuePolicy{Types: types, OutOfRange: ErrorOutOfRange}
+	if e := p.WriteWithOptions(&buf, &SaveOptions{Values: policy}); e == nil {
+		t.Error("expected an error for red 300 as uchar")
+	}
+	if e := p.Freeze().WriteWithOptions(&buf, &SaveOptions{Values: &ValuePolicy{Types: types}}); e != nil {
+		t.Errorf("expected a frozen PLY to be written, got %v", e)
+	}
+}
+
+func TestFitRowOnEncode(t *testing.T) {
+	header := new(PLY)
+	if e := header.Read(strings.NewReader(testASCIIVertices)); e != nil {
+		t.Fatal(e)
+	}
+	header.GetVertices().Size = 1
+	sentinel := -1.0
+	var buf bytes.Buffer
+	enc, e := NewEncoder(&buf, header, &EncoderOptions{Values: &ValuePolicy{OutOfRange: ErrorOutOfRange, Sentinel: &sentinel}})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.EncodeRow(map[string]interface{}{"x": 1, "red": 300}); e == nil {
+		t.Error("expected an error for red 300")
+	}
+	if e := enc.EncodeRow(map[string]interface{}{"x": math.NaN(), "red": 255}); e != nil {
+		t.Fatal(e)
+	}
+	if e := enc.Close(); e != nil {
+		t.Fatal(e)
+	}
+	if !strings.HasSuffix(buf.String(), "\n-1 0 0 255\n") {
+		t.Errorf("expected NaN to be replaced:\n%s", buf.String())
+	}
+}
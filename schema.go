@@ -0,0 +1,116 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// PropertySchema describes a property; ListSizeType is only used for lists.
+type PropertySchema struct {
+	Name         string
+	Type         string
+	IsList       bool
+	ListSizeType string
+}
+
+type ElementSchema struct {
+	Name       string
+	Properties []PropertySchema
+}
+
+// Schema lists the elements and properties a file is expected to hold.
+type Schema struct {
+	Elements []ElementSchema
+}
+
+func scalars(typeName string, names ...string) []PropertySchema {
+	props := make([]PropertySchema, len(names))
+	for i, name := range names {
+		props[i] = PropertySchema{Name: name, Type: typeName}
+	}
+	return props
+}
+
+var faceSchema = ElementSchema{Name: "face", Properties: []PropertySchema{
+	{Name: "vertex_indices", Type: "int", IsList: true, ListSizeType: "uchar"},
+}}
+
+// Presets for common point cloud and mesh layouts.
+var (
+	PointXYZ = Schema{Elements: []ElementSchema{
+		{Name: "vertex", Properties: scalars("float", "x", "y", "z")},
+	}}
+	PointXYZRGB = Schema{Elements: []ElementSchema{
+		{Name: "vertex", Properties: append(scalars("float", "x", "y", "z"),
+			scalars("uchar", "red", "green", "blue")...)},
+	}}
+	PointXYZI = Schema{Elements: []ElementSchema{
+		{Name: "vertex", Properties: scalars("float", "x", "y", "z", "intensity")},
+	}}
+	MeshBasic = Schema{Elements: []ElementSchema{
+		{Name: "vertex", Properties: scalars("float", "x", "y", "z")},
+		faceSchema,
+	}}
+	MeshTextured = Schema{Elements: []ElementSchema{
+		{Name: "vertex", Properties: scalars("float", "x", "y", "z", "nx", "ny", "nz", "s", "t")},
+		faceSchema,
+	}}
+)
+
+// New returns a binary little endian PLY laid out by s, with each element
+// holding sizes[name] zeroed rows and lists left empty.
+func (s Schema) New(sizes map[string]int) *PLY {
+	p := &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian}
+	for _, es := range s.Elements {
+		n := sizes[es.Name]
+		elem := &Element{Name: es.Name, Size: n}
+		for _, ps := range es.Properties {
+			var prop *Property
+			if ps.IsList {
+				prop = newListProperty(ps.Name, ps.ListSizeType, ps.Type, n)
+				for i := range prop.Data {
+					prop.Data[i] = []byte{}
+				}
+			} else {
+				prop = newProperty(ps.Name, ps.Type, n)
+				for i := range prop.Data {
+					prop.Data[i] = make([]byte, SizeOfType[ps.Type])
+				}
+			}
+			prop.pos = len(elem.Properties)
+			elem.Properties = append(elem.Properties, prop)
+		}
+		p.Elements = append(p.Elements, elem)
+	}
+	return p
+}
+
+// Validate checks that p holds every element and property of s with the
+// same kind and type, old and new type names being interchangeable.
+// Additional elements and properties are allowed.
+func (s Schema) Validate(p *PLY) error {
+	for _, es := range s.Elements {
+		elem := p.findElement(es.Name)
+		if elem == nil {
+			return errors.New("Missing element " + es.Name)
+		}
+		for _, ps := range es.Properties {
+			prop := elem.findProperty(ps.Name)
+			if prop == nil {
+				return errors.New("Missing property " + es.Name + "." + ps.Name)
+			}
+			if prop.IsList != ps.IsList || normalizeType(prop.Type) != normalizeType(ps.Type) {
+				return errors.New("Property " + es.Name + "." + ps.Name + " has type " +
+					typeDecl(prop.IsList, prop.Type) + ", expected " + typeDecl(ps.IsList, ps.Type))
+			}
+		}
+	}
+	return nil
+}
+
+func typeDecl(isList bool, typeName string) string {
+	if isList {
+		return "list of " + typeName
+	}
+	return typeName
+}
@@ -0,0 +1,41 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSchemaPresets(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if e := MeshBasic.Validate(p); e != nil {
+		t.Error(e)
+	}
+	if e := PointXYZRGB.Validate(p); e == nil || !strings.Contains(e.Error(), "vertex.red") {
+		t.Errorf("expected a missing red property, got %v", e)
+	}
+	p.Elements[0].Properties[0].Type = "double"
+	if e := PointXYZ.Validate(p); e == nil {
+		t.Error("expected a type mismatch")
+	}
+
+	q := MeshTextured.New(map[string]int{"vertex": 3, "face": 1})
+	if e := MeshTextured.Validate(q); e != nil {
+		t.Fatal(e)
+	}
+	q.findElement("face").findProperty("vertex_indices").setListIntsAt(0, []int{0, 1, 2})
+	var out bytes.Buffer
+	if e := q.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	r := new(PLY)
+	if e := r.Read(&out); e != nil {
+		t.Fatal(e)
+	}
+	if e := MeshTextured.Validate(r); e != nil || r.VerticesCount() != 3 || len(r.ReadFaces()[0]) != 3 {
+		t.Errorf("unexpected round trip: %v", e)
+	}
+}
@@ -0,0 +1,74 @@
+package ply
+
+import (
+	"errors"
+	"strings"
+)
+
+// selected reports which properties of elem LoadOptions.Properties keeps,
+// nil when it keeps them all.
+func (opts *LoadOptions) selected(elem *Element) []bool {
+	if opts.Properties == nil {
+		return nil
+	}
+	keep := make([]bool, len(elem.Properties))
+	for k, prop := range elem.Properties {
+		for _, name := range opts.Properties {
+			if name == elem.Name+"."+prop.Name {
+				keep[k] = true
+			}
+		}
+	}
+	return keep
+}
+
+// checkSelection verifies that LoadOptions.Properties and Rows name
+// properties and elements declared by the header.
+func checkSelection(p *PLY, opts *LoadOptions) error {
+	for name, r := range opts.Rows {
+		if p.findElement(name) == nil {
+			return errors.New("No element " + name)
+		}
+		if _, _, e := r.clamp(0); e != nil {
+			return errors.New(e.Error() + " for element " + name)
+		}
+	}
+	for _, name := range opts.Properties {
+		dot := strings.IndexByte(name, '.')
+		if dot < 0 {
+			return errors.New("Invalid property name " + name + ", expected element.property")
+		}
+		elem := p.findElement(name[:dot])
+		if elem == nil {
+			return errors.New("No element " + name[:dot])
+		}
+		if elem.findProperty(name[dot+1:]) == nil {
+			return errors.New("No property " + name[dot+1:] + " in element " + elem.Name)
+		}
+	}
+	return nil
+}
+
+// dropUnselected removes the properties skipped by LoadOptions.Properties
+// and the elements left without any.
+func dropUnselected(p *PLY, opts *LoadOptions) {
+	if opts.Properties == nil {
+		return
+	}
+	var elements []*Element
+	for _, elem := range p.Elements {
+		keep := opts.selected(elem)
+		var props []*Property
+		for k, prop := range elem.Properties {
+			if keep[k] {
+				prop.pos = len(props)
+				props = append(props, prop)
+			}
+		}
+		if props != nil {
+			elem.Properties = props
+			elements = append(elements, elem)
+		}
+	}
+	p.Elements = elements
+}
@@ -0,0 +1,48 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSelectProperties(t *testing.T) {
+	ascii := benchMesh(100)
+	binary := benchBinary(t, 100)
+	all := new(PLY)
+	all.Read(strings.NewReader(ascii))
+	opts := &LoadOptions{Properties: []string{"vertex.x", "vertex.z", "face.vertex_indices"}}
+	for _, src := range []string{ascii, string(binary)} {
+		p := new(PLY)
+		if e := p.ReadWithOptions(strings.NewReader(src), opts); e != nil {
+			t.Fatal(e)
+		}
+		vertex := p.findElement("vertex")
+		if len(vertex.Properties) != 2 || vertex.Properties[1].Name != "z" || vertex.Properties[1].pos != 1 {
+			t.Fatalf("unexpected properties %v", vertex.Properties)
+		}
+		z, _ := vertex.Float32s("z")
+		want, _ := all.findElement("vertex").Float32s("z")
+		faces := p.ReadFaces()
+		if z[99] != want[99] || len(faces) != 98 || faces[97][2] != 99 {
+			t.Errorf("unexpected values %v %v", z[99], faces[97])
+		}
+	}
+
+	p := new(PLY)
+	if e := p.ReadWithOptions(bytes.NewReader(binary), &LoadOptions{Properties: []string{"vertex.red"}}); e != nil {
+		t.Fatal(e)
+	}
+	if len(p.Elements) != 1 || p.VerticesCount() != 100 {
+		t.Error("expected the face element to be left out")
+	}
+	for _, names := range [][]string{{"vertex.w"}, {"edge.x"}, {"x"}} {
+		if e := new(PLY).ReadWithOptions(bytes.NewReader(binary), &LoadOptions{Properties: names}); e == nil {
+			t.Errorf("expected an error for %v", names)
+		}
+	}
+	cut := binary[:len(binary)-10]
+	if _, ok := new(PLY).ReadWithOptions(bytes.NewReader(cut), opts).(*TruncatedError); !ok {
+		t.Error("expected a truncated body to be reported")
+	}
+}
@@ -0,0 +1,511 @@
+package ply
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"strconv"
+)
+
+// boundaryWeight scales the quadrics keeping boundary edges in place
+// relative to those of the faces.
+const boundaryWeight = 100
+
+// Simplify reduces the mesh to about targetFaces triangles by collapsing
+// edges in order of their quadric error (Garland and Heckbert), moving the
+// kept vertex to the position that best preserves the surrounding planes.
+// Polygons are fan-triangulated first. Triangles keep the other face
+// properties of the polygon they come from, except that lists holding the
+// same number of values for every corner, such as texcoord, keep only the
+// values of the triangle's corners. The scalar vertex properties of a
+// collapsed edge are interpolated along it, and the vertex1 and vertex2 of
+// an edge element follow the merged vertices, dropping edges that
+// collapse to a point. Collapses that would flip a triangle or make the
+// mesh non-manifold are skipped, so the target may not be reached. Faces
+// with invalid indices are dropped and vertices without faces kept.
+func (p *PLY) Simplify(targetFaces int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if targetFaces < 0 {
+		return errors.New("Target face count must not be negative")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return e
+	}
+	var ends []*Property
+	if edge := p.findElement("edge"); edge != nil {
+		if ends = edge.scalarProperties("vertex1", "vertex2"); ends == nil {
+			return errors.New("Edge element has no scalar vertex1, vertex2 properties")
+		}
+	}
+	s := &simplifier{pos: pos}
+	for row, f := range faces {
+		for c, t := range fanTriangles(f) {
+			if validTriangle(t, pos) && t[0] != t[1] && t[1] != t[2] && t[0] != t[2] {
+				s.tris = append(s.tris, t)
+				s.rows = append(s.rows, row)
+				s.corners = append(s.corners, [3]int{0, c + 1, c + 2})
+			}
+		}
+	}
+	if targetFaces >= len(s.tris) {
+		return nil
+	}
+	vertex := p.findElement("vertex")
+	var attrs []*Property
+	for _, prop := range vertex.Properties {
+		if !prop.IsList && prop.Name != "x" && prop.Name != "y" && prop.Name != "z" {
+			attrs = append(attrs, prop)
+			values := make([]float64, vertex.Size)
+			for i := range values {
+				values[i] = prop.float64At(i)
+			}
+			s.attrs = append(s.attrs, values)
+		}
+	}
+	s.run(targetFaces)
+
+	// compact the surviving vertices and triangles
+	remap := make([]int, len(pos))
+	var rows []int
+	for i := range pos {
+		remap[i] = -1
+		if !s.removed[i] {
+			remap[i] = len(rows)
+			rows = append(rows, i)
+		}
+	}
+	sub := vertex.selectRows(rows)
+	for _, prop := range sub.Properties {
+		var values func(i int) float64
+		switch prop.Name {
+		case "x", "y", "z":
+			j := int(prop.Name[0] - 'x')
+			values = func(i int) float64 { return s.pos[i][j] }
+		default:
+			for k, a := range attrs {
+				if a.Name == prop.Name {
+					column := s.attrs[k]
+					values = func(i int) float64 { return column[i] }
+				}
+			}
+		}
+		if values == nil {
+			continue
+		}
+		for n, i := range rows {
+			prop.Data[n] = encodeFloat64(values(i), prop.Type, prop.byteOrder())
+		}
+	}
+	if normals := sub.scalarProperties("nx", "ny", "nz"); normals != nil {
+		for n := range rows {
+			var v [3]float64
+			for j := range v {
+				v[j] = normals[j].float64At(n)
+			}
+			v = normalize3(v)
+			for j := range v {
+				normals[j].Data[n] = encodeFloat64(v[j], normals[j].Type, normals[j].byteOrder())
+			}
+		}
+	}
+	face := p.findElement("face")
+	idx := p.faceIndexProperty(face)
+	var faceRows []int
+	faceData := newListColumn(idx)
+	lists := make(map[string]*listColumn)
+	for _, prop := range face.Properties {
+		if prop.IsList && prop != idx {
+			lists[prop.Name] = newListColumn(prop)
+		}
+	}
+	for k, t := range s.tris {
+		if s.dead[k] {
+			continue
+		}
+		row := s.rows[k]
+		faceRows = append(faceRows, row)
+		faceData.add([]float64{float64(remap[t[0]]), float64(remap[t[1]]), float64(remap[t[2]])})
+		for _, prop := range face.Properties {
+			if column := lists[prop.Name]; column != nil {
+				column.add(cornerValues(prop.listFloat64At(row), len(faces[row]), s.corners[k]))
+			}
+		}
+	}
+	subFaces := face.selectRows(faceRows)
+	for _, prop := range subFaces.Properties {
+		if prop.Name == idx.Name {
+			prop.Data = faceData.rows()
+		} else if column := lists[prop.Name]; column != nil {
+			prop.Data = column.rows()
+		}
+	}
+	var subEdges *Element
+	if ends != nil {
+		subEdges = s.remapEdges(p.findElement("edge"), ends, remap)
+	}
+	vertex.Properties, vertex.Size = sub.Properties, sub.Size
+	face.Properties, face.Size = subFaces.Properties, subFaces.Size
+	if subEdges != nil {
+		edge := p.findElement("edge")
+		edge.Properties, edge.Size = subEdges.Properties, subEdges.Size
+	}
+	return nil
+}
+
+// cornerValues returns the values of the given corners from a face list
+// holding the same number of values for each of its n corners, and the
+// whole list otherwise.
+func cornerValues(values []float64, n int, corners [3]int) []float64 {
+	if n == 0 || len(values) == 0 || len(values)%n != 0 {
+		return values
+	}
+	m := len(values) / n
+	out := make([]float64, 0, 3*m)
+	for _, c := range corners {
+		out = append(out, values[c*m:(c+1)*m]...)
+	}
+	return out
+}
+
+// remapEdges returns the rows of edge with their ends moved to the
+// vertices they were merged into and renumbered by remap, dropping edges
+// with invalid ends or that collapsed to a point.
+func (s *simplifier) remapEdges(edge *Element, ends []*Property, remap []int) *Element {
+	var rows []int
+	var pairs [][2]int
+	for i := 0; i < edge.Size; i++ {
+		var pair [2]int
+		ok := true
+		for j, prop := range ends {
+			v := prop.float64At(i)
+			n := int(v)
+			if n < 0 || n >= len(remap) || float64(n) != v {
+				ok = false
+				break
+			}
+			for s.removed[n] {
+				n = s.into[n]
+			}
+			pair[j] = remap[n]
+		}
+		if ok && pair[0] != pair[1] {
+			rows = append(rows, i)
+			pairs = append(pairs, pair)
+		}
+	}
+	sub := edge.selectRows(rows)
+	for j, name := range []string{ends[0].Name, ends[1].Name} {
+		prop := sub.findProperty(name)
+		for i, pair := range pairs {
+			prop.Data[i] = encodeFloat64(float64(pair[j]), prop.Type, prop.byteOrder())
+		}
+	}
+	return sub
+}
+
+// SimplifyRatio is Simplify with the target given as a share of the
+// current triangles, e.g. 0.1 to keep a tenth of them.
+func (p *PLY) SimplifyRatio(ratio float64) error {
+	if !(ratio >= 0 && ratio <= 1) {
+		return errors.New("Simplify ratio " + strconv.FormatFloat(ratio, 'g', -1, 64) + " outside [0, 1]")
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return e
+	}
+	tris := 0
+	for _, f := range faces {
+		if len(f) >= 3 {
+			tris += len(f) - 2
+		}
+	}
+	return p.Simplify(int(ratio*float64(tris) + 0.5))
+}
+
+// quadric is a symmetric 4x4 matrix measuring the squared distance to a
+// set of planes, stored as its upper triangle row by row.
+type quadric [10]float64
+
+// planeQuadric returns the quadric of the plane through v with unit
+// normal n, scaled by w.
+func planeQuadric(n, v [3]float64, w float64) quadric {
+	a, b, c := n[0], n[1], n[2]
+	d := -dot3(n, v)
+	return quadric{w * a * a, w * a * b, w * a * c, w * a * d, w * b * b, w * b * c, w * b * d,
+		w * c * c, w * c * d, w * d * d}
+}
+
+func (q *quadric) add(r quadric) {
+	for k := range q {
+		q[k] += r[k]
+	}
+}
+
+func (q *quadric) error(v [3]float64) float64 {
+	x, y, z := v[0], v[1], v[2]
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x + q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z + q[9]
+}
+
+// optimum returns the point minimizing the error, if the quadric is not
+// singular.
+func (q *quadric) optimum() ([3]float64, bool) {
+	m := [3][3]float64{{q[0], q[1], q[2]}, {q[1], q[4], q[5]}, {q[2], q[5], q[7]}}
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) - m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	scale := math.Abs(q[0]) + math.Abs(q[4]) + math.Abs(q[7])
+	if !(math.Abs(det) > 1e-10*scale*scale*scale) {
+		return [3]float64{}, false
+	}
+	rhs := [3]float64{-q[3], -q[6], -q[8]}
+	var x [3]float64
+	// Cramer's rule
+	for j := 0; j < 3; j++ {
+		mj := m
+		for r := 0; r < 3; r++ {
+			mj[r][j] = rhs[r]
+		}
+		x[j] = (mj[0][0]*(mj[1][1]*mj[2][2]-mj[1][2]*mj[2][1]) - mj[0][1]*(mj[1][0]*mj[2][2]-mj[1][2]*mj[2][0]) +
+			mj[0][2]*(mj[1][0]*mj[2][1]-mj[1][1]*mj[2][0])) / det
+	}
+	return x, true
+}
+
+type simplifier struct {
+	pos   [][3]float64
+	attrs [][]float64
+	tris  [][3]int
+	// rows holds the face each triangle comes from and corners the
+	// positions of its corners in that face
+	rows    []int
+	corners [][3]int
+	dead    []bool
+	live    int
+	// around lists the triangles of each vertex, including dead ones
+	around   [][]int
+	quadrics []quadric
+	removed  []bool
+	// into holds the vertex each removed vertex was merged into
+	into []int
+	// version invalidates the queued collapses of a changed vertex
+	version []int
+	queue   collapseQueue
+}
+
+type collapse struct {
+	cost   float64
+	a, b   int
+	va, vb int
+	target [3]float64
+}
+
+type collapseQueue []collapse
+
+func (q collapseQueue) Len() int { return len(q) }
+func (q collapseQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].a != q[j].a {
+		return q[i].a < q[j].a
+	}
+	return q[i].b < q[j].b
+}
+func (q collapseQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *collapseQueue) Push(x interface{}) { *q = append(*q, x.(collapse)) }
+func (q *collapseQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+func (s *simplifier) run(target int) {
+	n := len(s.pos)
+	s.dead = make([]bool, len(s.tris))
+	s.live = len(s.tris)
+	s.around = make([][]int, n)
+	s.quadrics = make([]quadric, n)
+	s.removed = make([]bool, n)
+	s.into = make([]int, n)
+	s.version = make([]int, n)
+	edges := make(map[[2]int]int)
+	for k, t := range s.tris {
+		normal := cross3(sub3(s.pos[t[1]], s.pos[t[0]]), sub3(s.pos[t[2]], s.pos[t[0]]))
+		area := length3(normal) / 2
+		normal = normalize3(normal)
+		for c, v := range t {
+			s.around[v] = append(s.around[v], k)
+			s.quadrics[v].add(planeQuadric(normal, s.pos[v], area))
+			a, b := v, t[(c+1)%3]
+			if a > b {
+				a, b = b, a
+			}
+			edges[[2]int{a, b}]++
+		}
+	}
+	// hold boundary edges in place with planes perpendicular to their
+	// triangle
+	for _, t := range s.tris {
+		normal := triangleNormal(s.pos[t[0]], s.pos[t[1]], s.pos[t[2]])
+		for c := range t {
+			a, b := t[c], t[(c+1)%3]
+			key := [2]int{a, b}
+			if a > b {
+				key = [2]int{b, a}
+			}
+			if edges[key] != 1 {
+				continue
+			}
+			d := sub3(s.pos[b], s.pos[a])
+			side := normalize3(cross3(d, normal))
+			w := boundaryWeight * dot3(d, d)
+			s.quadrics[a].add(planeQuadric(side, s.pos[a], w))
+			s.quadrics[b].add(planeQuadric(side, s.pos[a], w))
+		}
+	}
+	for key := range edges {
+		s.push(key[0], key[1])
+	}
+	for s.live > target && s.queue.Len() > 0 {
+		c := heap.Pop(&s.queue).(collapse)
+		if s.removed[c.a] || s.removed[c.b] || s.version[c.a] != c.va || s.version[c.b] != c.vb {
+			continue
+		}
+		s.collapse(c)
+	}
+}
+
+// push queues the collapse of the edge from a to b at its best position.
+func (s *simplifier) push(a, b int) {
+	q := s.quadrics[a]
+	q.add(s.quadrics[b])
+	mid := scale3(add3(s.pos[a], s.pos[b]), 0.5)
+	target, ok := q.optimum()
+	// nearly singular quadrics can put the optimum far off the edge
+	if ok && length3(sub3(target, mid)) > length3(sub3(s.pos[b], s.pos[a])) {
+		ok = false
+	}
+	cost := 0.0
+	if ok {
+		cost = q.error(target)
+	} else {
+		cost = math.Inf(1)
+		for _, v := range [][3]float64{s.pos[a], s.pos[b], mid} {
+			if e := q.error(v); e < cost {
+				target, cost = v, e
+			}
+		}
+	}
+	heap.Push(&s.queue, collapse{cost, a, b, s.version[a], s.version[b], target})
+}
+
+// collapse merges b into a at the target position unless that flips a
+// triangle or breaks the manifold.
+func (s *simplifier) collapse(c collapse) {
+	a, b := c.a, c.b
+	var shared []int
+	// sides marks the vertices adjacent to a with 1 and to b with 2
+	sides := make(map[int]int)
+	for bit, v := range [2]int{a, b} {
+		for _, k := range s.around[v] {
+			if s.dead[k] {
+				continue
+			}
+			t := s.tris[k]
+			for _, u := range t {
+				if u != v {
+					sides[u] |= 1 << uint(bit)
+				}
+			}
+			if hasCorner(t, a) && hasCorner(t, b) {
+				if v == a {
+					shared = append(shared, k)
+				}
+				continue
+			}
+			if s.flips(t, v, c.target) {
+				return
+			}
+		}
+	}
+	// the link condition: a and b share no neighbors but the corners
+	// opposite their edge
+	common := 0
+	for u, bits := range sides {
+		if bits == 3 && u != a && u != b {
+			common++
+		}
+	}
+	if len(shared) == 0 || common != len(shared) {
+		return
+	}
+	for _, k := range shared {
+		s.dead[k] = true
+		s.live--
+	}
+	for _, k := range s.around[b] {
+		if s.dead[k] {
+			continue
+		}
+		for j := range s.tris[k] {
+			if s.tris[k][j] == b {
+				s.tris[k][j] = a
+			}
+		}
+		s.around[a] = append(s.around[a], k)
+	}
+	d := sub3(s.pos[b], s.pos[a])
+	t := 0.0
+	if l := dot3(d, d); l > 0 {
+		t = clampFloat64(dot3(sub3(c.target, s.pos[a]), d)/l, 0, 1)
+	}
+	for _, column := range s.attrs {
+		column[a] += (column[b] - column[a]) * t
+	}
+	s.pos[a] = c.target
+	s.quadrics[a].add(s.quadrics[b])
+	s.removed[b] = true
+	s.into[b] = a
+	s.around[b] = nil
+	s.version[a]++
+	s.version[b]++
+	queued := make(map[int]bool)
+	for _, k := range s.around[a] {
+		if s.dead[k] {
+			continue
+		}
+		for _, u := range s.tris[k] {
+			if u != a && !queued[u] {
+				queued[u] = true
+				s.push(a, u)
+			}
+		}
+	}
+}
+
+// flips reports whether moving corner v of t to target turns t over or
+// makes it degenerate.
+func (s *simplifier) flips(t [3]int, v int, target [3]float64) bool {
+	var moved [3][3]float64
+	for j, u := range t {
+		moved[j] = s.pos[u]
+		if u == v {
+			moved[j] = target
+		}
+	}
+	before := cross3(sub3(s.pos[t[1]], s.pos[t[0]]), sub3(s.pos[t[2]], s.pos[t[0]]))
+	after := cross3(sub3(moved[1], moved[0]), sub3(moved[2], moved[0]))
+	return !(dot3(before, after) > 0)
+}
+
+func hasCorner(t [3]int, v int) bool {
+	return t[0] == v || t[1] == v || t[2] == v
+}
@@ -0,0 +1,211 @@
+package ply
+
+import (
+	"math"
+	"testing"
+)
+
+// gridMesh returns an n by n grid of unit squares in the z = 0 plane,
+// split into triangles, with a red property rising along x.
+func gridMesh(t *testing.T, n int) *PLY {
+	var pos [][3]float64
+	for j := 0; j <= n; j++ {
+		for i := 0; i <= n; i++ {
+			pos = append(pos, [3]float64{float64(i), float64(j), 0})
+		}
+	}
+	var faces [][]int
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			v := j*(n+1) + i
+			faces = append(faces, []int{v, v + 1, v + n + 2}, []int{v, v + n + 2, v + n + 1})
+		}
+	}
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	red := make([]float64, len(pos))
+	for i, v := range pos {
+		red[i] = v[0] * 255 / float64(n)
+	}
+	if e := p.GetVertices().AddProperty("red", "uchar", red); e != nil {
+		t.Fatal(e)
+	}
+	p.Elements = append(p.Elements, faceElement(faces))
+	return p
+}
+
+func TestSimplifyPlane(t *testing.T) {
+	p := gridMesh(t, 20)
+	if e := p.Simplify(100); e != nil {
+		t.Fatal(e)
+	}
+	faces := p.ReadFaces()
+	if len(faces) > 100 || len(faces) < 2 {
+		t.Errorf("expected at most 100 faces, got %d", len(faces))
+	}
+	pos, _ := p.Positions()
+	corners := 0
+	red := p.GetVertices().findProperty("red")
+	for i, v := range pos {
+		if v[2] != 0 || v[0] < 0 || v[0] > 20 || v[1] < 0 || v[1] > 20 {
+			t.Errorf("vertex %v left the square", v)
+		}
+		if (v[0] == 0 || v[0] == 20) && (v[1] == 0 || v[1] == 20) {
+			corners++
+		}
+		if math.Abs(red.float64At(i)-v[0]*255/20) > 1 {
+			t.Errorf("expected red interpolated along x, got %v at %v", red.float64At(i), v)
+		}
+	}
+	if corners != 4 {
+		t.Errorf("expected the corners kept, got %d", corners)
+	}
+	area := 0.0
+	for _, f := range faces {
+		if len(f) != 3 {
+			t.Fatalf("expected triangles, got %v", f)
+		}
+		n := cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]]))
+		if n[2] <= 0 {
+			t.Errorf("face %v flipped", f)
+		}
+		area += n[2] / 2
+	}
+	if math.Abs(area-400) > 1e-9 {
+		t.Errorf("expected the area kept, got %v", area)
+	}
+}
+
+func TestSimplifySphere(t *testing.T) {
+	// a latitude-longitude sphere without poles, closed by two fans
+	const rings, segments = 20, 40
+	var pos [][3]float64
+	for r := 1; r < rings; r++ {
+		theta := math.Pi * float64(r) / rings
+		for s := 0; s < segments; s++ {
+			phi := 2 * math.Pi * float64(s) / segments
+			pos = append(pos, [3]float64{math.Sin(theta) * math.Cos(phi), math.Sin(theta) * math.Sin(phi), math.Cos(theta)})
+		}
+	}
+	top, bottom := len(pos), len(pos)+1
+	pos = append(pos, [3]float64{0, 0, 1}, [3]float64{0, 0, -1})
+	var faces [][]int
+	at := func(r, s int) int { return r*segments + (s+segments)%segments }
+	for s := 0; s < segments; s++ {
+		faces = append(faces, []int{top, at(0, s), at(0, s+1)},
+			[]int{bottom, at(rings-2, s+1), at(rings-2, s)})
+		for r := 0; r+1 < rings-1; r++ {
+			faces = append(faces, []int{at(r, s), at(r+1, s), at(r+1, s+1), at(r, s+1)})
+		}
+	}
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	p.Elements = append(p.Elements, faceElement(faces))
+	if e := p.SimplifyRatio(0.2); e != nil {
+		t.Fatal(e)
+	}
+	faces = p.ReadFaces()
+	if len(faces) > 304 || len(faces) < 303 {
+		t.Errorf("expected about 304 faces, got %d", len(faces))
+	}
+	pos, _ = p.Positions()
+	edges := make(map[[2]int]int)
+	for _, f := range faces {
+		n := cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]]))
+		if dot3(n, add3(add3(pos[f[0]], pos[f[1]]), pos[f[2]])) <= 0 {
+			t.Errorf("face %v points inwards", f)
+		}
+		for c := range f {
+			a, b := f[c], f[(c+1)%3]
+			if a > b {
+				a, b = b, a
+			}
+			edges[[2]int{a, b}]++
+		}
+	}
+	for e, n := range edges {
+		if n != 2 {
+			t.Fatalf("edge %v has %d faces, expected a closed manifold", e, n)
+		}
+	}
+	for _, v := range pos {
+		if r := length3(v); math.Abs(r-1) > 0.1 {
+			t.Errorf("vertex %v strays from the sphere", v)
+		}
+	}
+	if e := p.SimplifyRatio(2); e == nil {
+		t.Error("expected an error for a ratio above 1")
+	}
+}
+
+func TestSimplifyQuadsWithEdges(t *testing.T) {
+	const n = 10
+	var pos [][3]float64
+	for j := 0; j <= n; j++ {
+		for i := 0; i <= n; i++ {
+			pos = append(pos, [3]float64{float64(i), float64(j), 0})
+		}
+	}
+	var faces [][]int
+	var uvs [][]Vec2
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			v := j*(n+1) + i
+			f := []int{v, v + 1, v + n + 2, v + n + 1}
+			var uv []Vec2
+			for _, c := range f {
+				uv = append(uv, Vec2{pos[c][0] / n, pos[c][1] / n})
+			}
+			faces, uvs = append(faces, f), append(uvs, uv)
+		}
+	}
+	p := new(PLY)
+	if e := p.SetPositions(pos); e != nil {
+		t.Fatal(e)
+	}
+	p.Elements = append(p.Elements, faceElement(faces))
+	if e := p.SetFaceTexcoords(uvs); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := p.Wireframe(false); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.Simplify(40); e != nil {
+		t.Fatal(e)
+	}
+	uvs, e := p.FaceTexcoords()
+	if e != nil {
+		t.Fatal(e)
+	}
+	for i, f := range p.ReadFaces() {
+		if len(f) != 3 || len(uvs[i]) != 3 {
+			t.Fatalf("face %d: expected a triangle with 3 texcoords, got %v and %v", i, f, uvs[i])
+		}
+	}
+	vertices := p.VerticesCount()
+	for _, edge := range p.ReadEdges() {
+		if edge[0] < 0 || edge[0] >= vertices || edge[1] < 0 || edge[1] >= vertices || edge[0] == edge[1] {
+			t.Errorf("edge %v not remapped to %d vertices", edge, vertices)
+		}
+	}
+}
+
+func TestCornerValues(t *testing.T) {
+	got := cornerValues([]float64{0, 1, 2, 3, 4, 5, 6, 7}, 4, [3]int{0, 2, 3})
+	want := []float64{0, 1, 4, 5, 6, 7}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if got := cornerValues([]float64{1, 2, 3}, 4, [3]int{0, 1, 2}); len(got) != 3 {
+		t.Errorf("expected a list not per corner kept, got %v", got)
+	}
+}
@@ -0,0 +1,59 @@
+package ply
+
+import "errors"
+
+// Smooth moves every vertex towards the mean of its neighbors along the
+// face edges, iterations times. Each iteration takes a step of lambda
+// and, unless mu is 0, a second step of mu. Taubin smoothing makes mu
+// negative and slightly larger than lambda, e.g. 0.5 and -0.53, to remove
+// noise without shrinking the mesh; mu of 0 is plain Laplacian smoothing.
+// Vertices without faces, or with an invalid position, do not move.
+func (p *PLY) Smooth(iterations int, lambda, mu float64) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	if iterations < 0 {
+		return errors.New("Smooth iterations must not be negative")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return e
+	}
+	edges, _ := faceEdges(faces)
+	neighbors := make([][]int, len(pos))
+	for _, edge := range edges {
+		a, b := edge[0], edge[1]
+		if a < 0 || b >= len(pos) || isInvalidPoint(pos[a]) || isInvalidPoint(pos[b]) {
+			continue
+		}
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+	}
+	next := make([][3]float64, len(pos))
+	step := func(factor float64) {
+		for i, v := range pos {
+			next[i] = v
+			if len(neighbors[i]) == 0 {
+				continue
+			}
+			var mean [3]float64
+			for _, j := range neighbors[i] {
+				mean = add3(mean, pos[j])
+			}
+			mean = scale3(mean, 1/float64(len(neighbors[i])))
+			next[i] = add3(v, scale3(sub3(mean, v), factor))
+		}
+		pos, next = next, pos
+	}
+	for n := 0; n < iterations; n++ {
+		step(lambda)
+		if mu != 0 {
+			step(mu)
+		}
+	}
+	return p.SetPositions(pos)
+}
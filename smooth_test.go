@@ -0,0 +1,52 @@
+package ply
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSmooth(t *testing.T) {
+	p := gridMesh(t, 10)
+	pos, _ := p.Positions()
+	r := rand.New(rand.NewSource(1))
+	noisy := make([][3]float64, len(pos))
+	for i, v := range pos {
+		noisy[i] = v
+		if i%11 != 0 && i%11 != 10 && i > 10 && i < 110 {
+			noisy[i][2] = r.Float64() - 0.5
+		}
+	}
+	// the squared height differences along edges
+	edges, _ := faceEdges(p.ReadFaces())
+	roughness := func() float64 {
+		pos, _ := p.Positions()
+		sum := 0.0
+		for _, e := range edges {
+			d := pos[e[0]][2] - pos[e[1]][2]
+			sum += d * d
+		}
+		return sum
+	}
+	if e := p.SetPositions(noisy); e != nil {
+		t.Fatal(e)
+	}
+	before := roughness()
+	if e := p.Smooth(10, 0.5, -0.53); e != nil {
+		t.Fatal(e)
+	}
+	if after := roughness(); !(after < before/10) {
+		t.Errorf("expected the noise damped, from %v to %v", before, after)
+	}
+	// a flat grid keeps its interior in place under Taubin smoothing
+	p = gridMesh(t, 10)
+	if e := p.Smooth(5, 0.5, -0.53); e != nil {
+		t.Fatal(e)
+	}
+	smoothed, _ := p.Positions()
+	if d := length3(sub3(smoothed[60], pos[60])); d > 1e-6 {
+		t.Errorf("expected the center to stay, moved by %v", d)
+	}
+	if e := p.Smooth(-1, 0.5, 0); e == nil {
+		t.Error("expected an error for negative iterations")
+	}
+}
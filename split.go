@@ -0,0 +1,42 @@
+package ply
+
+import "errors"
+
+// SplitByElement returns one PLY per element, in order, each sharing the
+// element's data and p's format, comments and obj_info.
+func (p *PLY) SplitByElement() []*PLY {
+	parts := make([]*PLY, len(p.Elements))
+	for k, elem := range p.Elements {
+		q := p.shallowCopy()
+		q.Elements = []*Element{elem}
+		parts[k] = q
+	}
+	return parts
+}
+
+// SplitByValue partitions the vertices by the value of a scalar vertex
+// property, e.g. a "label" or "class" segmentation, returning one copy of
+// p per distinct value. Faces go to the part holding all their vertices
+// and are dropped if their vertices have different values.
+func (p *PLY) SplitByValue(property string) (map[float64]*PLY, error) {
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return nil, errors.New("No vertex element")
+	}
+	prop := vertex.findProperty(property)
+	if prop == nil || prop.IsList {
+		return nil, errors.New("Vertex element has no scalar " + property + " property")
+	}
+	values := make([]float64, vertex.Size)
+	distinct := make(map[float64]bool)
+	for i := range values {
+		values[i] = prop.float64At(i)
+		distinct[values[i]] = true
+	}
+	parts := make(map[float64]*PLY, len(distinct))
+	for v := range distinct {
+		v := v
+		parts[v] = p.filterVertices(func(i int) bool { return values[i] == v })
+	}
+	return parts, nil
+}
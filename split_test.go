@@ -0,0 +1,32 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	src := strings.NewReplacer("property double quality", "property uchar label",
+		" 0.5\n", " 1\n", " 0.25\n", " 1\n", " -1e-05\n", " 1\n", " 3\n", " 2\n").Replace(testASCIIMesh)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	parts := p.SplitByElement()
+	if len(parts) != 2 || parts[1].Elements[0].Name != "face" || len(parts[1].Comments) != 1 {
+		t.Errorf("unexpected element split %v", parts)
+	}
+	byLabel, e := p.SplitByValue("label")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(byLabel) != 2 || byLabel[1].VerticesCount() != 3 || byLabel[2].VerticesCount() != 1 {
+		t.Fatalf("unexpected value split %v", byLabel)
+	}
+	if len(byLabel[1].ReadFaces()) != 1 || len(byLabel[2].ReadFaces()) != 0 {
+		t.Error("expected only the triangle within label 1 to be kept")
+	}
+	if _, e := p.SplitByValue("missing"); e == nil {
+		t.Error("expected an error for a missing property")
+	}
+}
@@ -0,0 +1,93 @@
+package ply
+
+import "math"
+
+// Stats summarizes the values of a numeric property.
+type Stats struct {
+	// Count is the number of values, all list items for lists, not
+	// counting NaNs and infinities.
+	Count int
+	// NaN and Inf are the numbers of NaN and ±Inf values of float
+	// properties, which are left out of the other fields.
+	NaN          int
+	Inf          int
+	Min, Max     float64
+	Mean, StdDev float64
+	// Histogram counts the values in equal-width bins spanning Min to
+	// Max, the last bin including Max. See StatsWithHistogram.
+	Histogram []int
+}
+
+// Stats returns the minimum, maximum, mean and population standard
+// deviation of the property's values. Every PLY type converts exactly to
+// float64, so Min and Max are the stored values. Without values, Min, Max,
+// Mean and StdDev are NaN.
+func (p *Property) Stats() Stats {
+	return p.StatsWithHistogram(0)
+}
+
+// StatsWithHistogram is Stats with a histogram of bins bins; bins <= 0
+// leaves it nil. When all values are equal they all fall in the first bin.
+func (p *Property) StatsWithHistogram(bins int) Stats {
+	s := Stats{Min: math.Inf(1), Max: math.Inf(-1)}
+	mean, m2 := 0.0, 0.0
+	p.eachValue(func(v float64) {
+		if math.IsNaN(v) {
+			s.NaN++
+			return
+		}
+		if math.IsInf(v, 0) {
+			s.Inf++
+			return
+		}
+		s.Count++
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		// Welford's update keeps the variance accurate for large offsets
+		d := v - mean
+		mean += d / float64(s.Count)
+		m2 += d * (v - mean)
+	})
+	if s.Count == 0 {
+		s.Min, s.Max, s.Mean, s.StdDev = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		return s
+	}
+	s.Mean, s.StdDev = mean, math.Sqrt(m2/float64(s.Count))
+	if bins > 0 {
+		s.Histogram = make([]int, bins)
+		width := (s.Max - s.Min) / float64(bins)
+		p.eachValue(func(v float64) {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return
+			}
+			k := 0
+			if width > 0 {
+				k = int((v - s.Min) / width)
+			}
+			if k >= bins {
+				k = bins - 1
+			}
+			s.Histogram[k]++
+		})
+	}
+	return s
+}
+
+// eachValue calls f with every value of the property, row by row.
+func (p *Property) eachValue(f func(v float64)) {
+	size := SizeOfType[p.Type]
+	if size == 0 {
+		return
+	}
+	order := p.byteOrder()
+	p.load()
+	for i := range p.Data {
+		b := p.row(i)
+		if !p.IsList && len(b) != size {
+			continue
+		}
+		for k := 0; k+size <= len(b); k += size {
+			f(scalarFloat64(b[k:k+size], p.Type, order))
+		}
+	}
+}
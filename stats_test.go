@@ -0,0 +1,44 @@
+package ply
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	x := p.findElement("vertex").findProperty("x")
+	s := x.StatsWithHistogram(2)
+	if s.Count != 4 || s.Min != 0 || s.Max != 1 || s.Mean != 0.5 || s.StdDev != 0.5 ||
+		!reflect.DeepEqual(s.Histogram, []int{2, 2}) {
+		t.Errorf("unexpected x stats %+v", s)
+	}
+	quality := p.findElement("vertex").findProperty("quality")
+	if s := quality.Stats(); s.Min != -1e-05 || s.Max != 3 || s.Histogram != nil {
+		t.Errorf("unexpected quality stats %+v", s)
+	}
+	idx := p.findElement("face").findProperty("vertex_indices")
+	if s := idx.StatsWithHistogram(4); s.Count != 7 || s.Max != 3 ||
+		!reflect.DeepEqual(s.Histogram, []int{2, 2, 2, 1}) {
+		t.Errorf("unexpected list stats %+v", s)
+	}
+
+	x.setFloat64At(1, math.NaN())
+	if s := x.Stats(); s.Count != 3 || s.NaN != 1 || s.Max != 1 {
+		t.Errorf("expected NaNs to be skipped, got %+v", s)
+	}
+	x.setFloat64At(0, math.Inf(-1))
+	if s := x.StatsWithHistogram(4); s.Count != 2 || s.Inf != 1 || s.Min != 0 || s.Max != 1 ||
+		!reflect.DeepEqual(s.Histogram, []int{1, 0, 0, 1}) {
+		t.Errorf("expected infinities to be skipped, got %+v", s)
+	}
+	empty := newProperty("intensity", "ushort", 0)
+	if s := empty.StatsWithHistogram(3); s.Count != 0 || !math.IsNaN(s.Mean) || s.Histogram != nil {
+		t.Errorf("unexpected empty stats %+v", s)
+	}
+}
@@ -0,0 +1,77 @@
+package ply
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// ToSTL writes the mesh as binary STL, or ASCII STL when ascii is set.
+// Polygons are fan-triangulated and facet normals computed from the
+// vertex winding.
+func (p *PLY) ToSTL(w io.Writer, ascii bool) error {
+	tris, pos, e := p.stlTriangles()
+	if e != nil {
+		return e
+	}
+	bw := bufio.NewWriter(w)
+	if ascii {
+		bw.WriteString("solid ply\n")
+		for _, t := range tris {
+			a, b, c := pos[t[0]], pos[t[1]], pos[t[2]]
+			n := triangleNormal(a, b, c)
+			bw.WriteString("  facet normal " + formatVec3(n) + "\n    outer loop\n")
+			for _, v := range [][3]float64{a, b, c} {
+				bw.WriteString("      vertex " + formatVec3(v) + "\n")
+			}
+			bw.WriteString("    endloop\n  endfacet\n")
+		}
+		bw.WriteString("endsolid ply\n")
+		return bw.Flush()
+	}
+	if uint64(len(tris)) > math.MaxUint32 {
+		return errors.New("Too many triangles for STL")
+	}
+	header := make([]byte, 80)
+	copy(header, "binary STL written by go-ply")
+	bw.Write(header)
+	binary.Write(bw, binary.LittleEndian, uint32(len(tris)))
+	rec := make([]byte, 50)
+	for _, t := range tris {
+		a, b, c := pos[t[0]], pos[t[1]], pos[t[2]]
+		for k, v := range [][3]float64{triangleNormal(a, b, c), a, b, c} {
+			for j := 0; j < 3; j++ {
+				binary.LittleEndian.PutUint32(rec[k*12+j*4:], math.Float32bits(float32(v[j])))
+			}
+		}
+		bw.Write(rec)
+	}
+	return bw.Flush()
+}
+
+func (p *PLY) stlTriangles() ([][3]int, [][3]float64, error) {
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return nil, nil, e
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return nil, nil, e
+	}
+	var tris [][3]int
+	for i, f := range faces {
+		for _, idx := range f {
+			if idx < 0 || idx >= len(pos) {
+				return nil, nil, errors.New("Face " + itoa(i) + " references missing vertex " + itoa(idx))
+			}
+		}
+		tris = append(tris, fanTriangles(f)...)
+	}
+	return tris, pos, nil
+}
+
+func formatVec3(v [3]float64) string {
+	return formatFloat(v[0]) + " " + formatFloat(v[1]) + " " + formatFloat(v[2])
+}
@@ -0,0 +1,36 @@
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestToSTL(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := p.ToSTL(&buf, false); e != nil {
+		t.Fatal(e)
+	}
+	b := buf.Bytes()
+	// one triangle plus a fan-triangulated quad
+	if len(b) != 84+3*50 || binary.LittleEndian.Uint32(b[80:]) != 3 {
+		t.Fatalf("unexpected binary STL size %d", len(b))
+	}
+	if nz := math.Float32frombits(binary.LittleEndian.Uint32(b[84+8:])); nz != 1 {
+		t.Errorf("expected +z facet normal, got %v", nz)
+	}
+	buf.Reset()
+	if e := p.ToSTL(&buf, true); e != nil {
+		t.Fatal(e)
+	}
+	out := buf.String()
+	if strings.Count(out, "facet normal 0 0 1") != 3 || !strings.HasSuffix(out, "endsolid ply\n") {
+		t.Errorf("unexpected ASCII STL:\n%s", out)
+	}
+}
@@ -0,0 +1,207 @@
+package ply
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// TexcoordName is the per-face list of u, v pairs, one pair per corner.
+const TexcoordName = "texcoord"
+
+// TexnumberName is the per-face index into TextureFiles of multi-texture
+// meshes.
+const TexnumberName = "texnumber"
+
+const textureFileComment = "TextureFile"
+
+// TextureFiles returns the images named by "comment TextureFile" lines,
+// in order.
+func (p *PLY) TextureFiles() []string {
+	var files []string
+	for _, c := range p.Comments {
+		if name, ok := textureFile(c); ok {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// SetTextureFiles replaces the TextureFile comments by one per file,
+// placed where the first one was or else after the other comments.
+func (p *PLY) SetTextureFiles(files []string) {
+	if p.frozen {
+		panic(ErrFrozen)
+	}
+	var comments []string
+	added := false
+	add := func() {
+		for _, f := range files {
+			comments = append(comments, textureFileComment+" "+f)
+		}
+		added = true
+	}
+	for _, c := range p.Comments {
+		if _, ok := textureFile(c); !ok {
+			comments = append(comments, c)
+		} else if !added {
+			add()
+		}
+	}
+	if !added {
+		add()
+	}
+	p.Comments = comments
+}
+
+func textureFile(comment string) (string, bool) {
+	words := strings.Fields(comment)
+	if len(words) < 2 || !strings.EqualFold(words[0], textureFileComment) {
+		return "", false
+	}
+	return strip(comment[strings.Index(comment, words[0])+len(words[0]):]), true
+}
+
+// FaceTexcoords decodes the texcoord list of every face into one u, v pair
+// per corner.
+func (p *PLY) FaceTexcoords() ([][]Vec2, error) {
+	face := p.findElement("face")
+	if face == nil {
+		return nil, errors.New("No face element")
+	}
+	prop := face.findProperty(TexcoordName)
+	if prop == nil || !prop.IsList {
+		return nil, errors.New("Face element has no " + TexcoordName + " list")
+	}
+	uvs := make([][]Vec2, face.Size)
+	for i := range uvs {
+		values := prop.listFloat64At(i)
+		if len(values)%2 != 0 {
+			return nil, errors.New("Odd number of texture coordinates in face " + itoa(i))
+		}
+		uvs[i] = make([]Vec2, len(values)/2)
+		for k := range uvs[i] {
+			uvs[i][k] = Vec2{values[2*k], values[2*k+1]}
+		}
+	}
+	return uvs, nil
+}
+
+// SetFaceTexcoords stores one u, v pair per face corner in the texcoord
+// list, adding it as a list of uchar float when missing.
+func (p *PLY) SetFaceTexcoords(uvs [][]Vec2) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	faces, e := p.faceIndices()
+	if e != nil {
+		return e
+	}
+	if len(uvs) != len(faces) {
+		return errors.New("Expected texture coordinates for " + itoa(len(faces)) + " faces")
+	}
+	for i, f := range faces {
+		if len(uvs[i]) != len(f) {
+			return errors.New("Face " + itoa(i) + " has " + itoa(len(f)) + " corners")
+		}
+	}
+	face := p.findElement("face")
+	prop := face.findProperty(TexcoordName)
+	if prop == nil || !prop.IsList {
+		if prop != nil {
+			return errors.New("Property " + TexcoordName + " is not a list")
+		}
+		prop = newListProperty(TexcoordName, "uchar", "float", face.Size)
+		prop.pos = len(face.Properties)
+		face.Properties = append(face.Properties, prop)
+	}
+	for i, corners := range uvs {
+		values := make([]float64, 0, 2*len(corners))
+		for _, uv := range corners {
+			values = append(values, uv[0], uv[1])
+		}
+		prop.setListFloat64At(i, values)
+	}
+	return nil
+}
+
+// FaceTextures returns the texnumber of every face, the index of its image
+// in TextureFiles.
+func (p *PLY) FaceTextures() ([]int, error) {
+	face := p.findElement("face")
+	if face == nil {
+		return nil, errors.New("No face element")
+	}
+	prop := face.findProperty(TexnumberName)
+	if prop == nil || prop.IsList {
+		return nil, errors.New("Face element has no scalar " + TexnumberName + " property")
+	}
+	numbers := make([]int, face.Size)
+	for i := range numbers {
+		numbers[i] = int(prop.float64At(i))
+	}
+	return numbers, nil
+}
+
+// SetFaceTextures stores the texnumber of every face, adding it as an int
+// property when missing.
+func (p *PLY) SetFaceTextures(numbers []int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	face := p.findElement("face")
+	if face == nil {
+		return errors.New("No face element")
+	}
+	if len(numbers) != face.Size {
+		return errors.New("Expected texture numbers for " + itoa(face.Size) + " faces")
+	}
+	prop := face.findProperty(TexnumberName)
+	if prop == nil {
+		prop = newProperty(TexnumberName, "int", face.Size)
+		prop.pos = len(face.Properties)
+		face.Properties = append(face.Properties, prop)
+	} else if prop.IsList {
+		return errors.New("Property " + TexnumberName + " is a list")
+	}
+	for i, n := range numbers {
+		prop.setFloat64At(i, float64(n))
+	}
+	return nil
+}
+
+// FacesByTexture groups the face rows by texnumber.
+func (p *PLY) FacesByTexture() (map[int][]int, error) {
+	numbers, e := p.FaceTextures()
+	if e != nil {
+		return nil, e
+	}
+	groups := make(map[int][]int)
+	for i, n := range numbers {
+		groups[n] = append(groups[n], i)
+	}
+	return groups, nil
+}
+
+// SortFacesByTexture reorders the faces by texnumber, keeping their order
+// within each texture, so that every texture's faces are contiguous and
+// can be drawn in one batch.
+func (p *PLY) SortFacesByTexture() error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	numbers, e := p.FaceTextures()
+	if e != nil {
+		return e
+	}
+	rows := make([]int, len(numbers))
+	for i := range rows {
+		rows[i] = i
+	}
+	sort.SliceStable(rows, func(a, b int) bool { return numbers[rows[a]] < numbers[rows[b]] })
+	face := p.findElement("face")
+	sub := face.selectRows(rows)
+	face.Properties = sub.Properties
+	face.Size = sub.Size
+	return nil
+}
@@ -0,0 +1,80 @@
+package ply
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTextures(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	p.Comments = append(p.Comments, "TextureFile  albedo.png", "texturefile normal map.png")
+	if files := p.TextureFiles(); !reflect.DeepEqual(files, []string{"albedo.png", "normal map.png"}) {
+		t.Errorf("unexpected texture files %q", files)
+	}
+	p.SetTextureFiles([]string{"atlas.jpg"})
+	if !reflect.DeepEqual(p.Comments, []string{"made by hand", "TextureFile atlas.jpg"}) {
+		t.Errorf("unexpected comments %q", p.Comments)
+	}
+
+	if _, e := p.FaceTexcoords(); e == nil {
+		t.Error("expected an error without texcoord")
+	}
+	uvs := [][]Vec2{{{0, 0}, {1, 0}, {1, 1}}, {{0, 0}, {1, 0}, {1, 1}, {0, 1}}}
+	if e := p.SetFaceTexcoords(uvs[:1]); e == nil {
+		t.Error("expected an error for a missing face")
+	}
+	if e := p.SetFaceTexcoords(uvs); e != nil {
+		t.Fatal(e)
+	}
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	q := new(PLY)
+	if e := q.Read(&out); e != nil {
+		t.Fatal(e)
+	}
+	got, e := q.FaceTexcoords()
+	if e != nil || !reflect.DeepEqual(got, uvs) || len(q.TextureFiles()) != 1 {
+		t.Errorf("expected textures to round-trip, got %v %v", got, e)
+	}
+
+	if e := q.Triangulate(FanTriangulation); e != nil {
+		t.Fatal(e)
+	}
+	got, _ = q.FaceTexcoords()
+	if len(got) != 3 || !reflect.DeepEqual(got[2], []Vec2{{0, 0}, {1, 1}, {0, 1}}) {
+		t.Errorf("expected texcoords split with the triangles, got %v", got)
+	}
+}
+
+func TestTexnumber(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := p.FaceTextures(); e == nil {
+		t.Error("expected an error without texnumber")
+	}
+	if e := p.SetFaceTextures([]int{1, 0}); e != nil {
+		t.Fatal(e)
+	}
+	groups, e := p.FacesByTexture()
+	if e != nil || !reflect.DeepEqual(groups, map[int][]int{0: {1}, 1: {0}}) {
+		t.Errorf("unexpected groups %v %v", groups, e)
+	}
+	if e := p.SortFacesByTexture(); e != nil {
+		t.Fatal(e)
+	}
+	numbers, _ := p.FaceTextures()
+	faces := p.ReadFaces()
+	flags := p.findElement("face").findProperty("flags")
+	if !reflect.DeepEqual(numbers, []int{0, 1}) || len(faces[0]) != 4 || flags.float64At(0) != 300 {
+		t.Errorf("expected the quad and its flags first, got %v %v", numbers, faces)
+	}
+}
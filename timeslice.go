@@ -0,0 +1,87 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// TimeSlice holds the vertices of p whose time falls in [Start, End).
+type TimeSlice struct {
+	// Index numbers the window from the earliest time, counting empty
+	// windows, so frames of a capture keep their place in time.
+	Index      int
+	Start, End float64
+	PLY        *PLY
+}
+
+// SplitByTime partitions the vertices by windows of the given length over
+// a scalar time property such as "gps_time", starting at the earliest
+// time. Each slice is a copy of p keeping all vertex properties; windows
+// without vertices are left out and vertices with a NaN time are dropped.
+func (p *PLY) SplitByTime(property string, window float64) ([]TimeSlice, error) {
+	if !(window > 0) || math.IsInf(window, 0) {
+		return nil, errors.New("Time window must be positive")
+	}
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return nil, errors.New("No vertex element")
+	}
+	prop := vertex.findProperty(property)
+	if prop == nil || prop.IsList {
+		return nil, errors.New("Vertex element has no scalar " + property + " property")
+	}
+	start := math.Inf(1)
+	for i := 0; i < vertex.Size; i++ {
+		if t := prop.float64At(i); t < start {
+			start = t
+		}
+	}
+	frames := make([]int, vertex.Size)
+	index := make(map[int]bool)
+	var order []int
+	for i := range frames {
+		t := prop.float64At(i)
+		frames[i] = -1
+		if math.IsNaN(t) {
+			continue
+		}
+		frames[i] = int((t - start) / window)
+		if !index[frames[i]] {
+			index[frames[i]] = true
+			order = append(order, frames[i])
+		}
+	}
+	sort.Ints(order)
+	slices := make([]TimeSlice, len(order))
+	for k, n := range order {
+		n := n
+		slices[k] = TimeSlice{
+			Index: n,
+			Start: start + float64(n)*window,
+			End:   start + float64(n+1)*window,
+			PLY:   p.filterVertices(func(i int) bool { return frames[i] == n }),
+		}
+	}
+	return slices, nil
+}
+
+// SaveTimeSlices saves each slice to dir as prefix followed by its
+// zero-padded Index and ".ply", e.g. frame_000042.ply, and returns the
+// file names.
+func SaveTimeSlices(slices []TimeSlice, dir, prefix string, opts *SaveOptions) ([]string, error) {
+	names := make([]string, len(slices))
+	for k, s := range slices {
+		n := strconv.Itoa(s.Index)
+		for len(n) < 6 {
+			n = "0" + n
+		}
+		names[k] = filepath.Join(dir, prefix+n+".ply")
+		if e := s.PLY.SaveWithOptions(names[k], opts); e != nil {
+			return names[:k], e
+		}
+	}
+	return names, nil
+}
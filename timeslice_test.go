@@ -0,0 +1,70 @@
+package ply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitByTime(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 6
+property float x
+property float y
+property float z
+property uchar intensity
+property double gps_time
+end_header
+0 0 0 1 10.0
+1 0 0 2 10.4
+2 0 0 3 11.2
+3 0 0 4 13.5
+4 0 0 5 10.9
+5 0 0 6 nan
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	slices, e := p.SplitByTime("gps_time", 1)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(slices) != 3 || slices[0].Index != 0 || slices[1].Index != 1 || slices[2].Index != 3 {
+		t.Fatalf("unexpected slices %v", slices)
+	}
+	if slices[2].Start != 13 || slices[2].End != 14 {
+		t.Errorf("unexpected window [%v, %v)", slices[2].Start, slices[2].End)
+	}
+	first := slices[0].PLY.findElement("vertex")
+	if first.Size != 3 || first.findProperty("intensity").float64At(2) != 5 ||
+		first.findProperty("gps_time").float64At(1) != 10.4 {
+		t.Error("expected the first window to keep vertices 0, 1 and 4 with their attributes")
+	}
+	if _, e := p.SplitByTime("gps_time", 0); e == nil {
+		t.Error("expected an error for a zero window")
+	}
+	if _, e := p.SplitByTime("time", 1); e == nil {
+		t.Error("expected an error for a missing property")
+	}
+
+	dir, e := ioutil.TempDir("", "ply")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	names, e := SaveTimeSlices(slices, dir, "frame_", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(names) != 3 || names[2] != filepath.Join(dir, "frame_000003.ply") {
+		t.Fatalf("unexpected names %v", names)
+	}
+	q := new(PLY)
+	if e := q.Load(names[2]); e != nil || q.VerticesCount() != 1 {
+		t.Error("expected the last frame to hold one vertex", e)
+	}
+}
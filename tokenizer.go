@@ -0,0 +1,78 @@
+package ply
+
+import "strings"
+
+// Tokenizer splits one line of ASCII element data into value tokens. The
+// tokens are handed to strconv, so a custom tokenizer may also rewrite them
+// (e.g. "1,5" to "1.5") for exporters with unusual numeric formats.
+type Tokenizer func(line string) ([]string, error)
+
+// TokenError reports an invalid token. Tokenizers may return it so that
+// the resulting *DataError names the offending property.
+type TokenError struct {
+	// Index is the 0-based position of Token among the line's
+	// whitespace-separated fields.
+	Index int
+	Token string
+}
+
+func (e *TokenError) Error() string {
+	return "Invalid number \"" + e.Token + "\""
+}
+
+// DefaultTokenizer splits on whitespace and accepts signed decimal numbers
+// with optional fraction and exponent, as well as inf, infinity and nan.
+func DefaultTokenizer(line string) ([]string, error) {
+	words := strings.Fields(line)
+	for i, w := range words {
+		if !isNumber(w) {
+			return nil, &TokenError{Index: i, Token: w}
+		}
+	}
+	return words, nil
+}
+
+func isNumber(s string) bool {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	switch strings.ToLower(s[i:]) {
+	case "inf", "infinity", "nan":
+		return true
+	}
+	digits := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && isDigit(s[i]) {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		exp := 0
+		for i < len(s) && isDigit(s[i]) {
+			i++
+			exp++
+		}
+		if exp == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
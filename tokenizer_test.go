@@ -0,0 +1,74 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDefaultTokenizer(t *testing.T) {
+	valid := []string{"1", "-1", "+2", "1.5e-03", "-.5", "3.", "1E+10", "inf", "-Inf", "NaN", "Infinity"}
+	for _, v := range valid {
+		words, e := DefaultTokenizer(v)
+		if e != nil || len(words) != 1 || words[0] != v {
+			t.Errorf("%q: got %v, %v", v, words, e)
+		}
+	}
+	invalid := []string{"1e", "e5", "--1", ".", "1.2.3", "abc", "+"}
+	for _, v := range invalid {
+		if _, e := DefaultTokenizer(v); e == nil {
+			t.Errorf("%q: expected error", v)
+		}
+	}
+}
+
+func TestReadASCIIScientific(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 2
+property float x
+property float y
+property float z
+property uchar red
+
+1.5e-03 -2.0E+1 nan 3.0
+inf -.25 1e-2 255
+`
+	src = strings.Replace(src, "\n\n", "\nend_header\n", 1)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	vs := p.ReadVertices()
+	if vs[0][0] != float32(1.5e-03) || vs[1][0] != -20 || vs[1][1] != -0.25 {
+		t.Errorf("unexpected vertices %v", vs)
+	}
+	if !math.IsInf(float64(vs[0][1]), 1) {
+		t.Errorf("expected +Inf, got %v", vs[0][1])
+	}
+	if !math.IsNaN(float64(vs[2][0])) {
+		t.Errorf("expected NaN, got %v", vs[2][0])
+	}
+	red := p.Elements[0].Properties[3].Data
+	if red[0][0] != 3 || red[1][0] != 255 {
+		t.Errorf("unexpected red %v", red)
+	}
+}
+
+func TestReadASCIICustomTokenizer(t *testing.T) {
+	src := "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nproperty float y\nend_header\n1,5;2,25\n"
+	opts := &LoadOptions{Tokenizer: func(line string) ([]string, error) {
+		return DefaultTokenizer(strings.NewReplacer(",", ".", ";", " ").Replace(line))
+	}}
+	p := new(PLY)
+	if e := p.ReadWithOptions(strings.NewReader(src), opts); e != nil {
+		t.Fatal(e)
+	}
+	if e := new(PLY).Read(strings.NewReader(src)); e == nil {
+		t.Error("expected default tokenizer to reject comma decimals")
+	}
+	x := p.Elements[0].Properties[0].Data[0]
+	if x[3] != 0x3f || x[2] != 0xc0 {
+		t.Errorf("unexpected x bytes %v", x)
+	}
+}
@@ -0,0 +1,158 @@
+package ply
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// TrajectoryElementNames are the element names recognized as scanner
+// trajectories, in order of preference.
+var TrajectoryElementNames []string = []string{"trajectory", "scanner_position"}
+
+var timeNames = []string{"gps_time", "time", "timestamp"}
+
+// Pose is a scanner position at a point in time. Orientation is a unit
+// quaternion (w, x, y, z).
+type Pose struct {
+	Time        float64
+	Position    [3]float64
+	Orientation [4]float64
+}
+
+// Trajectory decodes the trajectory element. Time is read from gps_time,
+// time or timestamp, the position from x, y, z and the orientation from
+// qw, qx, qy, qz or, failing that, roll, pitch, yaw in radians. A missing
+// orientation yields the identity quaternion.
+func (p *PLY) Trajectory() ([]Pose, error) {
+	var elem *Element
+	for _, name := range TrajectoryElementNames {
+		if elem = p.findElement(name); elem != nil {
+			break
+		}
+	}
+	if elem == nil {
+		return nil, errors.New("No trajectory element")
+	}
+	t := elem.firstScalarProperty(timeNames...)
+	pos := elem.scalarProperties("x", "y", "z")
+	if t == nil || pos == nil {
+		return nil, errors.New("Trajectory element needs time and x, y, z properties")
+	}
+	quat := elem.scalarProperties("qw", "qx", "qy", "qz")
+	euler := elem.scalarProperties("roll", "pitch", "yaw")
+	poses := make([]Pose, elem.Size)
+	for i := range poses {
+		poses[i].Time = t.float64At(i)
+		for j := 0; j < 3; j++ {
+			poses[i].Position[j] = pos[j].float64At(i)
+		}
+		switch {
+		case quat != nil:
+			for j := 0; j < 4; j++ {
+				poses[i].Orientation[j] = quat[j].float64At(i)
+			}
+		case euler != nil:
+			poses[i].Orientation = eulerToQuaternion(euler[0].float64At(i), euler[1].float64At(i), euler[2].float64At(i))
+		default:
+			poses[i].Orientation = [4]float64{1, 0, 0, 0}
+		}
+	}
+	return poses, nil
+}
+
+// SetTrajectory replaces the trajectory element with poses, stored as
+// double time, x, y, z, qw, qx, qy, qz.
+func (p *PLY) SetTrajectory(poses []Pose) {
+	if p.frozen {
+		panic(ErrFrozen)
+	}
+	elem := &Element{Name: TrajectoryElementNames[0], Size: len(poses)}
+	names := []string{"time", "x", "y", "z", "qw", "qx", "qy", "qz"}
+	for j, name := range names {
+		prop := newProperty(name, "double", len(poses))
+		prop.pos = j
+		elem.Properties = append(elem.Properties, prop)
+	}
+	for i, pose := range poses {
+		values := []float64{pose.Time, pose.Position[0], pose.Position[1], pose.Position[2],
+			pose.Orientation[0], pose.Orientation[1], pose.Orientation[2], pose.Orientation[3]}
+		for j, v := range values {
+			elem.Properties[j].setFloat64At(i, v)
+		}
+	}
+	for k, e := range p.Elements {
+		for _, name := range TrajectoryElementNames {
+			if e.Name == name {
+				p.Elements[k] = elem
+				return
+			}
+		}
+	}
+	p.Elements = append(p.Elements, elem)
+}
+
+// NearestPoses returns, for every vertex, the index of the trajectory pose
+// closest in time to the vertex gps_time (or time, timestamp), or -1 for
+// vertices with an invalid time.
+func (p *PLY) NearestPoses() ([]int, error) {
+	poses, e := p.Trajectory()
+	if e != nil {
+		return nil, e
+	}
+	if len(poses) == 0 {
+		return nil, errors.New("Empty trajectory")
+	}
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return nil, errors.New("No vertex element")
+	}
+	t := vertex.firstScalarProperty(timeNames...)
+	if t == nil {
+		return nil, errors.New("Vertex element has no time property")
+	}
+	order := make([]int, len(poses))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return poses[order[a]].Time < poses[order[b]].Time })
+	nearest := make([]int, vertex.Size)
+	for i := range nearest {
+		v := t.float64At(i)
+		if math.IsNaN(v) {
+			nearest[i] = -1
+			continue
+		}
+		k := sort.Search(len(order), func(k int) bool { return poses[order[k]].Time >= v })
+		switch {
+		case k == len(order):
+			k--
+		case k > 0 && v-poses[order[k-1]].Time <= poses[order[k]].Time-v:
+			k--
+		}
+		nearest[i] = order[k]
+	}
+	return nearest, nil
+}
+
+func (e *Element) firstScalarProperty(names ...string) *Property {
+	for _, name := range names {
+		if prop := e.findProperty(name); prop != nil && !prop.IsList {
+			return prop
+		}
+	}
+	return nil
+}
+
+// eulerToQuaternion converts roll, pitch and yaw applied in z, y, x order.
+func eulerToQuaternion(roll, pitch, yaw float64) [4]float64 {
+	cr, sr := math.Cos(roll/2), math.Sin(roll/2)
+	cp, sp := math.Cos(pitch/2), math.Sin(pitch/2)
+	cy, sy := math.Cos(yaw/2), math.Sin(yaw/2)
+	return [4]float64{
+		cr*cp*cy + sr*sp*sy,
+		sr*cp*cy - cr*sp*sy,
+		cr*sp*cy + sr*cp*sy,
+		cr*cp*sy - sr*sp*cy,
+	}
+}
@@ -0,0 +1,59 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTrajectory(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 4
+property float x
+property float y
+property float z
+property double gps_time
+element scanner_position 3
+property double time
+property double x
+property double y
+property double z
+property float yaw
+property float pitch
+property float roll
+end_header
+0 0 0 0.9
+1 0 0 10.4
+2 0 0 5.6
+3 0 0 -3
+10 0 0 0 0 0 0
+0 1 2 3 0 0 0
+5 4 5 6 3.14159265 0 0
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	poses, e := p.Trajectory()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(poses) != 3 || poses[1].Position != [3]float64{1, 2, 3} || poses[0].Orientation != [4]float64{1, 0, 0, 0} {
+		t.Errorf("unexpected poses %v", poses)
+	}
+	if math.Abs(poses[2].Orientation[3]-1) > 1e-6 {
+		t.Errorf("expected 180° yaw quaternion, got %v", poses[2].Orientation)
+	}
+	nearest, e := p.NearestPoses()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if nearest[0] != 1 || nearest[1] != 0 || nearest[2] != 2 || nearest[3] != 1 {
+		t.Errorf("unexpected association %v", nearest)
+	}
+	p.SetTrajectory(poses[:1])
+	if len(p.Elements) != 2 || p.Elements[1].Name != "trajectory" || p.Elements[1].Size != 1 {
+		t.Error("SetTrajectory did not replace the element")
+	}
+}
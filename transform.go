@@ -0,0 +1,117 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// Transform applies the affine transform m, in row-major order and acting
+// on column vectors, to x, y and z in place. nx, ny and nz, if present, are
+// transformed by the inverse transpose of m's linear part and
+// renormalized. Values are stored back in their declared types. The
+// bottom row of m is ignored. Transforms that mirror space also reverse
+// the corner order of every face so that windings stay consistent with
+// the normals.
+func (p *PLY) Transform(m [4][4]float64) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	pos, e := p.Positions()
+	if e != nil {
+		return e
+	}
+	if det3(m) < 0 {
+		p.reverseFaces()
+	}
+	for i, v := range pos {
+		for j := 0; j < 3; j++ {
+			pos[i][j] = m[j][0]*v[0] + m[j][1]*v[1] + m[j][2]*v[2] + m[j][3]
+		}
+	}
+	if e = p.SetPositions(pos); e != nil {
+		return e
+	}
+	normals, e := p.Normals()
+	if e != nil {
+		// no normals to rotate
+		return nil
+	}
+	c := normalMatrix(m)
+	for i, n := range normals {
+		var t [3]float64
+		for j := 0; j < 3; j++ {
+			t[j] = c[j][0]*n[0] + c[j][1]*n[1] + c[j][2]*n[2]
+		}
+		normals[i] = normalize3(t)
+	}
+	return p.SetNormals(normals)
+}
+
+// normalMatrix returns the cofactor matrix of m's linear part, which is
+// the inverse transpose scaled by the determinant, with the determinant's
+// sign divided out so that it matches the inverse transpose up to a
+// positive factor. Unlike the inverse it exists for singular transforms.
+func normalMatrix(m [4][4]float64) [3][3]float64 {
+	var c [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			i1, i2 := (i+1)%3, (i+2)%3
+			j1, j2 := (j+1)%3, (j+2)%3
+			c[i][j] = m[i1][j1]*m[i2][j2] - m[i1][j2]*m[i2][j1]
+		}
+	}
+	if math.Signbit(det3(m)) {
+		for i := range c {
+			c[i] = scale3(c[i], -1)
+		}
+	}
+	return c
+}
+
+// det3 returns the determinant of m's linear part.
+func det3(m [4][4]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// reverseFaces flips the winding of every face.
+func (p *PLY) reverseFaces() {
+	face := p.findElement("face")
+	if face == nil {
+		return
+	}
+	idx := p.faceIndexProperty(face)
+	if idx == nil {
+		return
+	}
+	for i := 0; i < face.Size; i++ {
+		values := idx.listFloat64At(i)
+		for a, b := 0, len(values)-1; a < b; a, b = a+1, b-1 {
+			values[a], values[b] = values[b], values[a]
+		}
+		idx.setListFloat64At(i, values)
+	}
+}
+
+// SwapYZ exchanges the y and z axes, converting between Y-up and Z-up
+// conventions.
+func (p *PLY) SwapYZ() error {
+	return p.Transform([4][4]float64{{1, 0, 0, 0}, {0, 0, 1, 0}, {0, 1, 0, 0}, {0, 0, 0, 1}})
+}
+
+// FlipAxis negates axis 0, 1 or 2 (x, y or z).
+func (p *PLY) FlipAxis(axis int) error {
+	if axis < 0 || axis > 2 {
+		return errors.New("Invalid axis " + itoa(axis))
+	}
+	m := [4][4]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+	m[axis][axis] = -1
+	return p.Transform(m)
+}
+
+// ScaleUnits multiplies all positions by factor, e.g. 0.001 to convert
+// millimetres to metres.
+func (p *PLY) ScaleUnits(factor float64) error {
+	return p.Transform([4][4]float64{{factor, 0, 0, 0}, {0, factor, 0, 0}, {0, 0, factor, 0}, {0, 0, 0, 1}})
+}
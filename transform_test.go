@@ -0,0 +1,93 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 2
+property float x
+property float y
+property float z
+property float nx
+property float ny
+property float nz
+end_header
+1 0 0 1 0 0
+0 1 0 0 1 1
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	// scale z by 2, rotate 90 degrees about z and translate by (10, 0, 0)
+	m := [4][4]float64{
+		{0, -1, 0, 10},
+		{1, 0, 0, 0},
+		{0, 0, 2, 0},
+		{0, 0, 0, 1},
+	}
+	if e := p.Transform(m); e != nil {
+		t.Fatal(e)
+	}
+	pos, _ := p.Positions()
+	if pos[0] != [3]float64{10, 1, 0} || pos[1] != [3]float64{9, 0, 0} {
+		t.Errorf("unexpected positions %v", pos)
+	}
+	n, _ := p.Normals()
+	if n[0] != [3]float64{0, 1, 0} {
+		t.Errorf("unexpected normal %v", n[0])
+	}
+	// the stretched z axis flattens the tilted normal towards the xy plane
+	want := normalize3([3]float64{-1, 0, 0.5})
+	for j := range want {
+		if math.Abs(n[1][j]-want[j]) > 1e-6 {
+			t.Errorf("expected normal %v, got %v", want, n[1])
+			break
+		}
+	}
+	mirror := [4][4]float64{{-1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+	if e := p.Transform(mirror); e != nil {
+		t.Fatal(e)
+	}
+	if n, _ := p.Normals(); n[0] != [3]float64{0, 1, 0} {
+		t.Errorf("expected mirroring in x to keep a y normal, got %v", n[0])
+	}
+}
+
+func TestAxisHelpers(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.SwapYZ(); e != nil {
+		t.Fatal(e)
+	}
+	pos, _ := p.Positions()
+	if pos[2] != [3]float64{1, 0, 1} {
+		t.Errorf("unexpected swapped position %v", pos[2])
+	}
+	if faces := p.ReadFaces(); faces[0][0] != 2 || faces[0][2] != 0 {
+		t.Errorf("expected mirroring to reverse windings, got %v", faces)
+	}
+	if e := p.FlipAxis(0); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.ScaleUnits(1000); e != nil {
+		t.Fatal(e)
+	}
+	pos, _ = p.Positions()
+	if pos[2] != [3]float64{-1000, 0, 1000} {
+		t.Errorf("unexpected position %v", pos[2])
+	}
+	if faces := p.ReadFaces(); faces[0][0] != 0 {
+		t.Errorf("expected a second mirroring to restore windings, got %v", faces)
+	}
+	if e := p.FlipAxis(3); e == nil {
+		t.Error("expected an error for an invalid axis")
+	}
+}
@@ -0,0 +1,170 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// Triangulation methods for Triangulate.
+const (
+	FanTriangulation = iota
+	EarClipping
+)
+
+// Triangulate splits every face with more than three vertices into
+// triangles, replacing the face element's rows in place. Other face
+// properties are copied to each resulting triangle, except texcoord lists
+// holding a pair per corner, which are split along. Ear clipping handles
+// concave polygons and falls back to a fan when the polygon is degenerate.
+func (p *PLY) Triangulate(method int) error {
+	if p.frozen {
+		return ErrFrozen
+	}
+	elem := p.findElement("face")
+	if elem == nil {
+		return errors.New("No face element")
+	}
+	idx := p.faceIndexProperty(elem)
+	if idx == nil {
+		return errors.New("Face element has no vertex index list")
+	}
+	var pos [][3]float64
+	if method == EarClipping {
+		var e error
+		if pos, e = p.vertexPositions(); e != nil {
+			return e
+		}
+	}
+	tex := elem.findProperty(TexcoordName)
+	if tex != nil && !tex.IsList {
+		tex = nil
+	}
+	var rows []int
+	data := newListColumn(idx)
+	var texData *listColumn
+	if tex != nil {
+		texData = newListColumn(tex)
+	}
+	for i := 0; i < elem.Size; i++ {
+		face := idx.listIntsAt(i)
+		if len(face) <= 3 {
+			rows = append(rows, i)
+			data.addRow(idx.row(i))
+			if tex != nil {
+				texData.addRow(tex.row(i))
+			}
+			continue
+		}
+		var tris [][3]int
+		if method == EarClipping {
+			tris = earClip(face, pos)
+		}
+		if tris == nil {
+			tris = fanTriangles(face)
+		}
+		var uv []float64
+		if tex != nil {
+			uv = tex.listFloat64At(i)
+		}
+		for _, t := range tris {
+			rows = append(rows, i)
+			data.add([]float64{float64(t[0]), float64(t[1]), float64(t[2])})
+			if tex != nil && len(uv) != 2*len(face) {
+				texData.addRow(tex.row(i))
+			} else if tex != nil {
+				var corners []float64
+				for _, v := range t {
+					k := cornerOf(face, v)
+					corners = append(corners, uv[2*k], uv[2*k+1])
+				}
+				texData.add(corners)
+			}
+		}
+	}
+	sub := elem.selectRows(rows)
+	for _, prop := range sub.Properties {
+		switch {
+		case prop.Name == idx.Name:
+			prop.Data = data.rows()
+		case tex != nil && prop.Name == tex.Name:
+			prop.Data = texData.rows()
+		}
+	}
+	elem.Properties = sub.Properties
+	elem.Size = sub.Size
+	return nil
+}
+
+// cornerOf returns the first corner of face at vertex v.
+func cornerOf(face []int, v int) int {
+	for k, w := range face {
+		if w == v {
+			return k
+		}
+	}
+	return 0
+}
+
+// earClip triangulates a simple polygon by projecting it onto the plane of
+// its Newell normal. It returns nil if the polygon cannot be clipped.
+func earClip(face []int, pos [][3]float64) [][3]int {
+	for _, v := range face {
+		if v < 0 || v >= len(pos) {
+			return nil
+		}
+	}
+	n := newellNormal(face, pos)
+	if n[0] == 0 && n[1] == 0 && n[2] == 0 {
+		return nil
+	}
+	// drop the dominant axis; sign makes convex corners positive
+	u, w, sign := 1, 2, n[0]
+	switch {
+	case math.Abs(n[1]) >= math.Abs(n[0]) && math.Abs(n[1]) >= math.Abs(n[2]):
+		u, w, sign = 2, 0, n[1]
+	case math.Abs(n[2]) >= math.Abs(n[0]) && math.Abs(n[2]) >= math.Abs(n[1]):
+		u, w, sign = 0, 1, n[2]
+	}
+	pt := func(k int) [2]float64 { return [2]float64{pos[face[k]][u], pos[face[k]][w]} }
+	cross := func(a, b, c [2]float64) float64 {
+		return math.Copysign(1, sign) * ((b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0]))
+	}
+	remaining := make([]int, len(face))
+	for k := range remaining {
+		remaining[k] = k
+	}
+	tris := make([][3]int, 0, len(face)-2)
+	for len(remaining) > 3 {
+		clipped := false
+		for k := range remaining {
+			m := len(remaining)
+			i0, i1, i2 := remaining[(k+m-1)%m], remaining[k], remaining[(k+1)%m]
+			a, b, c := pt(i0), pt(i1), pt(i2)
+			if cross(a, b, c) <= 0 {
+				continue
+			}
+			ear := true
+			for _, j := range remaining {
+				if j == i0 || j == i1 || j == i2 {
+					continue
+				}
+				q := pt(j)
+				if cross(a, b, q) >= 0 && cross(b, c, q) >= 0 && cross(c, a, q) >= 0 {
+					ear = false
+					break
+				}
+			}
+			if !ear {
+				continue
+			}
+			tris = append(tris, [3]int{face[i0], face[i1], face[i2]})
+			remaining = append(remaining[:k], remaining[k+1:]...)
+			clipped = true
+			break
+		}
+		if !clipped {
+			return nil
+		}
+	}
+	return append(tris, [3]int{face[remaining[0]], face[remaining[1]], face[remaining[2]]})
+}
@@ -0,0 +1,69 @@
+package ply
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTriangulateFan(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.Triangulate(FanTriangulation); e != nil {
+		t.Fatal(e)
+	}
+	faces := p.ReadFaces()
+	if len(faces) != 3 || len(faces[1]) != 3 || faces[2][0] != 0 || faces[2][2] != 3 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	flags := p.Elements[1].findProperty("flags")
+	if flags.float64At(0) != -7 || flags.float64At(1) != 300 || flags.float64At(2) != 300 {
+		t.Error("expected face properties to be copied to each triangle")
+	}
+}
+
+func TestTriangulateEarClipping(t *testing.T) {
+	// a concave arrow whose fan from vertex 0 leaves the polygon
+	src := `ply
+format ascii 1.0
+element vertex 5
+property float x
+property float y
+property float z
+element face 1
+property list uchar int vertex_indices
+end_header
+0 2 0
+0 0 0
+2 0 0
+2 2 0
+1 1 0
+5 0 1 2 3 4
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	if e := p.Triangulate(EarClipping); e != nil {
+		t.Fatal(e)
+	}
+	pos, _ := p.vertexPositions()
+	faces := p.ReadFaces()
+	if len(faces) != 3 {
+		t.Fatalf("expected 3 triangles, got %v", faces)
+	}
+	area := 0.0
+	for _, f := range faces {
+		n := cross3(sub3(pos[f[1]], pos[f[0]]), sub3(pos[f[2]], pos[f[0]]))
+		if n[2] <= 0 {
+			t.Errorf("triangle %v flips the polygon's winding", f)
+		}
+		area += length3(n) / 2
+	}
+	// the 2x2 square minus the notch cut by vertex 4
+	if math.Abs(area-3) > 1e-9 {
+		t.Errorf("expected area 3, got %v", area)
+	}
+}
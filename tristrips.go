@@ -0,0 +1,79 @@
+package ply
+
+import "errors"
+
+// ReadTristrips expands the strips of the tristrips element into
+// triangles. A -1 index starts a new strip; every other triangle is
+// reversed to keep the winding consistent, and triangles repeating a
+// vertex, used to join strips, are skipped.
+func (p *PLY) ReadTristrips() ([][3]int, error) {
+	elem := p.findElement("tristrips")
+	if elem == nil {
+		return nil, errors.New("No tristrips element")
+	}
+	idx := p.faceIndexProperty(elem)
+	if idx == nil {
+		return nil, errors.New("Tristrips element has no vertex index list")
+	}
+	var triangles [][3]int
+	for i := 0; i < elem.Size; i++ {
+		var strip []int
+		for _, v := range append(idx.listIntsAt(i), -1) {
+			if v >= 0 {
+				strip = append(strip, v)
+				continue
+			}
+			for k := 0; k+2 < len(strip); k++ {
+				t := [3]int{strip[k], strip[k+1], strip[k+2]}
+				if k%2 == 1 {
+					t[0], t[1] = t[1], t[0]
+				}
+				if t[0] != t[1] && t[1] != t[2] && t[0] != t[2] {
+					triangles = append(triangles, t)
+				}
+			}
+			strip = strip[:0]
+		}
+	}
+	return triangles, nil
+}
+
+// ExpandTristrips replaces the tristrips element by its triangles, see
+// ReadTristrips. They are appended to the face element, which is created
+// in place of the strips when missing. It returns the number of
+// triangles.
+func (p *PLY) ExpandTristrips() (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	triangles, e := p.ReadTristrips()
+	if e != nil {
+		return 0, e
+	}
+	face := p.findElement("face")
+	created := face == nil
+	if created {
+		face = &Element{Name: "face"}
+		face.Properties = []*Property{newListProperty("vertex_indices", "uchar", "int", 0)}
+	}
+	idx := p.faceIndexProperty(face)
+	if idx == nil {
+		return 0, errors.New("Face element has no vertex index list")
+	}
+	for _, t := range triangles {
+		if e := face.AppendRow(map[string]interface{}{idx.Name: []int{t[0], t[1], t[2]}}); e != nil {
+			return 0, e
+		}
+	}
+	var elements []*Element
+	for _, elem := range p.Elements {
+		switch {
+		case elem.Name == "tristrips" && created:
+			elements = append(elements, face)
+		case elem.Name != "tristrips":
+			elements = append(elements, elem)
+		}
+	}
+	p.Elements = elements
+	return len(triangles), nil
+}
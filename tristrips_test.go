@@ -0,0 +1,51 @@
+package ply
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTristrips(t *testing.T) {
+	src := `ply
+format ascii 1.0
+element vertex 6
+property float x
+property float y
+property float z
+element tristrips 1
+property list int int vertex_indices
+end_header
+0 0 0
+1 0 0
+0 1 0
+1 1 0
+0 2 0
+1 2 0
+9 0 1 2 3 3 -1 2 3 4
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	triangles, e := p.ReadTristrips()
+	if e != nil {
+		t.Fatal(e)
+	}
+	want := [][3]int{{0, 1, 2}, {2, 1, 3}, {2, 3, 4}}
+	if !reflect.DeepEqual(triangles, want) {
+		t.Errorf("got %v, want %v", triangles, want)
+	}
+	n, e := p.ExpandTristrips()
+	if e != nil {
+		t.Fatal(e)
+	}
+	faces := p.ReadFaces()
+	if n != 3 || len(faces) != 3 || !reflect.DeepEqual(faces[1], []int{2, 1, 3}) ||
+		p.findElement("tristrips") != nil || p.Elements[1].Name != "face" {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if _, e := p.ExpandTristrips(); e == nil {
+		t.Error("expected an error without tristrips")
+	}
+}
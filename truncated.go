@@ -0,0 +1,53 @@
+package ply
+
+// TruncatedError reports a body ending before all declared rows were read.
+type TruncatedError struct {
+	Filename string
+	Element  string
+	// Row is the first incomplete row.
+	Row int
+	// Property is where the data ran out, when known.
+	Property string
+}
+
+func (e *TruncatedError) Error() string {
+	msg := "Unexpected end of " + e.Element + " data in " + e.Filename + " at row " + itoa(e.Row)
+	if e.Property != "" {
+		msg += " property " + e.Property
+	}
+	return msg
+}
+
+func (p *PLY) unexpectedEnd(elem *Element, row int, prop string) error {
+	return &TruncatedError{Filename: p.filename, Element: elem.Name, Row: row, Property: prop}
+}
+
+// keepCompleteRows cuts the element whose decoding stopped early to its
+// complete rows and empties the elements after it.
+func (p *PLY) keepCompleteRows() {
+	found := false
+	for _, elem := range p.Elements {
+		if found {
+			for _, prop := range elem.Properties {
+				prop.Data, prop.block = nil, nil
+			}
+			elem.Size = 0
+			continue
+		}
+		n := elem.Size
+		for _, prop := range elem.Properties {
+			prop.load()
+			if len(prop.Data) < n {
+				n = len(prop.Data)
+			}
+		}
+		if n == elem.Size {
+			continue
+		}
+		found = true
+		for _, prop := range elem.Properties {
+			prop.Data = prop.Data[:n]
+		}
+		elem.Size = n
+	}
+}
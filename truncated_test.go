@@ -0,0 +1,101 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTruncatedBody(t *testing.T) {
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(testASCIIMesh)); e != nil {
+		t.Fatal(e)
+	}
+	p.FileType = BinaryLittleEndian
+	var out bytes.Buffer
+	if e := p.Write(&out); e != nil {
+		t.Fatal(e)
+	}
+	src := out.Bytes()
+	body := bytes.Index(src, []byte("end_header\n")) + 11
+
+	// vertices are 20 bytes: x, y, z floats and a double quality
+	cut := src[:body+2*20+14]
+	q := new(PLY)
+	e := q.Read(bytes.NewReader(cut))
+	te, ok := e.(*TruncatedError)
+	if !ok || te.Element != "vertex" || te.Row != 2 || te.Property != "quality" {
+		t.Fatalf("unexpected error %v", e)
+	}
+	if !strings.HasSuffix(e.Error(), "at row 2 property quality") {
+		t.Errorf("unexpected message %q", e.Error())
+	}
+
+	q = new(PLY)
+	e = q.ReadWithOptions(bytes.NewReader(cut), &LoadOptions{KeepTruncatedRows: true})
+	if _, ok := e.(*TruncatedError); !ok {
+		t.Fatalf("expected the error to be returned, got %v", e)
+	}
+	if q.VerticesCount() != 2 || q.findElement("face").Size != 0 || q.ReadVertices()[0][1] != 1 {
+		t.Errorf("expected the two complete vertices, got %d", q.VerticesCount())
+	}
+
+	// the quad is cut, the triangle references loaded vertices only
+	q = new(PLY)
+	e = q.ReadWithOptions(bytes.NewReader(src[:len(src)-3]), &LoadOptions{KeepTruncatedRows: true})
+	if te, ok := e.(*TruncatedError); !ok || te.Element != "face" || te.Row != 1 {
+		t.Fatalf("unexpected error %v", e)
+	}
+	if faces := q.ReadFaces(); q.VerticesCount() != 4 || len(faces) != 1 {
+		t.Errorf("expected one complete face, got %v", faces)
+	}
+
+	q = new(PLY)
+	e = q.ReadWithOptions(bytes.NewReader(cut), &LoadOptions{MaxRows: 1, KeepTruncatedRows: true})
+	if _, ok := e.(*TruncatedError); !ok || q.VerticesCount() != 1 {
+		t.Errorf("expected the skipped rows to be reported, got %v", e)
+	}
+}
+
+func TestTruncatedASCIIBody(t *testing.T) {
+	cut := testASCIIMesh[:strings.Index(testASCIIMesh, "1 1 0 -1e-05")+5]
+	p := new(PLY)
+	e := p.Read(strings.NewReader(cut))
+	if te, ok := e.(*TruncatedError); !ok || te.Element != "vertex" || te.Row != 2 || te.Property != "quality" {
+		t.Fatalf("unexpected error %v", e)
+	}
+	for _, opts := range []*LoadOptions{{KeepTruncatedRows: true}, {RecoverPartial: true}} {
+		p = new(PLY)
+		p.ReadWithOptions(strings.NewReader(cut), opts)
+		vertex := p.GetVertices()
+		for _, prop := range vertex.Properties {
+			if vertex.Size != 2 || len(prop.Data) != vertex.Size {
+				t.Errorf("%+v: expected two complete vertices, got %d rows of %s for size %d",
+					*opts, len(prop.Data), prop.Name, vertex.Size)
+			}
+		}
+	}
+	// a complete last row needs no newline
+	p = new(PLY)
+	if e := p.Read(strings.NewReader(strings.TrimSuffix(testASCIIMesh, "\n"))); e != nil {
+		t.Error(e)
+	}
+}
+
+func TestRecoverPartial(t *testing.T) {
+	src := strings.Replace(testASCIIMesh, "1 1 0 -1e-05", "1 1 zero -1e-05", 1)
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e == nil {
+		t.Fatal("expected an error without recovery")
+	}
+	p = new(PLY)
+	if e := p.ReadWithOptions(strings.NewReader(src), &LoadOptions{RecoverPartial: true}); e != nil {
+		t.Fatal(e)
+	}
+	if len(p.LoadErrors) != 1 || p.VerticesCount() != 2 || len(p.ReadFaces()) != 0 {
+		t.Errorf("expected two vertices and no faces, got %d %v", p.VerticesCount(), p.LoadErrors)
+	}
+	if de, ok := p.LoadErrors[0].(*DataError); !ok || de.Row != 2 || de.Property != "z" {
+		t.Errorf("unexpected load error %v", p.LoadErrors[0])
+	}
+}
@@ -0,0 +1,263 @@
+package ply
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+func (p *PLY) findElement(name string) *Element {
+	for _, elem := range p.Elements {
+		if elem.Name == name {
+			return elem
+		}
+	}
+	return nil
+}
+
+func (e *Element) findProperty(name string) *Property {
+	for _, prop := range e.Properties {
+		if prop.Name == name {
+			prop.load()
+			return prop
+		}
+	}
+	return nil
+}
+
+func scalarFloat64(b []byte, typeName string, order binary.ByteOrder) float64 {
+	switch typeName {
+	case "int8", "char":
+		return float64(int8(b[0]))
+	case "int16", "short":
+		return float64(int16(order.Uint16(b)))
+	case "int32", "int":
+		return float64(int32(order.Uint32(b)))
+	case "uint8", "uchar":
+		return float64(b[0])
+	case "uint16", "ushort":
+		return float64(order.Uint16(b))
+	case "uint32", "uint":
+		return float64(order.Uint32(b))
+	case "float32", "float":
+		return float64(math.Float32frombits(order.Uint32(b)))
+	case "float64", "double":
+		return math.Float64frombits(order.Uint64(b))
+	}
+	if c := codecs[typeName]; c != nil {
+		return c.Decode(b, order)
+	}
+	return math.NaN()
+}
+
+// row returns the encoded data of row i, or nil when it is missing. Reads
+// of property data go through row so that the storage behind Data can
+// change without affecting users of the exported API.
+func (p *Property) row(i int) []byte {
+	if p.block != nil {
+		p.load()
+	}
+	if i < 0 || i >= len(p.Data) {
+		return nil
+	}
+	return p.Data[i]
+}
+
+// scalarRow returns row i when it holds exactly one value of the property
+// type, nil otherwise.
+func (p *Property) scalarRow(i int) []byte {
+	b := p.row(i)
+	if p.IsList || len(b) != SizeOfType[p.Type] || len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+// float64At decodes row i of a scalar property, NaN if it is missing.
+func (p *Property) float64At(i int) float64 {
+	b := p.scalarRow(i)
+	if b == nil {
+		return math.NaN()
+	}
+	return scalarFloat64(b, p.Type, p.byteOrder())
+}
+
+// listFloat64At decodes the items of row i of a list property.
+func (p *Property) listFloat64At(i int) []float64 {
+	size := SizeOfType[p.Type]
+	data := p.row(i)
+	if size == 0 {
+		return nil
+	}
+	values := make([]float64, len(data)/size)
+	for j := range values {
+		values[j] = scalarFloat64(data[j*size:(j+1)*size], p.Type, p.byteOrder())
+	}
+	return values
+}
+
+func (p *Property) listIntsAt(i int) []int {
+	values := p.listFloat64At(i)
+	ints := make([]int, len(values))
+	for j, v := range values {
+		ints[j] = int(v)
+	}
+	return ints
+}
+
+// faceIndexProperty finds the vertex index list of a face element by the
+// configured names, falling back to the element's only list property.
+func (p *PLY) faceIndexProperty(e *Element) *Property {
+	names := p.FaceIndexNames
+	if names == nil {
+		names = FaceIndexNames
+	}
+	for _, name := range names {
+		if prop := e.findProperty(name); prop != nil && prop.IsList {
+			return prop
+		}
+	}
+	var list *Property
+	for _, prop := range e.Properties {
+		if prop.IsList {
+			if list != nil {
+				return nil
+			}
+			list = prop
+		}
+	}
+	return list
+}
+
+func (p *PLY) vertexPositions() ([][3]float64, error) {
+	elem := p.findElement("vertex")
+	if elem == nil {
+		return nil, errors.New("No vertex element")
+	}
+	var props [3]*Property
+	for j, name := range []string{"x", "y", "z"} {
+		props[j] = elem.findProperty(name)
+		if props[j] == nil || props[j].IsList {
+			return nil, errors.New("Vertex element has no scalar " + name + " property")
+		}
+	}
+	pos := make([][3]float64, elem.Size)
+	for i := range pos {
+		for j := 0; j < 3; j++ {
+			pos[i][j] = props[j].float64At(i)
+		}
+	}
+	return pos, nil
+}
+
+func (p *PLY) faceIndices() ([][]int, error) {
+	elem := p.findElement("face")
+	if elem == nil {
+		return nil, errors.New("No face element")
+	}
+	prop := p.faceIndexProperty(elem)
+	if prop == nil {
+		return nil, errors.New("Face element has no vertex index list")
+	}
+	faces := make([][]int, elem.Size)
+	for i := range faces {
+		faces[i] = prop.listIntsAt(i)
+	}
+	return faces, nil
+}
+
+// encodeFloat64 encodes v as typeName, rounding to the nearest integer for
+// integral types.
+func encodeFloat64(v float64, typeName string, order binary.ByteOrder) []byte {
+	b := make([]byte, SizeOfType[typeName])
+	putFloat64(b, v, typeName, order)
+	return b
+}
+
+// putFloat64 stores v as typeName, clamping it to the type's range.
+func putFloat64(b []byte, v float64, typeName string, order binary.ByteOrder) {
+	v, _ = fitValue(v, typeName)
+	switch typeName {
+	case "int8", "char":
+		b[0] = byte(int8(v))
+	case "int16", "short":
+		order.PutUint16(b, uint16(int16(v)))
+	case "int32", "int":
+		order.PutUint32(b, uint32(int32(v)))
+	case "uint8", "uchar":
+		b[0] = uint8(v)
+	case "uint16", "ushort":
+		order.PutUint16(b, uint16(v))
+	case "uint32", "uint":
+		order.PutUint32(b, uint32(v))
+	case "float32", "float":
+		order.PutUint32(b, math.Float32bits(float32(v)))
+	case "float64", "double":
+		order.PutUint64(b, math.Float64bits(v))
+	default:
+		if c := codecs[typeName]; c != nil {
+			c.Encode(b, v, order)
+		}
+	}
+}
+
+func newProperty(name, typeName string, n int) *Property {
+	return &Property{Name: name, Type: typeName, Data: make([][]byte, n)}
+}
+
+func newListProperty(name, sizeType, typeName string, n int) *Property {
+	return &Property{Name: name, IsList: true, ListSizeType: sizeType, Type: typeName, Data: make([][]byte, n)}
+}
+
+func (p *Property) setFloat64At(i int, v float64) {
+	p.load()
+	if len(p.Data[i]) != SizeOfType[p.Type] {
+		p.Data[i] = make([]byte, SizeOfType[p.Type])
+	}
+	putFloat64(p.Data[i], v, p.Type, p.byteOrder())
+}
+
+func (p *Property) setListFloat64At(i int, values []float64) {
+	p.load()
+	p.Data[i] = p.encodeList(values)
+}
+
+func (p *Property) encodeList(values []float64) []byte {
+	size := SizeOfType[p.Type]
+	b := make([]byte, len(values)*size)
+	for j, v := range values {
+		putFloat64(b[j*size:(j+1)*size], v, p.Type, p.byteOrder())
+	}
+	return b
+}
+
+func (p *Property) setListIntsAt(i int, values []int) {
+	f := make([]float64, len(values))
+	for j, v := range values {
+		f[j] = float64(v)
+	}
+	p.setListFloat64At(i, f)
+}
+
+// faceElement builds a face element with a vertex_indices list, choosing
+// uchar counts unless a face has more than 255 vertices.
+func faceElement(faces [][]int) *Element {
+	sizeType := "uchar"
+	for _, f := range faces {
+		if len(f) > 255 {
+			sizeType = "int"
+			break
+		}
+	}
+	prop := newListProperty("vertex_indices", sizeType, "int", len(faces))
+	data := newListColumn(prop)
+	for _, f := range faces {
+		values := make([]float64, len(f))
+		for j, v := range f {
+			values[j] = float64(v)
+		}
+		data.add(values)
+	}
+	prop.Data = data.rows()
+	return &Element{Name: "face", Size: len(faces), Properties: []*Property{prop}}
+}
@@ -0,0 +1,76 @@
+package ply
+
+import "strings"
+
+// verbatimHeader returns the header lines p was loaded with, with element
+// counts updated to the current sizes, or false when the elements,
+// properties, format, comments or obj_info no longer match them.
+func (p *PLY) verbatimHeader() ([]string, bool) {
+	format, e := formatName(p.FileType)
+	if p.header == nil || e != nil {
+		return nil, false
+	}
+	lines := make([]string, len(p.header))
+	comments, objInfo := 0, 0
+	elem := -1
+	prop := 0
+	for n, line := range p.header {
+		lines[n] = line
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "format":
+			if len(words) < 2 || words[1] != format {
+				return nil, false
+			}
+		case "comment":
+			if comments >= len(p.Comments) ||
+				p.Comments[comments] != strip(strings.TrimPrefix(strip(line), "comment")) {
+				return nil, false
+			}
+			comments++
+		case "obj_info":
+			if len(words) < 2 {
+				return nil, false
+			}
+			key := words[1]
+			value, ok := p.ObjInfoItems[key]
+			trimmed := strip(line)
+			if !ok || value != strip(trimmed[strings.Index(trimmed, key)+len(key):]) {
+				return nil, false
+			}
+			objInfo++
+		case "element":
+			if elem >= 0 && prop != len(p.Elements[elem].Properties) {
+				return nil, false
+			}
+			elem++
+			prop = 0
+			if elem >= len(p.Elements) || len(words) != 3 || words[1] != p.Elements[elem].Name {
+				return nil, false
+			}
+			at := strings.LastIndex(line, words[2])
+			lines[n] = line[:at] + itoa(p.Elements[elem].Size) + line[at+len(words[2]):]
+		case "property":
+			if elem < 0 || prop >= len(p.Elements[elem].Properties) {
+				return nil, false
+			}
+			pr := p.Elements[elem].Properties[prop]
+			decl := []string{"property", pr.Type, pr.Name}
+			if pr.IsList {
+				decl = []string{"property", "list", pr.ListSizeType, pr.Type, pr.Name}
+			}
+			if SizeOfType[pr.Type] == 0 || strings.Join(words, " ") != strings.Join(decl, " ") {
+				return nil, false
+			}
+			prop++
+		}
+	}
+	if elem != len(p.Elements)-1 || elem >= 0 && prop != len(p.Elements[elem].Properties) ||
+		comments != len(p.Comments) || objInfo != len(p.ObjInfoItems) {
+		return nil, false
+	}
+	return lines, true
+}
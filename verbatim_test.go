@@ -0,0 +1,61 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVerbatimHeader(t *testing.T) {
+	header := "ply\r\n" +
+		"format binary_little_endian 1.0\r\n" +
+		"comment   exported by  scanner v2\r\n" +
+		"obj_info num_cols 2\r\n" +
+		"\r\n" +
+		"comment second\r\n" +
+		"element  vertex\t2\r\n" +
+		"property float32 x\r\n" +
+		"property  uchar   intensity\r\n" +
+		"end_header\r\n"
+	body := []byte{0, 0, 128, 63, 7, 0, 0, 0, 64, 9}
+	src := append([]byte(header), body...)
+
+	p := new(PLY)
+	if e := p.ReadWithOptions(bytes.NewReader(src), &LoadOptions{KeepHeader: true}); e != nil {
+		t.Fatal(e)
+	}
+	var out bytes.Buffer
+	if e := p.WriteWithOptions(&out, &SaveOptions{VerbatimHeader: true}); e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(out.Bytes(), src) {
+		t.Errorf("expected a byte-identical copy, got %q", out.String())
+	}
+
+	vertex := p.findElement("vertex")
+	if e := vertex.AppendRow(map[string]interface{}{"x": 3, "intensity": 1}); e != nil {
+		t.Fatal(e)
+	}
+	out.Reset()
+	p.WriteWithOptions(&out, &SaveOptions{VerbatimHeader: true})
+	if !strings.HasPrefix(out.String(), strings.Replace(header, "vertex\t2", "vertex\t3", 1)) {
+		t.Errorf("expected only the count to change, got %q", out.String())
+	}
+
+	p.Comments = append(p.Comments, "edited")
+	out.Reset()
+	p.WriteWithOptions(&out, &SaveOptions{VerbatimHeader: true})
+	if !strings.Contains(out.String(), "comment edited\n") || strings.Contains(out.String(), "\r") {
+		t.Errorf("expected a generated header after editing comments, got %q", out.String())
+	}
+
+	q := new(PLY)
+	if e := q.Read(bytes.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	out.Reset()
+	q.WriteWithOptions(&out, &SaveOptions{VerbatimHeader: true})
+	if strings.Contains(out.String(), "\r") {
+		t.Error("expected a generated header without KeepHeader")
+	}
+}
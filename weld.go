@@ -0,0 +1,110 @@
+package ply
+
+import (
+	"errors"
+	"math"
+)
+
+// WeldVertices merges vertices lying within tolerance of an earlier kept
+// vertex, which keeps its other properties. Face indices are remapped and
+// repeated corners collapsed; faces left with fewer than three corners are
+// removed. It returns the number of vertices removed.
+func (p *PLY) WeldVertices(tolerance float64) (int, error) {
+	if p.frozen {
+		return 0, ErrFrozen
+	}
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return 0, errors.New("No vertex element")
+	}
+	pos, e := p.vertexPositions()
+	if e != nil {
+		return 0, e
+	}
+	remap, rows := weldPositions(pos, tolerance)
+	removed := vertex.Size - len(rows)
+	if removed == 0 {
+		return 0, nil
+	}
+	sub := vertex.selectRows(rows)
+	vertex.Properties = sub.Properties
+	vertex.Size = sub.Size
+	face := p.findElement("face")
+	if face == nil {
+		return removed, nil
+	}
+	idx := p.faceIndexProperty(face)
+	if idx == nil {
+		return removed, nil
+	}
+	var faceRows []int
+	faceData := newListColumn(idx)
+	for i := 0; i < face.Size; i++ {
+		var corners []float64
+		for _, v := range idx.listIntsAt(i) {
+			if v >= 0 && v < len(remap) {
+				v = remap[v]
+			}
+			if n := len(corners); n == 0 || corners[n-1] != float64(v) {
+				corners = append(corners, float64(v))
+			}
+		}
+		for len(corners) > 1 && corners[0] == corners[len(corners)-1] {
+			corners = corners[:len(corners)-1]
+		}
+		if len(corners) >= 3 {
+			faceRows = append(faceRows, i)
+			faceData.add(corners)
+		}
+	}
+	sub = face.selectRows(faceRows)
+	for _, prop := range sub.Properties {
+		if prop.Name == idx.Name {
+			prop.Data = faceData.rows()
+		}
+	}
+	face.Properties = sub.Properties
+	face.Size = sub.Size
+	return removed, nil
+}
+
+// weldPositions maps every position to the index of its kept
+// representative and returns the kept rows. Candidates are found by
+// hashing positions into cells of the tolerance's size.
+func weldPositions(pos [][3]float64, tolerance float64) ([]int, []int) {
+	remap := make([]int, len(pos))
+	var rows []int
+	cell := func(v [3]float64) [3]int64 {
+		if tolerance <= 0 {
+			return [3]int64{int64(math.Float64bits(v[0])), int64(math.Float64bits(v[1])), int64(math.Float64bits(v[2]))}
+		}
+		return [3]int64{int64(math.Floor(v[0] / tolerance)), int64(math.Floor(v[1] / tolerance)), int64(math.Floor(v[2] / tolerance))}
+	}
+	grid := make(map[[3]int64][]int)
+	for i, v := range pos {
+		c := cell(v)
+		remap[i] = -1
+		reach := int64(1)
+		if tolerance <= 0 {
+			reach = 0
+		}
+		for dx := -reach; dx <= reach && remap[i] < 0; dx++ {
+			for dy := -reach; dy <= reach && remap[i] < 0; dy++ {
+				for dz := -reach; dz <= reach && remap[i] < 0; dz++ {
+					for _, k := range grid[[3]int64{c[0] + dx, c[1] + dy, c[2] + dz}] {
+						if length3(sub3(pos[rows[k]], v)) <= tolerance {
+							remap[i] = k
+							break
+						}
+					}
+				}
+			}
+		}
+		if remap[i] < 0 {
+			remap[i] = len(rows)
+			grid[c] = append(grid[c], len(rows))
+			rows = append(rows, i)
+		}
+	}
+	return remap, rows
+}
@@ -0,0 +1,51 @@
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWeldVertices(t *testing.T) {
+	// two triangles sharing an edge whose vertices were written twice
+	src := `ply
+format ascii 1.0
+element vertex 6
+property float x
+property float y
+property float z
+property uchar red
+element face 3
+property list uchar int vertex_indices
+end_header
+0 0 0 1
+1 0 0 2
+0 1 0 3
+1.001 0 0 4
+0 1.001 0 5
+1 1 0 6
+3 0 1 2
+3 3 5 4
+3 1 3 4
+`
+	p := new(PLY)
+	if e := p.Read(strings.NewReader(src)); e != nil {
+		t.Fatal(e)
+	}
+	removed, e := p.WeldVertices(0.01)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if removed != 2 || p.VerticesCount() != 4 {
+		t.Fatalf("expected 2 vertices removed, got %d leaving %d", removed, p.VerticesCount())
+	}
+	if red := p.Elements[0].findProperty("red"); red.float64At(3) != 6 {
+		t.Error("expected kept vertices to keep their properties")
+	}
+	faces := p.ReadFaces()
+	if len(faces) != 2 || faces[1][0] != 1 || faces[1][1] != 3 || faces[1][2] != 2 {
+		t.Errorf("unexpected faces %v", faces)
+	}
+	if removed, _ := p.WeldVertices(0); removed != 0 {
+		t.Errorf("expected no exact duplicates, removed %d", removed)
+	}
+}
@@ -0,0 +1,377 @@
+package ply
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type SaveOptions struct {
+	// Gzip compresses the output. Save also compresses when the filename
+	// ends in ".gz".
+	Gzip bool
+	// Rows restricts the written rows per element name, see SaveRows.
+	Rows map[string]RowRange
+	// ChunkRows, when positive, writes the bounds of every ChunkRows
+	// vertices as header comments, see PLY.ChunkBounds.
+	ChunkRows int
+	// Values converts property types and replaces NaN and ±Inf in float
+	// properties, see ValuePolicy.
+	Values *ValuePolicy
+	// Precision snaps coordinates to a fixed step, see PrecisionPolicy.
+	Precision *PrecisionPolicy
+	// Canonical writes the elements and vertex properties in canonical
+	// order without changing p, see PLY.Canonicalize.
+	Canonical bool
+	// ASCII controls the formatting of ASCII output.
+	ASCII *ASCIIOptions
+	// Checksum adds a header comment holding a checksum of the body,
+	// replacing any earlier one, see LoadOptions.VerifyChecksum. Checksum
+	// comments are dropped otherwise, as they would no longer match.
+	Checksum int
+	// VerbatimHeader writes the header lines p was loaded with, see
+	// LoadOptions.KeepHeader, updating only the element counts, so that
+	// saving an unmodified binary file reproduces it byte for byte. The
+	// header is generated as usual when p no longer matches it.
+	VerbatimHeader bool
+}
+
+func (p *PLY) Save(filename string) error {
+	return p.SaveWithOptions(filename, nil)
+}
+
+func (p *PLY) SaveWithOptions(filename string, opts *SaveOptions) error {
+	if opts == nil {
+		opts = &SaveOptions{}
+	}
+	if p.closed {
+		return ErrClosed
+	}
+	file, e := os.Create(filename)
+	if e != nil {
+		return e
+	}
+	o := *opts
+	if strings.HasSuffix(strings.ToLower(filename), ".gz") {
+		o.Gzip = true
+	}
+	e = p.WriteWithOptions(file, &o)
+	if ce := file.Close(); e == nil {
+		e = ce
+	}
+	return e
+}
+
+func (p *PLY) Write(w io.Writer) error {
+	return p.WriteWithOptions(w, nil)
+}
+
+func (p *PLY) WriteWithOptions(w io.Writer, opts *SaveOptions) error {
+	if opts == nil {
+		opts = &SaveOptions{}
+	}
+	if p.closed {
+		return ErrClosed
+	}
+	if len(opts.Rows) > 0 {
+		sub, e := p.rowSubset(opts.Rows)
+		if e != nil {
+			return e
+		}
+		p = sub
+	}
+	if opts.Values != nil {
+		q, e := p.sanitize(opts.Values)
+		if e != nil {
+			return e
+		}
+		p = q
+	}
+	if opts.Precision != nil {
+		q, e := p.quantize(opts.Precision)
+		if e != nil {
+			return e
+		}
+		p = q
+	}
+	if opts.Canonical {
+		p = p.canonical()
+	}
+	if opts.ChunkRows > 0 {
+		q, e := p.withChunkComments(opts.ChunkRows)
+		if e != nil {
+			return e
+		}
+		p = q
+	}
+	p = p.withoutChecksum()
+	if opts.Checksum != NoChecksum {
+		q, e := p.withChecksum(opts.Checksum, opts.ASCII)
+		if e != nil {
+			return e
+		}
+		p = q
+	}
+	var gz *gzip.Writer
+	if opts.Gzip {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	bw := bufio.NewWriter(w)
+	var lines []string
+	verbatim := false
+	if opts.VerbatimHeader {
+		lines, verbatim = p.verbatimHeader()
+	}
+	var e error
+	if verbatim {
+		for _, line := range lines {
+			bw.WriteString(line)
+		}
+	} else {
+		e = writeHeader(p, bw)
+	}
+	if e == nil {
+		e = writeBody(p, bw, opts.ASCII)
+	}
+	if e == nil {
+		e = bw.Flush()
+	}
+	if gz != nil {
+		if ce := gz.Close(); e == nil {
+			e = ce
+		}
+	}
+	return e
+}
+
+func formatName(fileType int8) (string, error) {
+	switch fileType {
+	case Ascii:
+		return "ascii", nil
+	case BinaryBigEndian:
+		return "binary_big_endian", nil
+	case BinaryLittleEndian:
+		return "binary_little_endian", nil
+	}
+	return "", errors.New("File type error")
+}
+
+func writeHeader(p *PLY, w *bufio.Writer) error {
+	format, e := formatName(p.FileType)
+	if e != nil {
+		return e
+	}
+	w.WriteString("ply\nformat " + format + " 1.0\n")
+	for _, c := range p.Comments {
+		w.WriteString("comment " + c + "\n")
+	}
+	keys := make([]string, 0, len(p.ObjInfoItems))
+	for k := range p.ObjInfoItems {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		w.WriteString("obj_info " + k + " " + p.ObjInfoItems[k] + "\n")
+	}
+	for _, elem := range p.Elements {
+		w.WriteString("element " + elem.Name + " " + itoa(elem.Size) + "\n")
+		for _, prop := range elem.Properties {
+			if SizeOfType[prop.Type] == 0 {
+				return errors.New("Unknown type " + prop.Type + " of property " + prop.Name)
+			}
+			if prop.IsList {
+				w.WriteString("property list " + prop.ListSizeType + " " + prop.Type + " " + prop.Name + "\n")
+			} else {
+				w.WriteString("property " + prop.Type + " " + prop.Name + "\n")
+			}
+		}
+	}
+	_, e = w.WriteString("end_header\n")
+	return e
+}
+
+func writeBody(p *PLY, w *bufio.Writer, ascii *ASCIIOptions) error {
+	var out binary.ByteOrder = binary.LittleEndian
+	if p.FileType == BinaryBigEndian {
+		out = binary.BigEndian
+	}
+	for _, elem := range p.Elements {
+		for i := 0; i < elem.Size; i++ {
+			var e error
+			if p.FileType == Ascii {
+				e = writeASCIIRow(elem, i, w, ascii)
+			} else {
+				e = writeBinaryRow(elem, i, out, w)
+			}
+			if e != nil {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+func rowData(elem *Element, prop *Property, i int) ([]byte, error) {
+	data := prop.row(i)
+	if data == nil && !prop.IsList {
+		return nil, errors.New("Missing data for property " + prop.Name +
+			" of element " + elem.Name + " at row " + itoa(i))
+	}
+	return data, nil
+}
+
+func listCount(prop *Property, data []byte) (int, error) {
+	size := SizeOfType[prop.Type]
+	if size == 0 || len(data)%size != 0 {
+		return 0, errors.New("Malformed list data for property " + prop.Name)
+	}
+	return len(data) / size, nil
+}
+
+func writeASCIIRow(elem *Element, i int, w *bufio.Writer, opts *ASCIIOptions) error {
+	first := true
+	put := func(s, sep string) {
+		if !first {
+			w.WriteString(sep)
+		}
+		first = false
+		w.WriteString(s)
+	}
+	sep := opts.listSeparator()
+	for _, prop := range elem.Properties {
+		data, e := rowData(elem, prop, i)
+		if e != nil {
+			return e
+		}
+		size := SizeOfType[prop.Type]
+		order := prop.byteOrder()
+		if prop.IsList {
+			n, e := listCount(prop, data)
+			if e != nil {
+				return e
+			}
+			put(itoa(n), " ")
+			for j := 0; j < n; j++ {
+				put(opts.format(data[j*size:(j+1)*size], elem, prop, order), sep)
+			}
+		} else {
+			if len(data) != size {
+				return errors.New("Malformed data for property " + prop.Name)
+			}
+			put(opts.format(data, elem, prop, order), " ")
+		}
+	}
+	_, e := w.WriteString("\n")
+	return e
+}
+
+func writeBinaryRow(elem *Element, i int, out binary.ByteOrder, w *bufio.Writer) error {
+	for _, prop := range elem.Properties {
+		data, e := rowData(elem, prop, i)
+		if e != nil {
+			return e
+		}
+		size := SizeOfType[prop.Type]
+		in := prop.byteOrder()
+		if prop.IsList {
+			n, e := listCount(prop, data)
+			if e != nil {
+				return e
+			}
+			cnt, e := encodeUint(uint64(n), prop.ListSizeType, out)
+			if e != nil {
+				return errors.New(e.Error() + " for property " + prop.Name)
+			}
+			w.Write(cnt)
+			for j := 0; j < n; j++ {
+				writeScalar(data[j*size:(j+1)*size], in, out, w)
+			}
+		} else {
+			if len(data) != size {
+				return errors.New("Malformed data for property " + prop.Name)
+			}
+			writeScalar(data, in, out, w)
+		}
+	}
+	return nil
+}
+
+func writeScalar(b []byte, in, out binary.ByteOrder, w *bufio.Writer) {
+	if in == out || len(b) == 1 {
+		w.Write(b)
+		return
+	}
+	for k := len(b) - 1; k >= 0; k-- {
+		w.WriteByte(b[k])
+	}
+}
+
+func encodeUint(n uint64, typeName string, order binary.ByteOrder) ([]byte, error) {
+	size := SizeOfType[typeName]
+	bits := uint(size) * 8
+	if isSigned(typeName) {
+		bits--
+	}
+	if size == 0 || isFloat(typeName) || bits < 64 && n >= 1<<bits {
+		return nil, errors.New("List size " + strconv.FormatUint(n, 10) + " does not fit " + typeName)
+	}
+	b := make([]byte, size)
+	switch size {
+	case 1:
+		b[0] = byte(n)
+	case 2:
+		order.PutUint16(b, uint16(n))
+	case 4:
+		order.PutUint32(b, uint32(n))
+	}
+	return b, nil
+}
+
+func isFloat(typeName string) bool {
+	switch typeName {
+	case "float32", "float64", "float", "double":
+		return true
+	}
+	return codecs[typeName] != nil && codecs[typeName].Float
+}
+
+func isSigned(typeName string) bool {
+	switch typeName {
+	case "int8", "int16", "int32", "char", "short", "int":
+		return true
+	}
+	return codecs[typeName] != nil && codecs[typeName].Signed
+}
+
+func formatValue(b []byte, typeName string, order binary.ByteOrder) string {
+	switch typeName {
+	case "int8", "char":
+		return strconv.FormatInt(int64(int8(b[0])), 10)
+	case "int16", "short":
+		return strconv.FormatInt(int64(int16(order.Uint16(b))), 10)
+	case "int32", "int":
+		return strconv.FormatInt(int64(int32(order.Uint32(b))), 10)
+	case "uint8", "uchar":
+		return strconv.FormatUint(uint64(b[0]), 10)
+	case "uint16", "ushort":
+		return strconv.FormatUint(uint64(order.Uint16(b)), 10)
+	case "uint32", "uint":
+		return strconv.FormatUint(uint64(order.Uint32(b)), 10)
+	case "float32", "float":
+		return strconv.FormatFloat(float64(math.Float32frombits(order.Uint32(b))), 'g', -1, 32)
+	case "float64", "double":
+		return strconv.FormatFloat(math.Float64frombits(order.Uint64(b)), 'g', -1, 64)
+	}
+	if c := codecs[typeName]; c != nil {
+		return c.Format(b, order)
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package ply
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testASCIIVertices = `ply
+format ascii 1.0
+obj_info scanner test
+element vertex 3
+property float x
+property float y
+property float z
+property uchar red
+end_header
+0 0 0 10
+1 0.5 -2 20
+-1.25 3 4 30
+`
+
+func TestWriteRoundTrip(t *testing.T) {
+	src := new(PLY)
+	if e := src.Read(strings.NewReader(testASCIIVertices)); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	if e := src.Write(&buf); e != nil {
+		t.Fatal(e)
+	}
+	if buf.String() != testASCIIVertices {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestGzipRoundTrip(t *testing.T) {
+	src := new(PLY)
+	if e := src.Read(strings.NewReader(testASCIIVertices)); e != nil {
+		t.Fatal(e)
+	}
+	dir, e := ioutil.TempDir("", "ply")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "test.ply.gz")
+	if e := src.Save(name); e != nil {
+		t.Fatal(e)
+	}
+	raw, _ := ioutil.ReadFile(name)
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		t.Fatal("expected gzip output")
+	}
+	dst := new(PLY)
+	if e := dst.Load(name); e != nil {
+		t.Fatal(e)
+	}
+	if dst.VerticesCount() != 3 || dst.ObjInfoItems["scanner"] != "test" {
+		t.Errorf("unexpected result %v", dst)
+	}
+	vs := dst.ReadVertices()
+	if vs[0][2] != -1.25 || vs[1][2] != 3 || vs[2][2] != 4 {
+		t.Errorf("unexpected vertices %v", vs)
+	}
+}
@@ -0,0 +1,202 @@
+package ply
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+type XYZOptions struct {
+	// Delimiter separates columns. When zero, whitespace, commas and
+	// semicolons are accepted on import and a space is written on export.
+	Delimiter rune
+	// Columns names the vertex property of each column; "" or "_" skips a
+	// column on import. On import the default is taken from a header row,
+	// or from the column count (x y z, x y z intensity, x y z red green
+	// blue, x y z intensity red green blue). On export it selects and
+	// orders the written properties; all scalar properties by default.
+	Columns []string
+	// Types maps property names to PLY types on import. red, green, blue
+	// and alpha default to uchar, everything else to float.
+	Types map[string]string
+	// Header writes a row of property names on export. Header rows are
+	// detected automatically on import.
+	Header bool
+}
+
+// FromXYZ reads a delimited point file into a vertex-only PLY. Empty lines
+// and lines starting with # or // are skipped.
+func FromXYZ(r io.Reader, opts *XYZOptions) (*PLY, error) {
+	if opts == nil {
+		opts = &XYZOptions{}
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	columns := opts.Columns
+	var rows [][]string
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		fields := splitXYZ(line, opts.Delimiter)
+		if len(rows) == 0 && columns == nil && !allNumbers(fields) {
+			columns = fields
+			continue
+		}
+		if len(rows) == 0 && opts.Columns != nil && !allNumbers(fields) {
+			// header row with explicit mapping
+			continue
+		}
+		if len(rows) > 0 && len(fields) != len(rows[0]) {
+			return nil, errors.New("Expected " + itoa(len(rows[0])) + " columns at line " + itoa(lineNo))
+		}
+		rows = append(rows, fields)
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, e
+	}
+	if columns == nil {
+		n := 0
+		if len(rows) > 0 {
+			n = len(rows[0])
+		}
+		columns = defaultXYZColumns(n)
+	}
+	if len(rows) > 0 && len(columns) != len(rows[0]) {
+		return nil, errors.New("Got " + itoa(len(columns)) + " column names for " + itoa(len(rows[0])) + " columns")
+	}
+	vertex := &Element{Name: "vertex", Size: len(rows)}
+	props := make([]*Property, len(columns))
+	for j, name := range columns {
+		if name == "" || name == "_" {
+			continue
+		}
+		if vertex.findProperty(name) != nil {
+			return nil, errors.New("Duplicate column " + name)
+		}
+		typeName := opts.Types[name]
+		if typeName == "" {
+			typeName = "float"
+			switch name {
+			case "red", "green", "blue", "alpha":
+				typeName = "uchar"
+			}
+		}
+		if SizeOfType[typeName] == 0 {
+			return nil, errors.New("Unknown type " + typeName + " for column " + name)
+		}
+		props[j] = newProperty(name, typeName, len(rows))
+		props[j].pos = len(vertex.Properties)
+		vertex.Properties = append(vertex.Properties, props[j])
+	}
+	for i, fields := range rows {
+		for j, f := range fields {
+			if props[j] == nil {
+				continue
+			}
+			b, e := toType(f, props[j].Type)
+			if e != nil {
+				return nil, errors.New("Invalid value " + f + " for " + columns[j] + " in row " + itoa(i))
+			}
+			props[j].Data[i] = b
+		}
+	}
+	return &PLY{FileType: BinaryLittleEndian, byteOrder: binary.LittleEndian, Elements: []*Element{vertex}}, nil
+}
+
+// ToXYZ writes vertex properties as delimited columns, one row per vertex.
+func (p *PLY) ToXYZ(w io.Writer, opts *XYZOptions) error {
+	if opts == nil {
+		opts = &XYZOptions{}
+	}
+	vertex := p.findElement("vertex")
+	if vertex == nil {
+		return errors.New("No vertex element")
+	}
+	var props []*Property
+	if opts.Columns != nil {
+		for _, name := range opts.Columns {
+			prop := vertex.findProperty(name)
+			if prop == nil || prop.IsList {
+				return errors.New("Vertex element has no scalar property " + name)
+			}
+			props = append(props, prop)
+		}
+	} else {
+		for _, prop := range vertex.Properties {
+			if !prop.IsList {
+				props = append(props, prop)
+			}
+		}
+	}
+	sep := " "
+	if opts.Delimiter != 0 {
+		sep = string(opts.Delimiter)
+	}
+	bw := bufio.NewWriter(w)
+	if opts.Header {
+		names := make([]string, len(props))
+		for j, prop := range props {
+			names[j] = prop.Name
+		}
+		bw.WriteString(strings.Join(names, sep) + "\n")
+	}
+	for i := 0; i < vertex.Size; i++ {
+		for j, prop := range props {
+			if j > 0 {
+				bw.WriteString(sep)
+			}
+			b := prop.scalarRow(i)
+			if b == nil {
+				return errors.New("Missing data for property " + prop.Name)
+			}
+			bw.WriteString(formatValue(b, prop.Type, prop.byteOrder()))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func splitXYZ(line string, delim rune) []string {
+	if delim == 0 {
+		return strings.FieldsFunc(line, func(c rune) bool {
+			return unicode.IsSpace(c) || c == ',' || c == ';'
+		})
+	}
+	fields := strings.Split(line, string(delim))
+	for k := range fields {
+		fields[k] = strings.TrimSpace(fields[k])
+	}
+	return fields
+}
+
+func allNumbers(fields []string) bool {
+	for _, f := range fields {
+		if !isNumber(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func defaultXYZColumns(n int) []string {
+	switch n {
+	case 4:
+		return []string{"x", "y", "z", "intensity"}
+	case 6:
+		return []string{"x", "y", "z", "red", "green", "blue"}
+	case 7:
+		return []string{"x", "y", "z", "intensity", "red", "green", "blue"}
+	}
+	columns := []string{"x", "y", "z"}
+	for j := 3; j < n; j++ {
+		columns = append(columns, "field_"+itoa(j))
+	}
+	return columns[:n]
+}
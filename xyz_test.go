@@ -0,0 +1,36 @@
+package ply
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestXYZ(t *testing.T) {
+	p, e := FromXYZ(strings.NewReader("# scan\n1 2 3 255 0 10\n\n4,5,6,0,128,255\n"), nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	v := p.GetVertices()
+	if v.Size != 2 || v.findProperty("green").Type != "uchar" || v.findProperty("green").float64At(1) != 128 {
+		t.Errorf("unexpected vertices %+v", v)
+	}
+	var buf bytes.Buffer
+	if e := p.ToXYZ(&buf, &XYZOptions{Delimiter: ',', Header: true, Columns: []string{"z", "x"}}); e != nil {
+		t.Fatal(e)
+	}
+	if buf.String() != "z,x\n3,1\n6,4\n" {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+	q, e := FromXYZ(&buf, &XYZOptions{Delimiter: ',', Types: map[string]string{"x": "double"}})
+	if e != nil {
+		t.Fatal(e)
+	}
+	qv := q.GetVertices()
+	if qv.Properties[0].Name != "z" || qv.findProperty("x").Type != "double" || qv.findProperty("x").float64At(1) != 4 {
+		t.Errorf("unexpected header import %+v", qv)
+	}
+	if _, e := FromXYZ(strings.NewReader("1 2 3\n1 2\n"), nil); e == nil {
+		t.Error("expected ragged rows to fail")
+	}
+}